@@ -0,0 +1,167 @@
+-- 任务执行记录
+CREATE TABLE IF NOT EXISTS `task_execution` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `task_id` BIGINT UNSIGNED NOT NULL COMMENT '任务ID',
+  `admin_id` BIGINT NOT NULL COMMENT '任务创建者ID',
+  `run_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT 'asynq任务ID',
+  `source` VARCHAR(32) NOT NULL DEFAULT '' COMMENT '触发来源（payload msg_type）',
+  `attempt` INT NOT NULL DEFAULT 0 COMMENT 'asynq重试次数',
+  `status` INT NOT NULL DEFAULT 0 COMMENT '状态：0-执行中，1-成功，2-失败，3-部分失败',
+  `total_count` INT NOT NULL DEFAULT 0 COMMENT '投递总数',
+  `success_count` INT NOT NULL DEFAULT 0 COMMENT '成功数',
+  `failed_count` INT NOT NULL DEFAULT 0 COMMENT '失败数',
+  `error_message` TEXT COMMENT '错误信息',
+  `started_at` DATETIME NOT NULL COMMENT '开始时间',
+  `finished_at` DATETIME DEFAULT NULL COMMENT '结束时间',
+  `duration_ms` BIGINT NOT NULL DEFAULT 0 COMMENT '耗时（毫秒）',
+  `create_time` DATETIME NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_task_id` (`task_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='任务执行记录';
+
+-- 任务投递明细（每次执行中每个群组/消息一条）
+CREATE TABLE IF NOT EXISTS `task_delivery` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `execution_id` BIGINT UNSIGNED NOT NULL COMMENT '执行记录ID',
+  `task_id` BIGINT UNSIGNED NOT NULL COMMENT '任务ID',
+  `group_id` BIGINT NOT NULL COMMENT '群组ID',
+  `message_id` BIGINT UNSIGNED NOT NULL COMMENT '消息ID',
+  `status` INT NOT NULL COMMENT '状态：1-成功，2-失败',
+  `tg_message_id` BIGINT NOT NULL DEFAULT 0 COMMENT 'Telegram首条消息ID',
+  `tg_message_ids` JSON DEFAULT NULL COMMENT 'Telegram消息ID列表（媒体组会产生多条）',
+  `latency_ms` BIGINT NOT NULL DEFAULT 0 COMMENT '发送耗时（毫秒）',
+  `error_message` TEXT COMMENT '错误信息',
+  `create_time` DATETIME NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_execution_id` (`execution_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='任务投递明细';
+
+-- 任务时区（IANA名称）
+ALTER TABLE `task`
+  ADD COLUMN `timezone` VARCHAR(64) NOT NULL DEFAULT 'Asia/Shanghai' COMMENT '任务时区（IANA名称），用于Cron计算与时间回显；时间字段统一以UTC存储' AFTER `expire_time`;
+
+-- 时间字段改为UTC存储：历史数据需一次性转换，见 config/migrate_utc.sql（带执行标记，重复执行不会再次转换）
+
+-- 错过执行策略
+ALTER TABLE `task`
+  ADD COLUMN `misfire_policy` VARCHAR(16) NOT NULL DEFAULT 'fire_once' COMMENT '错过执行策略：skip-跳过，fire_once-补执行一次，catch_up-按次补执行' AFTER `max_retry_count`,
+  ADD COLUMN `misfire_catch_up_limit` INT NOT NULL DEFAULT 5 COMMENT 'catch_up策略下的补执行次数上限' AFTER `misfire_policy`;
+ALTER TABLE `task_execution`
+  MODIFY COLUMN `status` INT NOT NULL DEFAULT 0 COMMENT '状态：0-执行中，1-成功，2-失败，3-部分失败，4-错过执行';
+
+-- 任务优先级（映射到asynq队列）
+ALTER TABLE `task`
+  ADD COLUMN `priority` VARCHAR(16) NOT NULL DEFAULT 'normal' COMMENT '优先级：high-高，normal-普通，low-低，对应不同的asynq队列' AFTER `misfire_catch_up_limit`;
+
+-- Telegram限流延后投递
+ALTER TABLE `task_execution`
+  ADD COLUMN `deferred_count` INT NOT NULL DEFAULT 0 COMMENT '因限流延后投递数' AFTER `failed_count`;
+ALTER TABLE `task_delivery`
+  MODIFY COLUMN `status` INT NOT NULL COMMENT '状态：1-成功，2-失败，3-限流延后';
+
+-- 消息轮换
+ALTER TABLE `task`
+  ADD COLUMN `rotation_mode` VARCHAR(16) NOT NULL DEFAULT 'all' COMMENT '消息轮换模式：all-全部发送，round_robin-顺序轮换，random-随机（不连续重复），weighted-按权重随机' AFTER `priority`,
+  ADD COLUMN `rotation_weights` JSON DEFAULT NULL COMMENT 'weighted模式下各消息的权重，JSON格式存储：{消息ID: 权重}' AFTER `rotation_mode`;
+
+-- 群组发送时段与静默期
+ALTER TABLE `bot_config`
+  ADD COLUMN `send_window` JSON DEFAULT NULL COMMENT '发送时段与静默期：{timezone, windows, blackouts, policy}';
+ALTER TABLE `task_execution`
+  ADD COLUMN `skipped_count` INT NOT NULL DEFAULT 0 COMMENT '因静默期跳过投递数' AFTER `deferred_count`;
+ALTER TABLE `task_delivery`
+  MODIFY COLUMN `status` INT NOT NULL COMMENT '状态：1-成功，2-失败，3-延后，4-静默期跳过';
+
+-- 任务重试退避策略（与asynq重试间隔一致）
+ALTER TABLE `task`
+  ADD COLUMN `retry_policy` VARCHAR(16) NOT NULL DEFAULT 'exponential' COMMENT '重试退避策略：fixed-固定，linear-线性，exponential-指数' AFTER `max_retry_count`,
+  ADD COLUMN `retry_base_seconds` INT NOT NULL DEFAULT 60 COMMENT '重试退避基准时长（秒）' AFTER `retry_policy`,
+  ADD COLUMN `retry_max_seconds` INT NOT NULL DEFAULT 1920 COMMENT '重试退避上限（秒）' AFTER `retry_base_seconds`;
+
+-- 管理员告警配置（通过自己的机器人私聊接收告警）
+CREATE TABLE IF NOT EXISTS `admin_alert_config` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `admin_id` BIGINT NOT NULL COMMENT '管理员ID',
+  `bot_config_id` BIGINT UNSIGNED NOT NULL COMMENT '用于发送告警的机器人配置ID',
+  `chat_id` BIGINT NOT NULL COMMENT '接收告警的私聊ID（需先与机器人私聊）',
+  `enabled` TINYINT(1) NOT NULL DEFAULT 1 COMMENT '是否启用',
+  `on_failure` TINYINT(1) NOT NULL DEFAULT 1 COMMENT '任务执行失败时告警',
+  `consecutive_failures` INT NOT NULL DEFAULT 0 COMMENT '连续失败N次时告警，0-不启用',
+  `on_expire` TINYINT(1) NOT NULL DEFAULT 0 COMMENT '周期任务到期时告警',
+  `daily_digest` TINYINT(1) NOT NULL DEFAULT 0 COMMENT '是否发送每日汇总',
+  `digest_hour` INT NOT NULL DEFAULT 9 COMMENT '每日汇总发送时间（0-23点，服务时区）',
+  `create_time` DATETIME NOT NULL COMMENT '创建时间',
+  `update_time` DATETIME NOT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_admin_id` (`admin_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='管理员告警配置';
+
+-- 管理员告警记录
+CREATE TABLE IF NOT EXISTS `admin_alert` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `admin_id` BIGINT NOT NULL COMMENT '管理员ID',
+  `task_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '关联任务ID，汇总/测试告警为0',
+  `kind` VARCHAR(32) NOT NULL COMMENT '告警类型：failure/consecutive/expired/digest/test',
+  `content` TEXT COMMENT '告警内容',
+  `status` INT NOT NULL DEFAULT 0 COMMENT '状态：0-待发送，1-已发送，2-发送失败，3-超过频率上限',
+  `error_message` TEXT COMMENT '发送失败原因',
+  `sent_at` DATETIME DEFAULT NULL COMMENT '发送时间',
+  `create_time` DATETIME NOT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_admin_id` (`admin_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='管理员告警记录';
+
+-- webhook订阅（任务生命周期事件）
+CREATE TABLE IF NOT EXISTS `webhook` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `admin_id` BIGINT NOT NULL COMMENT '管理员ID',
+  `name` VARCHAR(64) NOT NULL COMMENT '名称',
+  `url` VARCHAR(512) NOT NULL COMMENT '回调地址',
+  `secret` VARCHAR(128) NOT NULL COMMENT 'HMAC-SHA256签名密钥',
+  `events` JSON DEFAULT NULL COMMENT '订阅的事件列表，JSON格式存储，为空表示全部事件',
+  `enabled` TINYINT(1) NOT NULL DEFAULT 1 COMMENT '是否启用',
+  `is_delete` INT NOT NULL DEFAULT 0 COMMENT '是否删除 0:正常 1:删除',
+  `create_time` DATETIME NOT NULL COMMENT '创建时间',
+  `update_time` DATETIME NOT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_admin_id` (`admin_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='webhook订阅';
+
+-- webhook投递记录
+CREATE TABLE IF NOT EXISTS `webhook_delivery` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `webhook_id` BIGINT UNSIGNED NOT NULL COMMENT 'webhook ID',
+  `admin_id` BIGINT NOT NULL COMMENT '管理员ID',
+  `task_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '关联任务ID',
+  `event` VARCHAR(32) NOT NULL COMMENT '事件类型',
+  `event_id` VARCHAR(64) NOT NULL COMMENT '事件ID，重放时保持不变，供接收方去重',
+  `payload` MEDIUMTEXT COMMENT '请求体',
+  `status` INT NOT NULL DEFAULT 0 COMMENT '状态：0-待投递，1-成功，2-失败',
+  `attempts` INT NOT NULL DEFAULT 0 COMMENT '已尝试次数',
+  `response_code` INT NOT NULL DEFAULT 0 COMMENT '最近一次响应状态码',
+  `response_body` TEXT COMMENT '最近一次响应内容（截断）',
+  `error_message` TEXT COMMENT '最近一次错误信息',
+  `duration_ms` BIGINT NOT NULL DEFAULT 0 COMMENT '最近一次耗时（毫秒）',
+  `replay_of` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '重放的原投递记录ID，0-非重放',
+  `delivered_at` DATETIME DEFAULT NULL COMMENT '投递成功时间',
+  `create_time` DATETIME NOT NULL COMMENT '创建时间',
+  `update_time` DATETIME NOT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_webhook_id` (`webhook_id`),
+  KEY `idx_admin_id` (`admin_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='webhook投递记录';
+
+-- 任务模板
+CREATE TABLE IF NOT EXISTS `task_template` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `admin_id` BIGINT NOT NULL COMMENT '创建者ID',
+  `name` VARCHAR(64) NOT NULL COMMENT '模板名称，同一管理员下唯一',
+  `description` TEXT COMMENT '模板描述',
+  `config` JSON NOT NULL COMMENT '任务配置快照（与创建任务请求结构一致），JSON格式存储',
+  `source_task_id` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '由任务保存时的来源任务ID，0-手动创建',
+  `is_delete` INT NOT NULL DEFAULT 0 COMMENT '是否删除 0:正常 1:删除',
+  `create_time` DATETIME NOT NULL COMMENT '创建时间',
+  `update_time` DATETIME NOT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_admin_id` (`admin_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='任务模板';
@@ -0,0 +1,62 @@
+-- 时间字段改为UTC存储的一次性数据迁移
+-- 1. 必须在新版本服务启动前执行（新版本按UTC读写，未迁移的数据会被当作UTC）
+-- 2. @src_tz 设为旧版本写入数据时使用的时区：固定偏移（如 '+08:00'）或时区名称（如 'Asia/Shanghai'，需已导入MySQL时区表）
+-- 3. 执行成功后在 schema_migration 中记录 utc_storage，重复执行直接跳过；转换在事务内完成，失败整体回滚
+SET @src_tz = '+08:00';
+
+CREATE TABLE IF NOT EXISTS `schema_migration` (
+  `name` VARCHAR(64) NOT NULL COMMENT '迁移名称',
+  `applied_at` DATETIME NOT NULL COMMENT '执行时间（UTC）',
+  PRIMARY KEY (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='一次性数据迁移记录';
+
+DROP PROCEDURE IF EXISTS `migrate_utc_storage`;
+DELIMITER $$
+CREATE PROCEDURE `migrate_utc_storage`(IN src_tz VARCHAR(64))
+BEGIN
+  DECLARE EXIT HANDLER FOR SQLEXCEPTION
+  BEGIN
+    ROLLBACK;
+    RESIGNAL;
+  END;
+
+  IF EXISTS (SELECT 1 FROM `schema_migration` WHERE `name` = 'utc_storage') THEN
+    SELECT 'utc_storage 已执行，跳过' AS result;
+  ELSEIF CONVERT_TZ('2000-01-01 00:00:00', src_tz, '+00:00') IS NULL THEN
+    -- 时区名称无法识别时 CONVERT_TZ 返回 NULL，继续执行会把所有时间清空
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = '无法识别的源时区，请检查 @src_tz 或导入MySQL时区表';
+  ELSE
+    START TRANSACTION;
+    UPDATE `task` SET
+      `schedule_time`    = CONVERT_TZ(`schedule_time`, src_tz, '+00:00'),
+      `expire_time`      = CONVERT_TZ(`expire_time`, src_tz, '+00:00'),
+      `last_executed_at` = CONVERT_TZ(`last_executed_at`, src_tz, '+00:00'),
+      `next_execute_at`  = CONVERT_TZ(`next_execute_at`, src_tz, '+00:00'),
+      `create_time`      = CONVERT_TZ(`create_time`, src_tz, '+00:00'),
+      `update_time`      = CONVERT_TZ(`update_time`, src_tz, '+00:00');
+    UPDATE `task_execution` SET
+      `started_at`  = CONVERT_TZ(`started_at`, src_tz, '+00:00'),
+      `finished_at` = CONVERT_TZ(`finished_at`, src_tz, '+00:00'),
+      `create_time` = CONVERT_TZ(`create_time`, src_tz, '+00:00');
+    UPDATE `task_delivery` SET `create_time` = CONVERT_TZ(`create_time`, src_tz, '+00:00');
+    UPDATE `message` SET
+      `create_time` = CONVERT_TZ(`create_time`, src_tz, '+00:00'),
+      `update_time` = CONVERT_TZ(`update_time`, src_tz, '+00:00');
+    UPDATE `admin_group` SET
+      `create_time` = CONVERT_TZ(`create_time`, src_tz, '+00:00'),
+      `update_time` = CONVERT_TZ(`update_time`, src_tz, '+00:00');
+    UPDATE `bot_config` SET
+      `create_time` = CONVERT_TZ(`create_time`, src_tz, '+00:00'),
+      `update_time` = CONVERT_TZ(`update_time`, src_tz, '+00:00');
+    UPDATE `admin` SET
+      `created_at` = CONVERT_TZ(`created_at`, src_tz, '+00:00'),
+      `updated_at` = CONVERT_TZ(`updated_at`, src_tz, '+00:00');
+    INSERT INTO `schema_migration` (`name`, `applied_at`) VALUES ('utc_storage', UTC_TIMESTAMP());
+    COMMIT;
+    SELECT 'utc_storage 执行完成' AS result;
+  END IF;
+END$$
+DELIMITER ;
+
+CALL `migrate_utc_storage`(@src_tz);
+DROP PROCEDURE IF EXISTS `migrate_utc_storage`;
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultQueue asynq 默认队列
+const DefaultQueue = "default"
+
+// 任务优先级
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
+)
+
+// JobConf 异步任务 Worker 配置（[job] 段）
+type JobConf struct {
+	Concurrency     int               // Worker 并发数
+	Queues          map[string]int    // 队列名称 -> 权重
+	StrictPriority  bool              // 严格优先级：高权重队列清空后才处理低权重队列
+	ShutdownTimeout time.Duration     // 停机时等待处理中任务的时长
+	PriorityQueues  map[string]string // 任务优先级 -> 队列名称
+	OperatorAdmins  []uint            // 可访问队列运维接口的管理员ID（队列与调度数据不区分管理员）
+}
+
+// ParseQueueWeights 解析 "critical:6,default:3,low:1" 格式的队列权重；未写权重时为1
+func ParseQueueWeights(s string) map[string]int {
+	queues := make(map[string]int)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, weight := item, 1
+		if i := strings.Index(item, ":"); i >= 0 {
+			name = strings.TrimSpace(item[:i])
+			if w, err := strconv.Atoi(strings.TrimSpace(item[i+1:])); err == nil && w > 0 {
+				weight = w
+			}
+		}
+		if name != "" {
+			queues[name] = weight
+		}
+	}
+	return queues
+}
+
+// ParseAdminIDs 解析 "1,2,3" 格式的管理员ID列表，忽略无效项
+func ParseAdminIDs(s string) []uint {
+	ids := make([]uint, 0)
+	for _, item := range strings.Split(s, ",") {
+		if id, err := strconv.ParseUint(strings.TrimSpace(item), 10, 64); err == nil && id > 0 {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
+
+// QueueFor 任务优先级对应的队列；未配置或队列不存在时使用默认队列
+func (c *JobConf) QueueFor(priority string) string {
+	if priority == "" {
+		priority = PriorityNormal
+	}
+	if q, ok := c.PriorityQueues[priority]; ok {
+		if _, exists := c.Queues[q]; exists {
+			return q
+		}
+	}
+	return DefaultQueue
+}
+
+// QueueNames 已配置的队列名称
+func (c *JobConf) QueueNames() []string {
+	names := make([]string, 0, len(c.Queues))
+	for name := range c.Queues {
+		names = append(names, name)
+	}
+	return names
+}
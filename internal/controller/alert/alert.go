@@ -0,0 +1,91 @@
+package alert
+
+import (
+	"app/internal/controller"
+	"app/internal/request"
+	"app/internal/service"
+	"app/tools/resp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AlertController 管理员告警控制器
+type AlertController struct {
+	controller.BaseController
+	service.AlertService
+}
+
+// NewAlertController 创建告警控制器实例
+func NewAlertController(alertService service.AlertService) *AlertController {
+	return &AlertController{
+		AlertService: alertService,
+	}
+}
+
+// GetConfig 获取告警配置
+func (ac *AlertController) GetConfig(ctx *gin.Context) {
+	adminID := uint(ac.CurrentUserId(ctx))
+
+	cfg, err := ac.AlertService.GetConfig(adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "获取告警配置失败: " + err.Error()}).Response()
+		return
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取告警配置成功", Data: cfg}).Response()
+}
+
+// SaveConfig 保存告警配置
+func (ac *AlertController) SaveConfig(ctx *gin.Context) {
+	var req request.AlertConfigSaveRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(ac.CurrentUserId(ctx))
+
+	cfg, err := ac.AlertService.SaveConfig(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "保存告警配置失败: " + err.Error()}).Response()
+		return
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "保存告警配置成功", Data: cfg}).Response()
+}
+
+// List 告警记录
+func (ac *AlertController) List(ctx *gin.Context) {
+	var req request.AlertListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(ac.CurrentUserId(ctx))
+
+	result, err := ac.AlertService.ListAlerts(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "查询告警记录失败: " + err.Error()}).Response()
+		return
+	}
+
+	data := map[string]interface{}{
+		"list":      result.List,
+		"total":     result.Total,
+		"page":      req.Page,
+		"pageSize":  req.Limit,
+		"pageCount": (result.Total + int64(req.Limit) - 1) / int64(req.Limit),
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取告警记录成功", Data: data}).Response()
+}
+
+// SendTest 发送测试告警
+func (ac *AlertController) SendTest(ctx *gin.Context) {
+	adminID := uint(ac.CurrentUserId(ctx))
+
+	alert, err := ac.AlertService.SendTest(adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "发送测试告警失败: " + err.Error()}).Response()
+		return
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "测试告警已入队，请在机器人私聊中查看", Data: alert}).Response()
+}
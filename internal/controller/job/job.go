@@ -0,0 +1,100 @@
+package job
+
+import (
+	"app/internal/controller"
+	"app/internal/request"
+	"app/internal/service"
+	"app/tools/resp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobController 异步任务运维控制器
+type JobController struct {
+	controller.BaseController
+	service.JobAdminService
+}
+
+// NewJobController 创建异步任务运维控制器实例
+func NewJobController(jobAdminService service.JobAdminService) *JobController {
+	return &JobController{
+		JobAdminService: jobAdminService,
+	}
+}
+
+// ReconcileReport 最近一次对账报告
+func (jc *JobController) ReconcileReport(ctx *gin.Context) {
+	report, err := jc.JobAdminService.GetReconcileReport()
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "获取对账报告失败: " + err.Error()}).Response()
+		return
+	}
+	if report == nil {
+		(&resp.JsonResp{Code: resp.ReSuccess, Msg: "暂无对账报告"}).Response()
+		return
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取对账报告成功", Data: report}).Response()
+}
+
+// RunReconcile 立即执行一次对账
+func (jc *JobController) RunReconcile(ctx *gin.Context) {
+	if err := jc.JobAdminService.TriggerReconcile(); err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "触发对账失败: " + err.Error()}).Response()
+		return
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "已触发对账，稍后查看对账报告"}).Response()
+}
+
+// Queues 队列概况
+func (jc *JobController) Queues(ctx *gin.Context) {
+	list, err := jc.JobAdminService.ListQueues()
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "获取队列失败: " + err.Error()}).Response()
+		return
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取队列成功", Data: list}).Response()
+}
+
+// SchedulerEntries 调度条目
+func (jc *JobController) SchedulerEntries(ctx *gin.Context) {
+	list, err := jc.JobAdminService.ListSchedulerEntries()
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "获取调度条目失败: " + err.Error()}).Response()
+		return
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取调度条目成功", Data: list}).Response()
+}
+
+// Tasks 按状态分页查询队列任务
+func (jc *JobController) Tasks(ctx *gin.Context) {
+	var req request.JobTaskListRequest
+	// 兼容查询参数（?state=pending）与JSON请求体
+	_ = ctx.ShouldBindQuery(&req)
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+			return
+		}
+	}
+	if req.State == "" {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: state 不能为空"}).Response()
+		return
+	}
+
+	result, err := jc.JobAdminService.ListQueueTasks(&req)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "获取队列任务失败: " + err.Error()}).Response()
+		return
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取队列任务成功", Data: result}).Response()
+}
+
+// Servers Worker 服务实例
+func (jc *JobController) Servers(ctx *gin.Context) {
+	list, err := jc.JobAdminService.ListServers()
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "获取服务实例失败: " + err.Error()}).Response()
+		return
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取服务实例成功", Data: list}).Response()
+}
@@ -0,0 +1,67 @@
+package task
+
+import (
+	"app/internal/request"
+	"app/internal/vo"
+	"app/tools/resp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BatchSubmit 批量提交任务
+func (tc *TaskController) BatchSubmit(ctx *gin.Context) {
+	tc.batch(ctx, "批量提交任务", tc.TaskService.BatchSubmitTasks)
+}
+
+// BatchPause 批量暂停任务
+func (tc *TaskController) BatchPause(ctx *gin.Context) {
+	tc.batch(ctx, "批量暂停任务", tc.TaskService.BatchPauseTasks)
+}
+
+// BatchResume 批量恢复任务
+func (tc *TaskController) BatchResume(ctx *gin.Context) {
+	tc.batch(ctx, "批量恢复任务", tc.TaskService.BatchResumeTasks)
+}
+
+// BatchDelete 批量删除任务
+func (tc *TaskController) BatchDelete(ctx *gin.Context) {
+	tc.batch(ctx, "批量删除任务", tc.TaskService.BatchDeleteTasks)
+}
+
+// BatchExtendExpire 批量修改周期任务到期时间
+func (tc *TaskController) BatchExtendExpire(ctx *gin.Context) {
+	var req request.TaskBatchExtendExpireRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	result, err := tc.TaskService.BatchExtendExpire(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "批量修改到期时间失败: " + err.Error()}).Response()
+		return
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "批量修改到期时间完成", Data: result}).Response()
+}
+
+// batch 批量操作通用处理：单个任务失败不影响整体结果，逐项结果见 items
+func (tc *TaskController) batch(ctx *gin.Context, name string, fn func(*request.TaskBatchRequest, uint) (*vo.TaskBatchResultVo, error)) {
+	var req request.TaskBatchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	result, err := fn(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: name + "失败: " + err.Error()}).Response()
+		return
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: name + "完成", Data: result}).Response()
+}
@@ -62,6 +62,63 @@ func (tc *TaskController) SubmitTask(ctx *gin.Context) {
     (&resp.JsonResp{Code: resp.ReSuccess, Msg: "提交任务成功", Data: taskVO}).Response()
 }
 
+// PauseTask 暂停任务（卸载调度，保留配置）
+func (tc *TaskController) PauseTask(ctx *gin.Context) {
+	var req request.PauseTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	taskVO, err := tc.TaskService.PauseTask(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "暂停任务失败: " + err.Error()}).Response()
+		return
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "暂停任务成功", Data: taskVO}).Response()
+}
+
+// ResumeTask 恢复任务（重新计算下次执行时间并重新注册调度）
+func (tc *TaskController) ResumeTask(ctx *gin.Context) {
+	var req request.ResumeTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	taskVO, err := tc.TaskService.ResumeTask(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "恢复任务失败: " + err.Error()}).Response()
+		return
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "恢复任务成功", Data: taskVO}).Response()
+}
+
+// TriggerTask 手动触发任务（立即执行一次，不影响调度）
+func (tc *TaskController) TriggerTask(ctx *gin.Context) {
+	var req request.TriggerTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	result, err := tc.TaskService.TriggerTask(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "触发任务失败: " + err.Error()}).Response()
+		return
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "触发任务成功", Data: result}).Response()
+}
+
 // UpdateTask 更新任务
 func (tc *TaskController) UpdateTask(ctx *gin.Context) {
 	var req request.UpdateTaskRequest
@@ -155,3 +212,136 @@ func (tc *TaskController) TaskList(ctx *gin.Context) {
 
 	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取任务列表成功", Data: data}).Response()
 }
+
+// ExecutionList 任务执行记录列表
+func (tc *TaskController) ExecutionList(ctx *gin.Context) {
+	var req request.TaskExecutionListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	result, err := tc.TaskService.ListExecutions(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "查询执行记录失败: " + err.Error()}).Response()
+		return
+	}
+
+	data := map[string]interface{}{
+		"list":      result.List,
+		"total":     result.Total,
+		"page":      req.Page,
+		"pageSize":  req.Limit,
+		"pageCount": (result.Total + int64(req.Limit) - 1) / int64(req.Limit),
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取执行记录成功", Data: data}).Response()
+}
+
+// ExecutionDetail 任务执行记录详情
+func (tc *TaskController) ExecutionDetail(ctx *gin.Context) {
+	var req request.TaskExecutionDetailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	detail, err := tc.TaskService.GetExecutionDetail(req.ID, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "获取执行记录详情失败: " + err.Error()}).Response()
+		return
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取执行记录详情成功", Data: detail}).Response()
+}
+
+// CronPreview Cron表达式校验与执行时间预览
+func (tc *TaskController) CronPreview(ctx *gin.Context) {
+	var req request.CronPreviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	preview, err := tc.TaskService.PreviewCron(&req)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "Cron预览失败: " + err.Error()}).Response()
+		return
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "Cron预览成功", Data: preview}).Response()
+}
+
+// CronPresets 预设的常用Cron表达式
+func (tc *TaskController) CronPresets(ctx *gin.Context) {
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取预设Cron表达式成功", Data: tc.TaskService.GetCronPresets()}).Response()
+}
+
+// DeadLetterList 任务重试耗尽后归档的队列任务
+func (tc *TaskController) DeadLetterList(ctx *gin.Context) {
+	var req request.TaskDeadLetterListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	result, err := tc.TaskService.ListDeadLetters(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "查询归档任务失败: " + err.Error()}).Response()
+		return
+	}
+
+	data := map[string]interface{}{
+		"list":      result.List,
+		"total":     result.Total,
+		"page":      req.Page,
+		"pageSize":  req.Limit,
+		"pageCount": (result.Total + int64(req.Limit) - 1) / int64(req.Limit),
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取归档任务成功", Data: data}).Response()
+}
+
+// RequeueDeadLetters 重新执行归档任务
+func (tc *TaskController) RequeueDeadLetters(ctx *gin.Context) {
+	var req request.TaskDeadLetterRequeueRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	result, err := tc.TaskService.RequeueDeadLetters(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "重新执行归档任务失败: " + err.Error()}).Response()
+		return
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "重新执行归档任务成功", Data: result}).Response()
+}
+
+// DeleteDeadLetters 删除归档任务
+func (tc *TaskController) DeleteDeadLetters(ctx *gin.Context) {
+	var req request.TaskDeadLetterDeleteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	result, err := tc.TaskService.DeleteDeadLetters(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "删除归档任务失败: " + err.Error()}).Response()
+		return
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "删除归档任务成功", Data: result}).Response()
+}
@@ -0,0 +1,148 @@
+package task
+
+import (
+	"app/internal/request"
+	"app/tools/resp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CloneTask 复制任务为新的待提交任务
+func (tc *TaskController) CloneTask(ctx *gin.Context) {
+	var req request.CloneTaskRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	taskVO, err := tc.TaskService.CloneTask(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "复制任务失败: " + err.Error()}).Response()
+		return
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "复制任务成功", Data: taskVO}).Response()
+}
+
+// CreateTemplate 创建任务模板
+func (tc *TaskController) CreateTemplate(ctx *gin.Context) {
+	var req request.CreateTaskTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	tpl, err := tc.TaskService.CreateTemplate(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "创建模板失败: " + err.Error()}).Response()
+		return
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "创建模板成功", Data: tpl}).Response()
+}
+
+// UpdateTemplate 更新任务模板
+func (tc *TaskController) UpdateTemplate(ctx *gin.Context) {
+	var req request.UpdateTaskTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	tpl, err := tc.TaskService.UpdateTemplate(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "更新模板失败: " + err.Error()}).Response()
+		return
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "更新模板成功", Data: tpl}).Response()
+}
+
+// DeleteTemplate 删除任务模板
+func (tc *TaskController) DeleteTemplate(ctx *gin.Context) {
+	var req request.DeleteTaskTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	if err := tc.TaskService.DeleteTemplate(&req, adminID); err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "删除模板失败: " + err.Error()}).Response()
+		return
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "删除模板成功"}).Response()
+}
+
+// TemplateDetail 获取任务模板详情
+func (tc *TaskController) TemplateDetail(ctx *gin.Context) {
+	var req request.GetTaskTemplateDetailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	tpl, err := tc.TaskService.GetTemplate(req.ID, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "获取模板详情失败: " + err.Error()}).Response()
+		return
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取模板详情成功", Data: tpl}).Response()
+}
+
+// TemplateList 任务模板列表
+func (tc *TaskController) TemplateList(ctx *gin.Context) {
+	var req request.TaskTemplateListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	result, err := tc.TaskService.ListTemplates(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "查询模板失败: " + err.Error()}).Response()
+		return
+	}
+
+	data := map[string]interface{}{
+		"list":      result.List,
+		"total":     result.Total,
+		"page":      req.Page,
+		"pageSize":  req.Limit,
+		"pageCount": (result.Total + int64(req.Limit) - 1) / int64(req.Limit),
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取模板列表成功", Data: data}).Response()
+}
+
+// InstantiateTemplate 按模板创建任务（待提交）
+func (tc *TaskController) InstantiateTemplate(ctx *gin.Context) {
+	var req request.InstantiateTaskTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(tc.CurrentUserId(ctx))
+
+	taskVO, err := tc.TaskService.InstantiateTemplate(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "按模板创建任务失败: " + err.Error()}).Response()
+		return
+	}
+
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "按模板创建任务成功", Data: taskVO}).Response()
+}
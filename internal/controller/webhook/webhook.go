@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"app/internal/controller"
+	"app/internal/request"
+	"app/internal/service"
+	"app/tools/resp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookController webhook订阅控制器
+type WebhookController struct {
+	controller.BaseController
+	service.WebhookService
+}
+
+// NewWebhookController 创建webhook控制器实例
+func NewWebhookController(webhookService service.WebhookService) *WebhookController {
+	return &WebhookController{
+		WebhookService: webhookService,
+	}
+}
+
+// Create 创建webhook
+func (wc *WebhookController) Create(ctx *gin.Context) {
+	var req request.CreateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(wc.CurrentUserId(ctx))
+
+	hook, err := wc.WebhookService.CreateWebhook(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "创建webhook失败: " + err.Error()}).Response()
+		return
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "创建webhook成功，请妥善保存签名密钥", Data: hook}).Response()
+}
+
+// Update 更新webhook
+func (wc *WebhookController) Update(ctx *gin.Context) {
+	var req request.UpdateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(wc.CurrentUserId(ctx))
+
+	hook, err := wc.WebhookService.UpdateWebhook(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "更新webhook失败: " + err.Error()}).Response()
+		return
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "更新webhook成功", Data: hook}).Response()
+}
+
+// Delete 删除webhook
+func (wc *WebhookController) Delete(ctx *gin.Context) {
+	var req request.DeleteWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(wc.CurrentUserId(ctx))
+
+	if err := wc.WebhookService.DeleteWebhook(&req, adminID); err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "删除webhook失败: " + err.Error()}).Response()
+		return
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "删除webhook成功"}).Response()
+}
+
+// List webhook列表
+func (wc *WebhookController) List(ctx *gin.Context) {
+	var req request.WebhookListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(wc.CurrentUserId(ctx))
+
+	result, err := wc.WebhookService.ListWebhooks(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "查询webhook失败: " + err.Error()}).Response()
+		return
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取webhook列表成功", Data: pageData(result.List, result.Total, req.PageRequest)}).Response()
+}
+
+// Deliveries 投递记录
+func (wc *WebhookController) Deliveries(ctx *gin.Context) {
+	var req request.WebhookDeliveryListRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(wc.CurrentUserId(ctx))
+
+	result, err := wc.WebhookService.ListDeliveries(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "查询投递记录失败: " + err.Error()}).Response()
+		return
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "获取投递记录成功", Data: pageData(result.List, result.Total, req.PageRequest)}).Response()
+}
+
+// Replay 重放投递
+func (wc *WebhookController) Replay(ctx *gin.Context) {
+	var req request.ReplayWebhookDeliveryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		(&resp.JsonResp{Code: resp.ReFail, Msg: "参数缺失或格式错误: " + err.Error()}).Response()
+		return
+	}
+
+	adminID := uint(wc.CurrentUserId(ctx))
+
+	delivery, err := wc.WebhookService.ReplayDelivery(&req, adminID)
+	if err != nil {
+		(&resp.JsonResp{Code: resp.ReError, Msg: "重放失败: " + err.Error()}).Response()
+		return
+	}
+	(&resp.JsonResp{Code: resp.ReSuccess, Msg: "已重新入队投递", Data: delivery}).Response()
+}
+
+func pageData(list interface{}, total int64, page request.PageRequest) map[string]interface{} {
+	return map[string]interface{}{
+		"list":      list,
+		"total":     total,
+		"page":      page.Page,
+		"pageSize":  page.Limit,
+		"pageCount": (total + int64(page.Limit) - 1) / int64(page.Limit),
+	}
+}
@@ -0,0 +1,319 @@
+package job
+
+import (
+	"app/internal/config"
+	"app/internal/dto"
+	"app/internal/model"
+	"app/tools/logger"
+	"app/tools/telegram"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+// 告警发送与每日汇总任务类型
+const (
+	AlertType       = "admin_alert"
+	AlertDigestType = "admin_alert_digest"
+)
+
+// 每小时整点检查需要发送每日汇总的管理员
+const alertDigestSpec = "0 * * * *"
+
+const (
+	alertStreakKeyPrefix   = "alert:streak:" // 任务连续失败次数
+	alertRateKeyPrefix     = "alert:rate:"   // 管理员每小时已发送告警数
+	alertDigestKeyPrefix   = "alert:digest:" // 管理员当日汇总已发送标记
+	defaultAlertMaxPerHour = 20
+	alertErrorMaxLen       = 500
+)
+
+// AlertHandler 通过管理员自己的机器人私聊发送告警；按管理员限制每小时告警数，并共享 Telegram 发送限流
+type AlertHandler struct {
+	db         *gorm.DB
+	js         *JobService
+	tg         *telegram.Client
+	throttle   *deliveryThrottler
+	maxPerHour int
+}
+
+func NewAlertHandler(jobService *JobService, db *gorm.DB, conf *config.Config) {
+	timeout := config.Get[int](conf, "telegram", "timeout")
+	maxPerHour := config.Get[int](conf, "alert", "max_per_hour")
+	if maxPerHour <= 0 {
+		maxPerHour = defaultAlertMaxPerHour
+	}
+	handler := &AlertHandler{
+		db: db,
+		js: jobService,
+		tg: telegram.NewClient(config.Get[string](conf, "telegram", "api_base"), time.Duration(timeout)*time.Second),
+		throttle: newDeliveryThrottler(jobService.rdb,
+			config.Get[int](conf, "telegram", "rate_bot_per_second"),
+			config.Get[int](conf, "telegram", "rate_chat_per_minute"),
+			time.Duration(config.Get[int](conf, "telegram", "rate_max_wait"))*time.Second),
+		maxPerHour: maxPerHour,
+	}
+	jobService.RegisterHandler(handler)
+	jobService.RegisterHandler(&alertDigestHandler{db: db, js: jobService})
+	// 每小时只汇总一次，失败不重试（下一小时不会补发）
+	jobService.RegisterSystemCron(alertDigestSpec, AlertDigestType, asynq.MaxRetry(0), asynq.Unique(50*time.Minute))
+}
+
+func (h *AlertHandler) TaskType() string {
+	return AlertType
+}
+
+func (h *AlertHandler) Process(ctx context.Context, payload []byte) error {
+	p, err := DecodeJobPayload(payload)
+	if err != nil || p.Ref == 0 {
+		return fmt.Errorf("告警payload格式错误: %w", asynq.SkipRetry)
+	}
+	var alert model.AdminAlert
+	if err := h.db.Where("id = ?", p.Ref).First(&alert).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("告警记录不存在(ID=%d): %w", p.Ref, asynq.SkipRetry)
+		}
+		return err
+	}
+	if alert.Status == model.AlertStatusSent || alert.Status == model.AlertStatusSuppressed {
+		return nil
+	}
+
+	var cfg model.AdminAlertConfig
+	if err := h.db.Where("admin_id = ?", alert.AdminID).First(&cfg).Error; err != nil {
+		h.finish(&alert, model.AlertStatusFailed, "未配置告警接收")
+		return fmt.Errorf("管理员%d未配置告警接收: %w", alert.AdminID, asynq.SkipRetry)
+	}
+	token, err := h.botToken(&cfg)
+	if err != nil {
+		h.finish(&alert, model.AlertStatusFailed, err.Error())
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+
+	// 测试告警不计入频率上限
+	if alert.Kind != model.AlertKindTest && !h.allow(ctx, alert.AdminID) {
+		h.finish(&alert, model.AlertStatusSuppressed, fmt.Sprintf("超过每小时%d条告警上限", h.maxPerHour))
+		logger.System("告警超过频率上限，未发送", "adminID", alert.AdminID, "alertID", alert.ID, "kind", alert.Kind)
+		return nil
+	}
+
+	err = h.throttle.Call(ctx, token, cfg.ChatID, 1, func() error {
+		_, err := h.tg.SendMessage(ctx, token, cfg.ChatID, alert.Content)
+		return err
+	})
+	if err != nil {
+		h.finish(&alert, model.AlertStatusFailed, err.Error())
+		logger.Error("告警发送失败", "adminID", alert.AdminID, "alertID", alert.ID, "error", err)
+		return err
+	}
+	h.finish(&alert, model.AlertStatusSent, "")
+	logger.System("告警已发送", "adminID", alert.AdminID, "alertID", alert.ID, "kind", alert.Kind, "taskID", alert.TaskID)
+	return nil
+}
+
+// botToken 读取告警使用的机器人token，机器人必须属于该管理员
+func (h *AlertHandler) botToken(cfg *model.AdminAlertConfig) (string, error) {
+	var bc model.BotConfig
+	if err := h.db.Where("id = ? AND admin_id = ?", cfg.BotConfigID, cfg.AdminID).First(&bc).Error; err != nil {
+		return "", errors.New("告警机器人不存在或不属于该管理员")
+	}
+	var data dto.BotConfigData
+	if err := json.Unmarshal(bc.Config, &data); err != nil || strings.TrimSpace(data.Token) == "" {
+		return "", errors.New("告警机器人未配置token")
+	}
+	return data.Token, nil
+}
+
+// allow 管理员当前小时的告警数是否未超过上限
+func (h *AlertHandler) allow(ctx context.Context, adminID uint) bool {
+	key := fmt.Sprintf("%s%d:%s", alertRateKeyPrefix, adminID, time.Now().Format("2006010215"))
+	n, err := h.js.rdb.Incr(ctx, key).Result()
+	if err != nil {
+		return true
+	}
+	if n == 1 {
+		h.js.rdb.Expire(ctx, key, time.Hour)
+	}
+	return n <= int64(h.maxPerHour)
+}
+
+func (h *AlertHandler) finish(alert *model.AdminAlert, status int, errMsg string) {
+	updates := map[string]interface{}{
+		"status":        status,
+		"error_message": errMsg,
+	}
+	if status == model.AlertStatusSent {
+		now := time.Now()
+		updates["sent_at"] = &now
+	}
+	if err := h.db.Model(&model.AdminAlert{}).Where("id = ?", alert.ID).Updates(updates).Error; err != nil {
+		logger.Error("更新告警状态失败", "alertID", alert.ID, "error", err)
+	}
+}
+
+// EnqueueAlert 写入告警记录并入队发送；管理员未配置或已停用告警时返回 nil
+func (ts *JobService) EnqueueAlert(adminID uint, taskID uint64, kind, content string) (*model.AdminAlert, error) {
+	if ts.db == nil {
+		return nil, nil
+	}
+	var cfg model.AdminAlertConfig
+	if err := ts.db.Where("admin_id = ? AND enabled = 1", adminID).First(&cfg).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	alert := &model.AdminAlert{
+		AdminID:    adminID,
+		TaskID:     taskID,
+		Kind:       kind,
+		Content:    content,
+		Status:     model.AlertStatusPending,
+		CreateTime: time.Now(),
+	}
+	if err := ts.db.Create(alert).Error; err != nil {
+		return nil, err
+	}
+	payload := NewJobPayload(AlertType, 0, nil)
+	payload.Ref = alert.ID
+	if _, err := ts.EnqueueTask(AlertType, payload.Encode(), asynq.Queue(ts.QueueFor(config.PriorityHigh)), asynq.MaxRetry(3)); err != nil {
+		_ = ts.db.Model(alert).Updates(map[string]interface{}{"status": model.AlertStatusFailed, "error_message": "入队失败: " + err.Error()}).Error
+		return nil, err
+	}
+	return alert, nil
+}
+
+// notifyTaskFailure 任务本次运行最终失败（不再重试，且没有待执行的失败补发）：累计连续失败次数，按管理员规则发送失败或连续失败告警
+func (ts *JobService) notifyTaskFailure(t *model.Task, execErr error) {
+	ctx := context.Background()
+	key := fmt.Sprintf("%s%d", alertStreakKeyPrefix, t.ID)
+	streak, err := ts.rdb.Incr(ctx, key).Result()
+	if err == nil {
+		ts.rdb.Expire(ctx, key, 30*24*time.Hour)
+	}
+
+	var cfg model.AdminAlertConfig
+	if err := ts.db.Where("admin_id = ? AND enabled = 1", t.AdminID).First(&cfg).Error; err != nil {
+		return
+	}
+	errMsg := fmt.Sprintf("%v", execErr)
+	if utf8.RuneCountInString(errMsg) > alertErrorMaxLen {
+		errMsg = string([]rune(errMsg)[:alertErrorMaxLen]) + "…"
+	}
+	now := time.Now().In(t.Location()).Format("2006-01-02 15:04:05")
+
+	var kind, content string
+	switch {
+	case cfg.ConsecutiveFailures > 0 && streak > 0 && streak%int64(cfg.ConsecutiveFailures) == 0:
+		kind = model.AlertKindConsecutive
+		content = fmt.Sprintf("【任务连续失败】\n任务：%s (ID %d)\n已连续失败：%d 次\n时间：%s\n错误：%s", t.TaskName, t.ID, streak, now, errMsg)
+	case cfg.OnFailure:
+		kind = model.AlertKindFailure
+		content = fmt.Sprintf("【任务执行失败】\n任务：%s (ID %d)\n时间：%s\n错误：%s", t.TaskName, t.ID, now, errMsg)
+	default:
+		return
+	}
+	if _, err := ts.EnqueueAlert(t.AdminID, t.ID, kind, content); err != nil {
+		logger.Error("失败告警入队失败", "taskID", t.ID, "error", err)
+	}
+}
+
+// notifyTaskExpired 周期任务到期告警
+func (ts *JobService) notifyTaskExpired(t *model.Task) {
+	var cfg model.AdminAlertConfig
+	if err := ts.db.Where("admin_id = ? AND enabled = 1 AND on_expire = 1", t.AdminID).First(&cfg).Error; err != nil {
+		return
+	}
+	content := fmt.Sprintf("【周期任务已到期】\n任务：%s (ID %d)\n到期后已停止调度，如需继续请修改到期时间后重新提交。", t.TaskName, t.ID)
+	if _, err := ts.EnqueueAlert(t.AdminID, t.ID, model.AlertKindExpired, content); err != nil {
+		logger.Error("到期告警入队失败", "taskID", t.ID, "error", err)
+	}
+}
+
+// resetFailureStreak 任务执行成功（含补发成功）后清零连续失败次数
+func (ts *JobService) resetFailureStreak(taskID uint64) {
+	ts.rdb.Del(context.Background(), fmt.Sprintf("%s%d", alertStreakKeyPrefix, taskID))
+}
+
+// alertDigestHandler 每日汇总：到达管理员设定的整点时汇总最近24小时的执行情况
+type alertDigestHandler struct {
+	db *gorm.DB
+	js *JobService
+}
+
+func (h *alertDigestHandler) TaskType() string {
+	return AlertDigestType
+}
+
+func (h *alertDigestHandler) Process(ctx context.Context, payload []byte) error {
+	now := time.Now()
+	var configs []model.AdminAlertConfig
+	if err := h.db.Where("enabled = 1 AND daily_digest = 1 AND digest_hour = ?", now.Hour()).Find(&configs).Error; err != nil {
+		return err
+	}
+	for _, cfg := range configs {
+		// 同一天只发送一次（重启或重复触发时）
+		key := fmt.Sprintf("%s%d:%s", alertDigestKeyPrefix, cfg.AdminID, now.Format("20060102"))
+		if ok, err := h.js.rdb.SetNX(ctx, key, 1, 25*time.Hour).Result(); err != nil || !ok {
+			continue
+		}
+		content, err := h.digest(cfg.AdminID, now)
+		if err != nil {
+			logger.Error("生成每日汇总失败", "adminID", cfg.AdminID, "error", err)
+			continue
+		}
+		if _, err := h.js.EnqueueAlert(cfg.AdminID, 0, model.AlertKindDigest, content); err != nil {
+			logger.Error("每日汇总入队失败", "adminID", cfg.AdminID, "error", err)
+		}
+	}
+	return nil
+}
+
+// digest 汇总最近24小时的执行结果、当前失败任务与即将到期的周期任务
+func (h *alertDigestHandler) digest(adminID uint, now time.Time) (string, error) {
+	since := now.Add(-24 * time.Hour)
+	var stats []struct {
+		Status int
+		Count  int
+	}
+	if err := h.db.Model(&model.TaskExecution{}).Select("status, COUNT(*) AS count").
+		Where("admin_id = ? AND started_at >= ?", adminID, since).Group("status").Scan(&stats).Error; err != nil {
+		return "", err
+	}
+	byStatus := make(map[int]int, len(stats))
+	total := 0
+	for _, s := range stats {
+		byStatus[s.Status] = s.Count
+		total += s.Count
+	}
+
+	var failed []model.Task
+	if err := h.db.Select("id", "task_name").Where("admin_id = ? AND status = 3 AND is_delete = 0", adminID).
+		Order("update_time DESC").Limit(10).Find(&failed).Error; err != nil {
+		return "", err
+	}
+	var failedCount int64
+	h.db.Model(&model.Task{}).Where("admin_id = ? AND status = 3 AND is_delete = 0", adminID).Count(&failedCount)
+
+	var expiring int64
+	h.db.Model(&model.Task{}).Where("admin_id = ? AND trigger_type = ? AND status IN ? AND is_delete = 0 AND expire_time BETWEEN ? AND ?",
+		adminID, model.TriggerTypeCron, []int{0, 1}, now, now.Add(24*time.Hour)).Count(&expiring)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "【每日任务汇总】%s\n", now.Format("2006-01-02"))
+	fmt.Fprintf(&b, "最近24小时执行：%d 次（成功 %d，部分失败 %d，失败 %d，错过 %d）\n", total,
+		byStatus[model.ExecutionStatusSuccess], byStatus[model.ExecutionStatusPartial], byStatus[model.ExecutionStatusFailed], byStatus[model.ExecutionStatusMissed])
+	fmt.Fprintf(&b, "当前失败任务：%d 个\n", failedCount)
+	for _, t := range failed {
+		fmt.Fprintf(&b, "  - %s (ID %d)\n", t.TaskName, t.ID)
+	}
+	fmt.Fprintf(&b, "24小时内到期的周期任务：%d 个", expiring)
+	return b.String(), nil
+}
@@ -1,28 +1,107 @@
 package job
 
 import (
+	"app/internal/config"
+	"app/internal/dto"
+	"app/internal/model"
 	"app/tools/logger"
+	"app/tools/telegram"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
 )
 
 var (
 	BotMsgType = "bot_msg"
 )
 
-type BotMsgPayload struct {
-    MsgType string `json:"msg_type"`
-    Content string `json:"content"`
-    TaskID  uint64 `json:"taskId,omitempty"`
-    ExpireTime string `json:"expireTime,omitempty"`
+// MsgTypeManualTrigger 手动触发（立即执行一次）的 payload type，不影响任务调度状态
+const MsgTypeManualTrigger = "manual_trigger"
+
+// MsgTypeRateLimited 触发限流后补发剩余投递的 payload type，不影响任务调度状态
+const MsgTypeRateLimited = "rate_limited"
+
+// MsgTypeSendWindow 群组静默期结束后补发的 payload type，不影响任务调度状态
+const MsgTypeSendWindow = "send_window"
+
+// MsgTypeRetryFailed 部分投递失败后只补发失败部分的 payload type，不影响任务调度状态
+const MsgTypeRetryFailed = "retry_failed"
+
+// IsDeferredSource 是否为补发类 payload（限流/静默期/失败补发）：只投递 Targets 指定的部分，不改变任务状态
+func IsDeferredSource(source string) bool {
+	return source == MsgTypeRateLimited || source == MsgTypeSendWindow || source == MsgTypeRetryFailed
+}
+
+// tracksTaskStatus 该来源的运行结束后是否回写任务状态：手动触发与限流/静默期补发不改变任务状态
+func tracksTaskStatus(origin string) bool {
+	return origin != MsgTypeManualTrigger && !IsDeferredSource(origin)
+}
+
+// PendingRetryError 部分投递失败且可重试的部分已转入补发：本次运行尚未最终失败，由补发结束时回写任务状态
+// 同时包装 asynq.SkipRetry，避免 asynq 整体重试重复发送已成功的投递
+type PendingRetryError struct {
+	Err     error
+	Attempt int       // 补发对应的重试次数（从1开始）
+	RetryAt time.Time // 补发任务的执行时间
+}
+
+func (e *PendingRetryError) Error() string {
+	return fmt.Sprintf("%v（可重试部分已转入补发）", e.Err)
 }
 
+func (e *PendingRetryError) Unwrap() []error {
+	return []error{e.Err, asynq.SkipRetry}
+}
+
+// errNoBotToken 群组未配置机器人token，重试无意义
+var errNoBotToken = errors.New("未配置机器人token")
+
+// errFileNotFound 消息引用的文件已不存在（已删除或从未上传），重试无意义
+var errFileNotFound = errors.New("文件不存在")
+
+// FileReader 按文件ID读取上传文件（由文件服务实现），未找到时返回匹配 os.ErrNotExist 的错误
+type FileReader interface {
+	GetFileContent(fileID string) ([]byte, string, error)
+}
+
+// 同一批投递最多因限流延后的次数，超过后按失败处理
+const maxDeliveryDeferrals = 5
+
+// Telegram 单条 caption 最大长度
+const captionMaxLen = 1024
+
+// 单个媒体组最大数量
+const mediaGroupMaxSize = 10
+
 type BotMsgHandler struct {
+	db         *gorm.DB
+	jobService *JobService
+	tg         *telegram.Client
+	throttle   *deliveryThrottler
+	files      FileReader
 }
 
-func NewBotMsgHandler(jobService *JobService) {
-	handler := &BotMsgHandler{}
+func NewBotMsgHandler(jobService *JobService, db *gorm.DB, conf *config.Config, files FileReader) {
+	timeout := config.Get[int](conf, "telegram", "timeout")
+	handler := &BotMsgHandler{
+		db:         db,
+		jobService: jobService,
+		tg:         telegram.NewClient(config.Get[string](conf, "telegram", "api_base"), time.Duration(timeout)*time.Second),
+		throttle: newDeliveryThrottler(jobService.rdb,
+			config.Get[int](conf, "telegram", "rate_bot_per_second"),
+			config.Get[int](conf, "telegram", "rate_chat_per_minute"),
+			time.Duration(config.Get[int](conf, "telegram", "rate_max_wait"))*time.Second),
+		files: files,
+	}
+	logger.System("Telegram Bot API 地址", "baseURL", handler.tg.BaseURL())
 	jobService.RegisterHandler(handler)
 }
 
@@ -31,16 +110,460 @@ func (b *BotMsgHandler) TaskType() string {
 }
 
 func (b *BotMsgHandler) Process(ctx context.Context, payload []byte) error {
-	var botMsg BotMsgPayload
-	if err := json.Unmarshal(payload, &botMsg); err != nil {
+	botMsg, err := DecodeJobPayload(payload)
+	if err != nil {
 		logger.Error("BotMsgHandler 反序列化失败", "error", err, "payload", string(payload))
+		return fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+
+	taskID := botMsg.TaskID
+	if taskID == 0 {
+		// 无法关联DB任务的payload重试也无意义
+		return fmt.Errorf("payload缺少任务ID: %w", asynq.SkipRetry)
+	}
+
+	var task model.Task
+	if err := b.db.Where("id = ? AND is_delete = 0", taskID).First(&task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("任务不存在或已删除(ID=%d): %w", taskID, asynq.SkipRetry)
+		}
+		return err
+	}
+
+	// 补发只投递 payload 中指定的群组与消息，否则按轮换模式投递任务的群组×消息
+	targets := botMsg.Targets
+	var messages []model.Message
+	if len(targets) == 0 {
+		var groupIDs []int64
+		if err := json.Unmarshal(task.GroupIDs, &groupIDs); err != nil || len(groupIDs) == 0 {
+			return fmt.Errorf("任务群组列表为空或格式错误: %w", asynq.SkipRetry)
+		}
+		var messageIDs []uint64
+		if err := json.Unmarshal(task.MessageIDs, &messageIDs); err != nil || len(messageIDs) == 0 {
+			return fmt.Errorf("任务消息列表为空或格式错误: %w", asynq.SkipRetry)
+		}
+		if messages, err = b.loadMessages(messageIDs); err != nil {
+			return err
+		}
+		// 轮换只在未删除的消息中选取
+		available := make([]uint64, len(messages))
+		for i, m := range messages {
+			available[i] = uint64(m.ID)
+		}
+		var selected []uint64
+		if botMsg.Type == MsgTypeManualTrigger {
+			// 手动触发不推进轮换状态
+			selected = b.jobService.peekRotationMessages(ctx, &task, available)
+		} else {
+			runID, _ := asynq.GetTaskID(ctx)
+			selected = b.jobService.pickRotationMessages(ctx, &task, available, runID)
+		}
+		for _, groupID := range groupIDs {
+			targets = append(targets, DeliveryTarget{GroupID: groupID, MessageIDs: selected})
+		}
+	} else {
+		var messageIDs []uint64
+		for _, target := range targets {
+			messageIDs = append(messageIDs, target.MessageIDs...)
+		}
+		if messages, err = b.loadMessages(messageIDs); err != nil {
+			return err
+		}
+	}
+	if len(messages) == 0 {
+		return fmt.Errorf("任务关联的消息均不存在或已删除: %w", asynq.SkipRetry)
+	}
+
+	groupIDs := make([]int64, 0, len(targets))
+	for _, target := range targets {
+		groupIDs = append(groupIDs, target.GroupID)
+	}
+	byID := make(map[uint64]model.Message, len(messages))
+	for _, m := range messages {
+		byID[uint64(m.ID)] = m
+	}
+
+	tokens, windows, err := b.loadBotConfigs(groupIDs)
+	if err != nil {
 		return err
 	}
 
-	logger.System("成功处理机器人消息", "msgType", botMsg.MsgType, "content", botMsg.Content, "处理时间", time.Now().Format("2006-01-02 15:04:05"))
+	rec := ExecutionFromContext(ctx)
+	res := b.deliver(ctx, rec, &task, botMsg, targets, byID, tokens, windows)
+
+	logger.System("机器人消息处理完成", "taskID", taskID, "msgType", botMsg.Type, "total", res.total, "failed", len(res.failures), "deferred", res.deferred, "skipped", res.skipped, "处理时间", time.Now().Format("2006-01-02 15:04:05"))
+	if len(res.failures) == 0 {
+		return nil
+	}
+	failErr := fmt.Errorf("投递失败 %d/%d: %s", len(res.failures), res.total, strings.Join(res.failures, "; "))
+	if res.retryAll() {
+		return failErr
+	}
+	// 已有投递成功/延后/跳过或存在不可重试失败：整体重试会重复发送，只将可重试的失败投递以补发任务重新入队
+	if len(res.retry) > 0 {
+		// 失败补发延续原运行的重试次数与退避
+		retried, _ := asynq.GetRetryCount(ctx)
+		retried += botMsg.Attempt
+		maxRetry, ok := asynq.GetMaxRetry(ctx)
+		if ok {
+			maxRetry += botMsg.Attempt
+		} else {
+			maxRetry = task.MaxRetryCount
+		}
+		if remaining := maxRetry - retried; remaining > 0 {
+			info, err := b.retryFailedDeliveries(&task, botMsg, res.retry, retried+1, remaining-1)
+			if err != nil {
+				logger.Error("失败投递补发入队失败", "taskID", taskID, "error", err)
+			} else {
+				logger.System("已将失败投递转入补发", "taskID", taskID, "groups", len(res.retry), "maxRetry", remaining-1, "processAt", info.NextProcessAt)
+				return &PendingRetryError{Err: failErr, Attempt: retried + 1, RetryAt: info.NextProcessAt}
+			}
+		}
+	}
+	return fmt.Errorf("%v: %w", failErr, asynq.SkipRetry)
+}
+
+// deliveryResult 一次运行的投递结果
+type deliveryResult struct {
+	total, sent, deferred, skipped int
+	failures                       []string
+	// 可重试的失败投递（按群组聚合）与不可重试的失败数
+	retry     []DeliveryTarget
+	permanent int
+}
+
+// retryAll 全部为可重试失败且没有已完成的投递：按 asynq 重试整个 payload，不会重复发送
+func (r *deliveryResult) retryAll() bool {
+	return r.permanent == 0 && r.sent == 0 && r.deferred == 0 && r.skipped == 0
+}
+
+// deliver 按群组逐条投递 targets 中的消息，静默期与限流的投递延后入队，失败的投递按是否可重试分别统计
+func (b *BotMsgHandler) deliver(ctx context.Context, rec *ExecutionRecorder, task *model.Task, run *JobPayload, targets []DeliveryTarget, byID map[uint64]model.Message, tokens map[int64]string, windows map[int64]*model.SendWindow) *deliveryResult {
+	res := &deliveryResult{failures: make([]string, 0)}
+	fail := func(groupID int64, msgID uint64, err error) {
+		res.failures = append(res.failures, fmt.Sprintf("群组%d/消息%d: %v", groupID, msgID, err))
+		if permanentDeliveryError(err) {
+			res.permanent++
+			return
+		}
+		if n := len(res.retry); n > 0 && res.retry[n-1].GroupID == groupID {
+			res.retry[n-1].MessageIDs = append(res.retry[n-1].MessageIDs, msgID)
+			return
+		}
+		res.retry = append(res.retry, DeliveryTarget{GroupID: groupID, MessageIDs: []uint64{msgID}})
+	}
+	for _, target := range targets {
+		groupID := target.GroupID
+		token, ok := tokens[groupID]
+		if window := windows[groupID]; ok && window != nil && !window.Allowed(time.Now()) {
+			// 群组处于静默期：该群组本次的全部投递按策略延后或跳过
+			n, held, err := b.holdForSendWindow(rec, task, target, window, byID)
+			res.total += n
+			if err != nil {
+				logger.Error("静默期延后投递入队失败", "taskID", task.ID, "groupID", groupID, "error", err)
+				for _, id := range target.MessageIDs {
+					if _, exists := byID[id]; exists {
+						fail(groupID, id, fmt.Errorf("静默期延后入队失败: %v", err))
+					}
+				}
+			} else if held == model.DeliveryStatusSkipped {
+				res.skipped += n
+			} else {
+				res.deferred += n
+			}
+			continue
+		}
+		for i, msgID := range target.MessageIDs {
+			msg, exists := byID[msgID]
+			if !exists {
+				continue
+			}
+			res.total++
+			if !ok {
+				rec.RecordDelivery(groupID, msgID, nil, 0, errNoBotToken)
+				fail(groupID, msgID, errNoBotToken)
+				continue
+			}
+			// 限流补发时首条消息跳过已发送的分段
+			skip := 0
+			if i == 0 {
+				skip = target.SentChunks
+			}
+			sendStart := time.Now()
+			msgIDs, done, err := b.sendToChat(ctx, token, groupID, msg, skip)
+			var limited *RateLimitedError
+			if errors.As(err, &limited) && run.Deferrals < maxDeliveryDeferrals {
+				// 该群组当前及剩余消息整体延后（当前消息从未发送的分段继续），其他群组不受影响
+				rest := target.MessageIDs[i:]
+				resume := DeliveryTarget{GroupID: groupID, MessageIDs: rest, SentChunks: done}
+				if deferErr := b.deferDeliveries(task, MsgTypeRateLimited, run.Deferrals+1, resume, limited.RetryAfter); deferErr != nil {
+					logger.Error("限流延后投递入队失败", "taskID", task.ID, "groupID", groupID, "error", deferErr)
+					rec.RecordDelivery(groupID, msgID, msgIDs, time.Since(sendStart), err)
+					fail(groupID, msgID, err)
+					continue
+				}
+				n := 0
+				for _, id := range rest {
+					if _, exists := byID[id]; exists {
+						rec.RecordDeferred(groupID, id, limited.Error())
+						n++
+					}
+				}
+				res.total += n - 1
+				res.deferred += n
+				logger.System("机器人消息触发限流，已延后投递", "taskID", task.ID, "groupID", groupID, "messages", len(rest), "retryAfter", limited.RetryAfter.String())
+				break
+			}
+			rec.RecordDelivery(groupID, msgID, msgIDs, time.Since(sendStart), err)
+			if err != nil {
+				logger.Error("机器人消息发送失败", "taskID", task.ID, "groupID", groupID, "messageID", msg.ID, "error", err)
+				fail(groupID, msgID, err)
+				continue
+			}
+			res.sent++
+			logger.System("机器人消息发送成功", "taskID", task.ID, "groupID", groupID, "messageID", msg.ID, "tgMessageIDs", msgIDs)
+		}
+	}
+	return res
+}
+
+// permanentDeliveryError 重试也不会成功的投递错误：未配置token、文件不存在，或 Bot API 返回 4xx（429 限流除外）
+func permanentDeliveryError(err error) bool {
+	if errors.Is(err, errNoBotToken) || errors.Is(err, errFileNotFound) {
+		return true
+	}
+	var apiErr *telegram.APIError
+	return errors.As(err, &apiErr) && apiErr.Permanent()
+}
+
+// retryFailedDeliveries 将本次运行（run）失败的投递作为第 attempt 次重试，按任务退避策略延后以补发任务重新入队，
+// 重试次数为原任务剩余的次数
+func (b *BotMsgHandler) retryFailedDeliveries(task *model.Task, run *JobPayload, targets []DeliveryTarget, attempt, maxRetry int) (*asynq.TaskInfo, error) {
+	payload := NewJobPayload(MsgTypeRetryFailed, task.ID, nil)
+	payload.Targets = targets
+	payload.Deferrals = run.Deferrals
+	payload.Origin = run.RunOrigin()
+	payload.Attempt = attempt
+	return b.jobService.EnqueuePayload(task, payload, asynq.ProcessIn(task.RetryDelay(attempt)), asynq.MaxRetry(maxRetry))
+}
+
+// deferDeliveries 将某个群组剩余的投递在 delay 后以补发任务（source）重新入队
+func (b *BotMsgHandler) deferDeliveries(task *model.Task, source string, deferrals int, target DeliveryTarget, delay time.Duration) error {
+	payload := NewJobPayload(source, task.ID, nil)
+	payload.Targets = []DeliveryTarget{target}
+	payload.Deferrals = deferrals
+	_, err := b.jobService.EnqueuePayload(task, payload, asynq.ProcessIn(delay))
+	return err
+}
+
+// holdForSendWindow 按静默期策略处理群组本次的投递：defer 延后到下一个可发送时间，skip 或无可发送时间时跳过
+// 返回处理的投递数与记录的明细状态；延后入队失败时按失败记录
+func (b *BotMsgHandler) holdForSendWindow(rec *ExecutionRecorder, task *model.Task, target DeliveryTarget, window *model.SendWindow, byID map[uint64]model.Message) (int, int, error) {
+	ids := make([]uint64, 0, len(target.MessageIDs))
+	for _, id := range target.MessageIDs {
+		if _, ok := byID[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+
+	resumeAt, ok := window.NextAllowed(time.Now())
+	if window.Policy == model.QuietPolicySkip || !ok {
+		for _, id := range ids {
+			rec.RecordSkipped(target.GroupID, id, "群组处于静默期，已跳过本次投递")
+		}
+		logger.System("群组处于静默期，已跳过投递", "taskID", task.ID, "groupID", target.GroupID, "messages", len(ids))
+		return len(ids), model.DeliveryStatusSkipped, nil
+	}
+
+	held := DeliveryTarget{GroupID: target.GroupID, MessageIDs: ids}
+	if len(ids) > 0 && len(target.MessageIDs) > 0 && ids[0] == target.MessageIDs[0] {
+		// 限流补发进入静默期：保留首条消息已发送的分段
+		held.SentChunks = target.SentChunks
+	}
+	if err := b.deferDeliveries(task, MsgTypeSendWindow, 0, held, time.Until(resumeAt)); err != nil {
+		for _, id := range ids {
+			rec.RecordDelivery(target.GroupID, id, nil, 0, err)
+		}
+		return len(ids), model.DeliveryStatusFailed, err
+	}
+	reason := fmt.Sprintf("群组处于静默期，延后至%s投递", resumeAt.In(model.LoadLocation(window.Timezone)).Format("2006-01-02 15:04"))
+	for _, id := range ids {
+		rec.RecordDeferred(target.GroupID, id, reason)
+	}
+	logger.System("群组处于静默期，已延后投递", "taskID", task.ID, "groupID", target.GroupID, "messages", len(ids), "resumeAt", resumeAt)
+	return len(ids), model.DeliveryStatusDeferred, nil
+}
+
+// loadMessages 按任务中的顺序加载未删除的消息
+func (b *BotMsgHandler) loadMessages(ids []uint64) ([]model.Message, error) {
+	var list []model.Message
+	if err := b.db.Where("id IN ? AND status = 0", ids).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint64]model.Message, len(list))
+	for _, m := range list {
+		byID[uint64(m.ID)] = m
+	}
+	messages := make([]model.Message, 0, len(list))
+	for _, id := range ids {
+		if m, ok := byID[id]; ok {
+			messages = append(messages, m)
+		}
+	}
+	return messages, nil
+}
+
+// loadBotConfigs 读取群组对应的机器人token与发送时段
+func (b *BotMsgHandler) loadBotConfigs(groupIDs []int64) (map[int64]string, map[int64]*model.SendWindow, error) {
+	var configs []model.BotConfig
+	if err := b.db.Where("group_id IN ?", groupIDs).Find(&configs).Error; err != nil {
+		return nil, nil, err
+	}
+	tokens := make(map[int64]string, len(configs))
+	windows := make(map[int64]*model.SendWindow)
+	for _, c := range configs {
+		if len(c.SendWindow) > 0 && string(c.SendWindow) != "null" {
+			var w model.SendWindow
+			if err := json.Unmarshal(c.SendWindow, &w); err != nil {
+				logger.Error("解析发送时段失败，按不限制处理", "groupID", c.GroupID, "error", err)
+			} else {
+				windows[c.GroupID] = &w
+			}
+		}
+		var data dto.BotConfigData
+		if err := json.Unmarshal(c.Config, &data); err != nil {
+			logger.Error("解析机器人配置失败", "groupID", c.GroupID, "error", err)
+			continue
+		}
+		if strings.TrimSpace(data.Token) != "" {
+			tokens[c.GroupID] = data.Token
+		}
+	}
+	return tokens, windows, nil
+}
+
+// sendToChat 将一条消息（文本+图片+视频）分段发送到指定群组：纯文本为1段；含媒体时每个媒体组（或单个媒体）为1段，
+// caption 放不下的文本最后单独1段。跳过前 skip 段（限流前已发送的部分），返回Telegram消息ID与已完成的分段数
+// 每次 Bot API 调用均经过限流器，媒体组按媒体数量扣减配额；触发限流且无法原地等待时返回 *RateLimitedError
+func (b *BotMsgHandler) sendToChat(ctx context.Context, token string, chatID int64, msg model.Message, skip int) ([]int64, int, error) {
+	content := strings.TrimSpace(msg.Content)
+
+	medias := make([]telegram.InputMedia, 0, len(msg.Images)+len(msg.Medias))
+	for _, f := range msg.Images {
+		file, err := b.readFile(f)
+		if err != nil {
+			return nil, skip, err
+		}
+		medias = append(medias, telegram.InputMedia{Type: "photo", File: file})
+	}
+	for _, f := range msg.Medias {
+		file, err := b.readFile(f)
+		if err != nil {
+			return nil, skip, err
+		}
+		medias = append(medias, telegram.InputMedia{Type: "video", File: file})
+	}
+
+	if len(medias) == 0 {
+		if content == "" {
+			return nil, skip, fmt.Errorf("消息%d内容为空", msg.ID)
+		}
+		if skip > 0 {
+			return nil, skip, nil
+		}
+		var m *telegram.Message
+		err := b.throttle.Call(ctx, token, chatID, 1, func() (err error) {
+			m, err = b.tg.SendMessage(ctx, token, chatID, content)
+			return err
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		return []int64{m.MessageID}, 1, nil
+	}
+
+	// 文本能放进caption时随首个媒体发送，否则单独补发
+	caption := ""
+	if utf8.RuneCountInString(content) <= captionMaxLen {
+		caption = content
+	}
 
-	// 在这里实现具体的消息处理逻辑
-	// 例如：发送消息到Telegram、处理回调等
+	sent := make([]int64, 0, len(medias)+1)
+	done := 0
+	for start := 0; start < len(medias); start += mediaGroupMaxSize {
+		if done < skip {
+			done++
+			continue
+		}
+		end := start + mediaGroupMaxSize
+		if end > len(medias) {
+			end = len(medias)
+		}
+		chunk := medias[start:end]
+		if start == 0 {
+			chunk[0].Caption = caption
+		}
+		if len(chunk) == 1 {
+			var m *telegram.Message
+			err := b.throttle.Call(ctx, token, chatID, 1, func() (err error) {
+				if chunk[0].Type == "video" {
+					m, err = b.tg.SendVideo(ctx, token, chatID, chunk[0].File, chunk[0].Caption)
+				} else {
+					m, err = b.tg.SendPhoto(ctx, token, chatID, chunk[0].File, chunk[0].Caption)
+				}
+				return err
+			})
+			if err != nil {
+				return sent, done, err
+			}
+			sent = append(sent, m.MessageID)
+			done++
+			continue
+		}
+		var msgs []telegram.Message
+		err := b.throttle.Call(ctx, token, chatID, len(chunk), func() (err error) {
+			msgs, err = b.tg.SendMediaGroup(ctx, token, chatID, chunk)
+			return err
+		})
+		if err != nil {
+			return sent, done, err
+		}
+		for _, m := range msgs {
+			sent = append(sent, m.MessageID)
+		}
+		done++
+	}
 
-	return nil
+	if caption == "" && content != "" && done >= skip {
+		var m *telegram.Message
+		err := b.throttle.Call(ctx, token, chatID, 1, func() (err error) {
+			m, err = b.tg.SendMessage(ctx, token, chatID, content)
+			return err
+		})
+		if err != nil {
+			return sent, done, err
+		}
+		sent = append(sent, m.MessageID)
+		done++
+	}
+	return sent, done, nil
+}
+
+// readFile 通过文件服务读取消息引用的文件
+func (b *BotMsgHandler) readFile(f model.FileObject) (telegram.InputFile, error) {
+	if f.FileID == nil || *f.FileID == "" {
+		return telegram.InputFile{}, errors.New("文件ID为空")
+	}
+	fileID := *f.FileID
+	content, name, err := b.files.GetFileContent(fileID)
+	if errors.Is(err, os.ErrNotExist) {
+		return telegram.InputFile{}, fmt.Errorf("%w: %s", errFileNotFound, fileID)
+	}
+	if err != nil {
+		return telegram.InputFile{}, fmt.Errorf("读取文件%s失败: %w", fileID, err)
+	}
+	if f.FileName != "" {
+		name = f.FileName
+	}
+	return telegram.InputFile{FileName: name, Content: content}, nil
 }
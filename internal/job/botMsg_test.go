@@ -0,0 +1,169 @@
+package job
+
+import (
+	"app/internal/model"
+	"app/tools/telegram"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tgStub 本地 Bot API 桩服务：按 chat_id 返回预设的错误，记录每次 sendMessage 的群组
+type tgStub struct {
+	mu     sync.Mutex
+	fails  map[string]string // chat_id -> 错误响应
+	called []string
+}
+
+func (s *tgStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params map[string]string
+	_ = json.NewDecoder(r.Body).Decode(&params)
+	chatID := params["chat_id"]
+	s.mu.Lock()
+	s.called = append(s.called, chatID)
+	body, fail := s.fails[chatID]
+	s.mu.Unlock()
+	if fail {
+		var resp struct {
+			ErrorCode int `json:"error_code"`
+		}
+		_ = json.Unmarshal([]byte(body), &resp)
+		w.WriteHeader(resp.ErrorCode)
+		_, _ = w.Write([]byte(body))
+		return
+	}
+	_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+}
+
+// missingFiles 文件服务桩：所有文件均不存在
+type missingFiles struct{}
+
+func (missingFiles) GetFileContent(string) ([]byte, string, error) {
+	return nil, "", os.ErrNotExist
+}
+
+func newTestBotMsgHandler(t *testing.T, stub *tgStub) *BotMsgHandler {
+	t.Helper()
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+	// 限流器未连接Redis时不限流
+	return &BotMsgHandler{
+		tg:       telegram.NewClient(srv.URL, 5*time.Second),
+		throttle: newDeliveryThrottler(nil, 0, 0, 0),
+		files:    missingFiles{},
+	}
+}
+
+func TestBotMsgHandlerDeliverPartialFailure(t *testing.T) {
+	stub := &tgStub{fails: map[string]string{
+		"-1002": `{"ok":false,"error_code":500,"description":"Internal Server Error"}`,
+		"-1003": `{"ok":false,"error_code":403,"description":"Forbidden: bot was kicked"}`,
+	}}
+	h := newTestBotMsgHandler(t, stub)
+	task := &model.Task{ID: 1}
+	byID := map[uint64]model.Message{1: {ID: 1, Content: "hello"}, 2: {ID: 2, Content: "world"}}
+	tokens := map[int64]string{-1001: "1:a", -1002: "1:a", -1003: "1:a"}
+	targets := []DeliveryTarget{
+		{GroupID: -1001, MessageIDs: []uint64{1, 2}},
+		{GroupID: -1002, MessageIDs: []uint64{1, 2}},
+		{GroupID: -1003, MessageIDs: []uint64{1}},
+		{GroupID: -1004, MessageIDs: []uint64{1}}, // 未配置token
+	}
+
+	res := h.deliver(context.Background(), nil, task, NewJobPayload(BotMsgType, task.ID, nil), targets, byID, tokens, nil)
+
+	if res.total != 6 || res.sent != 2 || len(res.failures) != 4 || res.permanent != 2 {
+		t.Errorf("total=%d sent=%d failures=%d permanent=%d, want 6/2/4/2", res.total, res.sent, len(res.failures), res.permanent)
+	}
+	// 只有可重试的失败（500）进入补发，403 与缺少token的不补发
+	want := []DeliveryTarget{{GroupID: -1002, MessageIDs: []uint64{1, 2}}}
+	if !reflect.DeepEqual(res.retry, want) {
+		t.Errorf("retry = %+v, want %+v", res.retry, want)
+	}
+	if res.retryAll() {
+		t.Error("已有成功投递时不应整体重试")
+	}
+}
+
+func TestBotMsgHandlerDeliverRetriesOnlyFailedChats(t *testing.T) {
+	stub := &tgStub{fails: map[string]string{
+		"-1002": `{"ok":false,"error_code":500,"description":"Internal Server Error"}`,
+	}}
+	h := newTestBotMsgHandler(t, stub)
+	task := &model.Task{ID: 1}
+	byID := map[uint64]model.Message{1: {ID: 1, Content: "hello"}}
+	tokens := map[int64]string{-1001: "1:a", -1002: "1:a"}
+	targets := []DeliveryTarget{
+		{GroupID: -1001, MessageIDs: []uint64{1}},
+		{GroupID: -1002, MessageIDs: []uint64{1}},
+	}
+
+	first := h.deliver(context.Background(), nil, task, NewJobPayload(BotMsgType, task.ID, nil), targets, byID, tokens, nil)
+	if first.sent != 1 || len(first.retry) != 1 {
+		t.Fatalf("首次投递 sent=%d retry=%+v", first.sent, first.retry)
+	}
+
+	// 补发运行只投递失败的群组
+	stub.mu.Lock()
+	stub.fails = nil
+	stub.called = nil
+	stub.mu.Unlock()
+	run := NewJobPayload(MsgTypeRetryFailed, task.ID, nil)
+	run.Targets = first.retry
+	second := h.deliver(context.Background(), nil, task, run, run.Targets, byID, tokens, nil)
+
+	if second.sent != 1 || len(second.failures) != 0 {
+		t.Errorf("补发 sent=%d failures=%v", second.sent, second.failures)
+	}
+	if want := []string{"-1002"}; !reflect.DeepEqual(stub.called, want) {
+		t.Errorf("补发请求的群组 = %v, want %v", stub.called, want)
+	}
+}
+
+func TestBotMsgHandlerDeliverAllRetryable(t *testing.T) {
+	stub := &tgStub{fails: map[string]string{
+		"-1001": `{"ok":false,"error_code":500,"description":"Internal Server Error"}`,
+		"-1002": `{"ok":false,"error_code":502,"description":"Bad Gateway"}`,
+	}}
+	h := newTestBotMsgHandler(t, stub)
+	task := &model.Task{ID: 1}
+	byID := map[uint64]model.Message{1: {ID: 1, Content: "hello"}}
+	tokens := map[int64]string{-1001: "1:a", -1002: "1:a"}
+	targets := []DeliveryTarget{
+		{GroupID: -1001, MessageIDs: []uint64{1}},
+		{GroupID: -1002, MessageIDs: []uint64{1}},
+	}
+
+	res := h.deliver(context.Background(), nil, task, NewJobPayload(BotMsgType, task.ID, nil), targets, byID, tokens, nil)
+
+	// 没有已完成的投递：整体重试不会重复发送
+	if !res.retryAll() || len(res.retry) != 2 {
+		t.Errorf("retryAll=%v retry=%+v", res.retryAll(), res.retry)
+	}
+}
+
+func TestBotMsgHandlerDeliverMissingFile(t *testing.T) {
+	stub := &tgStub{}
+	h := newTestBotMsgHandler(t, stub)
+	task := &model.Task{ID: 1}
+	fileID := "deleted"
+	byID := map[uint64]model.Message{
+		1: {ID: 1, Content: "hello"},
+		2: {ID: 2, Content: "photo", Images: model.JSONFileSlice{{FileID: &fileID}}},
+	}
+	tokens := map[int64]string{-1001: "1:a"}
+	targets := []DeliveryTarget{{GroupID: -1001, MessageIDs: []uint64{1, 2}}}
+
+	res := h.deliver(context.Background(), nil, task, NewJobPayload(BotMsgType, task.ID, nil), targets, byID, tokens, nil)
+
+	// 文件已删除：按永久失败处理，不进入补发
+	if res.sent != 1 || res.permanent != 1 || len(res.retry) != 0 {
+		t.Errorf("sent=%d permanent=%d retry=%+v, want 1/1/[]", res.sent, res.permanent, res.retry)
+	}
+}
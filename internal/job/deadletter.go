@@ -0,0 +1,92 @@
+package job
+
+import (
+	"app/internal/model"
+	"app/tools/logger"
+	"errors"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+)
+
+// ListArchivedByDBTaskID 列出DB任务在所有队列中的归档（重试耗尽）任务
+func (ts *JobService) ListArchivedByDBTaskID(dbTaskID uint64) ([]*asynq.TaskInfo, error) {
+	inspector := ts.NewInspector()
+	defer inspector.Close()
+
+	queues, err := inspector.Queues()
+	if err != nil {
+		return nil, err
+	}
+	var list []*asynq.TaskInfo
+	for _, queue := range queues {
+		for page := 1; ; page++ {
+			items, err := inspector.ListArchivedTasks(queue, asynq.Page(page), asynq.PageSize(100))
+			if err != nil {
+				return nil, fmt.Errorf("扫描归档队列%s失败: %w", queue, err)
+			}
+			for _, ti := range items {
+				if ti.Type == BotMsgType && payloadTaskID(ti.Payload) == dbTaskID {
+					list = append(list, ti)
+				}
+			}
+			if len(items) < 100 {
+				break
+			}
+		}
+	}
+	return list, nil
+}
+
+// ArchiveNotDeletedError 归档任务已重新入队，但原归档任务删除失败：原任务仍在归档中，再次重新执行会重复投递
+type ArchiveNotDeletedError struct {
+	Err error
+}
+
+func (e *ArchiveNotDeletedError) Error() string {
+	return fmt.Sprintf("已重新入队，但删除原归档任务失败（请手动删除，避免再次重新执行时重复投递）: %v", e.Err)
+}
+
+func (e *ArchiveNotDeletedError) Unwrap() error {
+	return e.Err
+}
+
+// RequeueArchived 重新执行归档任务：以新的运行ID和任务当前的重试上限重新入队（重试次数重新计算），入队成功后再删除归档任务。
+// 不使用 asynq 的 RunTask：RunTask 沿用原任务ID与已耗尽的重试次数，也无法替换 payload
+// 入队失败时归档任务保持不变；删除归档任务失败时返回 *ArchiveNotDeletedError（此时已入队，调用方不应按失败重试）
+//   - payload 为空：沿用归档任务的原 payload（旧版 payload 先转换）
+//   - payload 非空：使用新 payload
+func (ts *JobService) RequeueArchived(t *model.Task, ti *asynq.TaskInfo, payload *JobPayload) error {
+	if payload == nil {
+		p, err := DecodeJobPayload(ti.Payload)
+		if errors.Is(err, ErrLegacyPayload) {
+			if legacy, ok := upgradeLegacyPayload(ti.Payload); ok {
+				p, err = legacy, nil
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("归档任务payload解析失败: %w", err)
+		}
+		payload = p
+	}
+	// 失败补发延续的重试次数一并清零
+	payload.Attempt = 0
+	if _, err := ts.EnqueuePayload(t, payload); err != nil {
+		return err
+	}
+
+	inspector := ts.NewInspector()
+	defer inspector.Close()
+	if err := inspector.DeleteTask(ti.Queue, ti.ID); err != nil {
+		logger.Error("归档任务已重新入队，删除原归档任务失败", "queue", ti.Queue, "id", ti.ID, "error", err)
+		return &ArchiveNotDeletedError{Err: err}
+	}
+	return nil
+}
+
+// DeleteArchived 删除归档任务
+func (ts *JobService) DeleteArchived(ti *asynq.TaskInfo) error {
+	inspector := ts.NewInspector()
+	defer inspector.Close()
+	return inspector.DeleteTask(ti.Queue, ti.ID)
+}
@@ -0,0 +1,201 @@
+package job
+
+import (
+	"app/internal/model"
+	"app/tools/logger"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+type executionRecorderKey struct{}
+
+// ExecutionRecorder 记录一次任务执行及其投递明细
+// 由 processTask 创建并放入 ctx，Handler 通过 ExecutionFromContext 获取
+type ExecutionRecorder struct {
+	db          *gorm.DB
+	executionID uint64
+	taskID      uint64
+	startedAt   time.Time
+
+	mu       sync.Mutex
+	total    int
+	success  int
+	failed   int
+	deferred int
+	skipped  int
+}
+
+// ExecutionFromContext 获取当前执行记录器；不存在时返回nil（nil接收者上的方法均为空操作）
+func ExecutionFromContext(ctx context.Context) *ExecutionRecorder {
+	rec, _ := ctx.Value(executionRecorderKey{}).(*ExecutionRecorder)
+	return rec
+}
+
+func withExecutionRecorder(ctx context.Context, rec *ExecutionRecorder) context.Context {
+	if rec == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, executionRecorderKey{}, rec)
+}
+
+// ExecutionID 当前执行记录ID
+func (r *ExecutionRecorder) ExecutionID() uint64 {
+	if r == nil {
+		return 0
+	}
+	return r.executionID
+}
+
+// RecordDelivery 写入一条投递明细
+func (r *ExecutionRecorder) RecordDelivery(groupID int64, messageID uint64, tgMessageIDs []int64, latency time.Duration, sendErr error) {
+	if r == nil {
+		return
+	}
+	d := model.TaskDelivery{
+		ExecutionID: r.executionID,
+		TaskID:      r.taskID,
+		GroupID:     groupID,
+		MessageID:   messageID,
+		Status:      model.DeliveryStatusSuccess,
+		LatencyMs:   latency.Milliseconds(),
+		CreateTime:  time.Now(),
+	}
+	if len(tgMessageIDs) > 0 {
+		d.TgMessageID = tgMessageIDs[0]
+		if ids, err := json.Marshal(tgMessageIDs); err == nil {
+			d.TgMessageIDs = model.JSON(ids)
+		}
+	}
+	if sendErr != nil {
+		d.Status = model.DeliveryStatusFailed
+		d.ErrorMessage = sendErr.Error()
+	}
+
+	r.mu.Lock()
+	r.total++
+	if sendErr != nil {
+		r.failed++
+	} else {
+		r.success++
+	}
+	r.mu.Unlock()
+
+	if err := r.db.Create(&d).Error; err != nil {
+		logger.Error("写入投递明细失败", "error", err, "executionID", r.executionID, "groupID", groupID, "messageID", messageID)
+	}
+}
+
+// RecordDeferred 写入一条延后投递（限流、静默期）的明细，不计为失败
+func (r *ExecutionRecorder) RecordDeferred(groupID int64, messageID uint64, reason string) {
+	r.recordHeld(groupID, messageID, model.DeliveryStatusDeferred, reason)
+}
+
+// RecordSkipped 写入一条因静默期跳过的投递明细，不计为失败
+func (r *ExecutionRecorder) RecordSkipped(groupID int64, messageID uint64, reason string) {
+	r.recordHeld(groupID, messageID, model.DeliveryStatusSkipped, reason)
+}
+
+// recordHeld 写入未实际发送的投递明细
+func (r *ExecutionRecorder) recordHeld(groupID int64, messageID uint64, status int, reason string) {
+	if r == nil {
+		return
+	}
+	d := model.TaskDelivery{
+		ExecutionID:  r.executionID,
+		TaskID:       r.taskID,
+		GroupID:      groupID,
+		MessageID:    messageID,
+		Status:       status,
+		ErrorMessage: reason,
+		CreateTime:   time.Now(),
+	}
+
+	r.mu.Lock()
+	r.total++
+	if status == model.DeliveryStatusSkipped {
+		r.skipped++
+	} else {
+		r.deferred++
+	}
+	r.mu.Unlock()
+
+	if err := r.db.Create(&d).Error; err != nil {
+		logger.Error("写入投递明细失败", "error", err, "executionID", r.executionID, "groupID", groupID, "messageID", messageID)
+	}
+}
+
+// startExecution 创建执行记录（状态：执行中）；重试次数计入 payload 中入队前已消耗的次数（失败补发、旧版迁移）
+func (ts *JobService) startExecution(ctx context.Context, dbTask *model.Task, env *JobPayload) *ExecutionRecorder {
+	if ts.db == nil || dbTask == nil || dbTask.ID == 0 {
+		return nil
+	}
+	now := time.Now()
+	exec := model.TaskExecution{
+		TaskID:     dbTask.ID,
+		AdminID:    dbTask.AdminID,
+		Source:     env.Type,
+		Attempt:    env.Attempt,
+		Status:     model.ExecutionStatusRunning,
+		StartedAt:  now,
+		CreateTime: now,
+	}
+	if id, ok := asynq.GetTaskID(ctx); ok {
+		exec.RunID = id
+	}
+	if n, ok := asynq.GetRetryCount(ctx); ok {
+		exec.Attempt += n
+	}
+	if err := ts.db.Create(&exec).Error; err != nil {
+		logger.Error("创建执行记录失败", "error", err, "taskID", dbTask.ID)
+		return nil
+	}
+	return &ExecutionRecorder{
+		db:          ts.db,
+		executionID: exec.ID,
+		taskID:      dbTask.ID,
+		startedAt:   now,
+	}
+}
+
+// finishExecution 根据投递结果与处理错误汇总执行记录
+func (ts *JobService) finishExecution(rec *ExecutionRecorder, execErr error) {
+	if rec == nil {
+		return
+	}
+	now := time.Now()
+	rec.mu.Lock()
+	total, success, failed, deferred, skipped := rec.total, rec.success, rec.failed, rec.deferred, rec.skipped
+	rec.mu.Unlock()
+
+	status := model.ExecutionStatusSuccess
+	switch {
+	case execErr != nil && success > 0:
+		status = model.ExecutionStatusPartial
+	case execErr != nil:
+		status = model.ExecutionStatusFailed
+	case failed > 0:
+		status = model.ExecutionStatusPartial
+	}
+	updates := map[string]interface{}{
+		"status":         status,
+		"total_count":    total,
+		"success_count":  success,
+		"failed_count":   failed,
+		"deferred_count": deferred,
+		"skipped_count":  skipped,
+		"finished_at":    &now,
+		"duration_ms":    now.Sub(rec.startedAt).Milliseconds(),
+	}
+	if execErr != nil {
+		updates["error_message"] = fmt.Sprintf("%v", execErr)
+	}
+	if err := ts.db.Model(&model.TaskExecution{}).Where("id = ?", rec.executionID).Updates(updates).Error; err != nil {
+		logger.Error("更新执行记录失败", "error", err, "executionID", rec.executionID)
+	}
+}
@@ -0,0 +1,184 @@
+package job
+
+import (
+	"app/internal/model"
+	toolsCron "app/tools/cron"
+	"app/tools/logger"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+// MsgTypeMisfire 错过执行后补执行的 payload type（执行记录来源）
+const MsgTypeMisfire = "misfire"
+
+// misfireGrace 超过该时长仍未执行才视为错过，避免把即将执行的任务误判
+const misfireGrace = time.Minute
+
+// 补执行之间的间隔，避免集中投递
+const catchUpInterval = 10 * time.Second
+
+// applyMisfirePolicies 启动（当选leader）时检查到点未执行的任务，按任务策略跳过或补执行，并写入执行记录
+// 每次leader切换都会执行：最近一次执行开始时间不早于应执行时间的任务说明该次已被执行（执行中或结果尚未回写），不视为错过
+func applyMisfirePolicies(db *gorm.DB, js *JobService) (skipped, fired int, err error) {
+	now := time.Now()
+	var tasks []model.Task
+	if err := db.Where("status IN ? AND next_execute_at IS NOT NULL AND next_execute_at < ? AND is_delete = 0", []int{0, 1, 3}, now.Add(-misfireGrace)).
+		Where("(last_executed_at IS NULL OR last_executed_at < next_execute_at)").
+		Find(&tasks).Error; err != nil {
+		return 0, 0, fmt.Errorf("查询错过执行的任务失败: %w", err)
+	}
+	for i := range tasks {
+		t := &tasks[i]
+		if t.TriggerType == model.TriggerTypeSchedule && t.Status != 0 {
+			// 一次性任务仅处理待执行的
+			continue
+		}
+		runs, err := js.handleMisfire(t, now)
+		if err != nil {
+			logger.Error("处理错过执行失败", "error", err, "taskID", t.ID)
+			continue
+		}
+		if runs == 0 {
+			skipped++
+		} else {
+			fired++
+		}
+	}
+	return skipped, fired, nil
+}
+
+// handleMisfire 处理单个任务，返回补执行次数
+func (ts *JobService) handleMisfire(t *model.Task, now time.Time) (int, error) {
+	missedAt := *t.NextExecuteAt
+	loc := t.Location()
+
+	// 周期任务已到期：不再补执行
+	if t.TriggerType == model.TriggerTypeCron && t.ExpireTime != nil && !now.Before(*t.ExpireTime) {
+		ts.recordMisfire(t, fmt.Sprintf("错过执行（%s），任务已到期，不补执行", missedAt.In(loc).Format("2006-01-02 15:04:05")))
+		ts.markExpiredAndCleanup(t.ID, "任务已到期")
+		return 0, nil
+	}
+
+	missed := 1
+	var next *time.Time
+	if t.TriggerType == model.TriggerTypeCron {
+		limit := t.MisfireCatchUpLimit
+		if limit <= 0 {
+			limit = 1
+		}
+		missed, next = countMissedRuns(t.CronExpression, missedAt.In(loc), now.In(loc), limit)
+	}
+
+	policy := t.MisfirePolicy
+	if policy == "" {
+		policy = model.MisfirePolicyFireOnce
+	}
+	runs := 0
+	switch policy {
+	case model.MisfirePolicySkip:
+	case model.MisfirePolicyFireOnce:
+		runs = 1
+	case model.MisfirePolicyCatchUp:
+		runs = missed
+		if runs > t.MisfireCatchUpLimit {
+			runs = t.MisfireCatchUpLimit
+		}
+		if runs > model.MisfireCatchUpMax {
+			runs = model.MisfireCatchUpMax
+		}
+	}
+
+	missedText := fmt.Sprintf("%d次", missed)
+	if t.TriggerType == model.TriggerTypeCron && missed > t.MisfireCatchUpLimit {
+		missedText = fmt.Sprintf("超过%d次", t.MisfireCatchUpLimit)
+	}
+	decision := fmt.Sprintf("停机期间错过执行%s（首次应执行于 %s），策略 %s：", missedText, missedAt.In(loc).Format("2006-01-02 15:04:05"), policy)
+
+	if t.TriggerType == model.TriggerTypeSchedule {
+		if runs == 0 {
+			// 一次性任务跳过：移除残留的队列任务并置为失败
+			_ = ts.DeleteScheduledByDBTaskID(t.ID)
+			reason := decision + "跳过"
+			ts.recordMisfire(t, reason)
+			return 0, ts.db.Model(&model.Task{}).Where("id = ?", t.ID).Updates(map[string]interface{}{
+				"status":          3,
+				"next_execute_at": nil,
+				"error_message":   reason,
+				"update_time":     now,
+			}).Error
+		}
+		// 补执行：按固定TaskID立即入队，若Redis中仍有该任务则由其执行
+		err := ts.EnqueueSchedule(t, now, MsgTypeMisfire)
+		if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+			return 0, fmt.Errorf("补执行入队失败: %w", err)
+		}
+		ts.recordMisfire(t, decision+"立即补执行")
+		return 1, nil
+	}
+
+	// 周期任务：先推进下一次执行时间，避免重复处理
+	if err := ts.db.Model(&model.Task{}).Where("id = ?", t.ID).Updates(map[string]interface{}{
+		"next_execute_at": next,
+		"update_time":     now,
+	}).Error; err != nil {
+		return 0, err
+	}
+	if runs == 0 {
+		ts.recordMisfire(t, decision+"跳过")
+		return 0, nil
+	}
+
+	enqueued := 0
+	for i := 0; i < runs; i++ {
+		if _, err := ts.EnqueueRun(t, MsgTypeMisfire, asynq.ProcessIn(time.Duration(i)*catchUpInterval)); err != nil {
+			logger.Error("补执行入队失败", "error", err, "taskID", t.ID)
+			continue
+		}
+		enqueued++
+	}
+	ts.recordMisfire(t, decision+fmt.Sprintf("补执行%d次", enqueued))
+	return enqueued, nil
+}
+
+// countMissedRuns 统计 [from, now] 内错过的执行次数（最多统计到 limit+1），并返回 now 之后的下一次执行时间
+func countMissedRuns(cronExpr string, from, now time.Time, limit int) (int, *time.Time) {
+	cu := toolsCron.NewCronUtils()
+	missed := 1
+	cursor := from
+	for missed <= limit {
+		n, err := cu.CalculateNextExecution(cronExpr, cursor)
+		if err != nil || n.After(now) {
+			break
+		}
+		missed++
+		cursor = *n
+	}
+	next, err := cu.CalculateNextExecution(cronExpr, now)
+	if err != nil {
+		return missed, nil
+	}
+	return missed, next
+}
+
+// recordMisfire 写入一条“错过执行”的执行记录
+func (ts *JobService) recordMisfire(t *model.Task, decision string) {
+	now := time.Now()
+	exec := model.TaskExecution{
+		TaskID:       t.ID,
+		AdminID:      t.AdminID,
+		Source:       MsgTypeMisfire,
+		Status:       model.ExecutionStatusMissed,
+		ErrorMessage: decision,
+		StartedAt:    now,
+		FinishedAt:   &now,
+		CreateTime:   now,
+	}
+	if err := ts.db.Create(&exec).Error; err != nil {
+		logger.Error("写入错过执行记录失败", "error", err, "taskID", t.ID)
+	}
+	logger.System("错过执行处理", "taskID", t.ID, "decision", decision)
+}
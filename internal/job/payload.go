@@ -0,0 +1,171 @@
+package job
+
+import (
+	"app/internal/model"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// PayloadVersion 当前 payload 结构版本
+const PayloadVersion = 2
+
+// ErrLegacyPayload 非当前版本的 payload（需经迁移转换）
+var ErrLegacyPayload = errors.New("payload版本不匹配")
+
+// JobPayload 所有异步任务统一的 payload 结构
+//   - Type: 执行来源（bot_msg、cron_restore、manual_trigger、misfire …），写入执行记录
+//   - RunID: 单次运行ID，与 asynq TaskID 一致；周期条目每次触发的ID由 asynq 生成，此处为空
+//   - ExpireTime: RFC3339，仅周期任务使用
+//   - Targets/Deferrals: 限流补发时仅投递的群组与消息，以及已延后的次数
+//   - Origin: 失败补发所属原运行的来源，决定补发结束后是否回写任务状态
+//   - Attempt: 本次入队前已消耗的重试次数（失败补发延续原运行、旧版payload迁移保留），计入重试计数与退避
+//   - Ref: 系统任务关联的记录ID（告警等），此时 TaskID 为 0
+//   - Retry: 入队时任务的重试退避策略，asynq 重试时据此计算间隔，无需查询DB
+type JobPayload struct {
+	Type       string           `json:"type"`
+	Version    int              `json:"v"`
+	TaskID     uint64           `json:"taskId"`
+	RunID      string           `json:"runId,omitempty"`
+	ExpireTime string           `json:"expireTime,omitempty"`
+	Targets    []DeliveryTarget `json:"targets,omitempty"`
+	Deferrals  int              `json:"deferrals,omitempty"`
+	Origin     string           `json:"origin,omitempty"`
+	Attempt    int              `json:"attempt,omitempty"`
+	Ref        uint64           `json:"ref,omitempty"`
+	Retry      *RetryPolicy     `json:"retry,omitempty"`
+}
+
+// RetryPolicy 任务重试退避策略快照
+type RetryPolicy struct {
+	Policy      string `json:"policy"`
+	BaseSeconds int    `json:"base,omitempty"`
+	MaxSeconds  int    `json:"max,omitempty"`
+}
+
+// DeliveryTarget 某个群组待投递的消息；SentChunks 为首条消息限流前已发送的分段数，补发时跳过
+type DeliveryTarget struct {
+	GroupID    int64    `json:"groupId"`
+	MessageIDs []uint64 `json:"messageIds"`
+	SentChunks int      `json:"sentChunks,omitempty"`
+}
+
+// NewJobPayload 创建当前版本的 payload
+func NewJobPayload(source string, taskID uint64, expireTime *time.Time) *JobPayload {
+	p := &JobPayload{Type: source, Version: PayloadVersion, TaskID: taskID}
+	if expireTime != nil {
+		p.ExpireTime = expireTime.Format(time.RFC3339)
+	}
+	return p
+}
+
+// WithRunID 设置运行ID
+func (p *JobPayload) WithRunID(runID string) *JobPayload {
+	p.RunID = runID
+	return p
+}
+
+// WithRetry 记录任务当前的重试退避策略
+func (p *JobPayload) WithRetry(t *model.Task) *JobPayload {
+	p.Retry = &RetryPolicy{Policy: t.RetryPolicy, BaseSeconds: t.RetryBaseSeconds, MaxSeconds: t.RetryMaxSeconds}
+	return p
+}
+
+// RetryDelay 第 n 次重试（从0开始，与 asynq RetryDelayFunc 一致）前的等待时长，计入入队前已消耗的重试次数；
+// 未记录策略时按默认策略计算
+func (p *JobPayload) RetryDelay(n int) time.Duration {
+	t := model.Task{}
+	if p.Retry != nil {
+		t.RetryPolicy, t.RetryBaseSeconds, t.RetryMaxSeconds = p.Retry.Policy, p.Retry.BaseSeconds, p.Retry.MaxSeconds
+	}
+	return t.RetryDelay(p.Attempt + n + 1)
+}
+
+// Encode 序列化为入队用的字符串
+func (p *JobPayload) Encode() string {
+	data, _ := json.Marshal(p)
+	return string(data)
+}
+
+// RunOrigin 本次运行所属原运行的来源：失败补发沿用原运行的来源，其他为自身来源
+func (p *JobPayload) RunOrigin() string {
+	if p.Type == MsgTypeRetryFailed && p.Origin != "" {
+		return p.Origin
+	}
+	return p.Type
+}
+
+// Expire 解析到期时间，未设置时返回 nil
+func (p *JobPayload) Expire() *time.Time {
+	if strings.TrimSpace(p.ExpireTime) == "" {
+		return nil
+	}
+	if t, err := time.Parse(time.RFC3339, p.ExpireTime); err == nil {
+		return &t
+	}
+	return nil
+}
+
+// DecodeJobPayload 解析当前版本的 payload；其他版本返回 ErrLegacyPayload
+func DecodeJobPayload(data []byte) (*JobPayload, error) {
+	var p JobPayload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("payload反序列化失败: %w", err)
+	}
+	if p.Version != PayloadVersion {
+		return nil, ErrLegacyPayload
+	}
+	return &p, nil
+}
+
+// payloadTaskID 当前版本 payload 关联的DB任务ID，无法解析时为0
+func payloadTaskID(data []byte) uint64 {
+	p, err := DecodeJobPayload(data)
+	if err != nil {
+		return 0
+	}
+	return p.TaskID
+}
+
+// newRunID 生成一次性入队的运行ID（同时作为 asynq TaskID）
+func newRunID(source string, taskID uint64) string {
+	return fmt.Sprintf("%s:%d:%d", source, taskID, time.Now().UnixNano())
+}
+
+// scheduleRunID 一次性定时任务固定的运行ID
+func scheduleRunID(taskID uint64) string {
+	return fmt.Sprintf("schedule:%d", taskID)
+}
+
+// EnqueueSchedule 按固定TaskID（schedule:<id>）入队一次性任务；已存在（含payload迁移后的任务）时返回 asynq.ErrTaskIDConflict
+func (ts *JobService) EnqueueSchedule(t *model.Task, processAt time.Time, source string) error {
+	inspector := ts.NewInspector()
+	queued := migratedScheduleQueued(inspector, ts.queueNames(inspector), t.ID)
+	inspector.Close()
+	if queued {
+		return asynq.ErrTaskIDConflict
+	}
+	runID := scheduleRunID(t.ID)
+	payload := NewJobPayload(source, t.ID, nil).WithRunID(runID).WithRetry(t).Encode()
+	_, err := ts.ScheduleTaskWithID(BotMsgType, payload, processAt, runID, asynq.Queue(ts.QueueFor(t.Priority)), asynq.MaxRetry(t.MaxRetryCount))
+	return err
+}
+
+// EnqueuePayload 以指定 payload 入队DB任务的一次运行（限流补发等），运行ID按 payload.Type 生成
+func (ts *JobService) EnqueuePayload(t *model.Task, payload *JobPayload, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	runID := newRunID(payload.Type, t.ID)
+	opts = append([]asynq.Option{asynq.TaskID(runID), asynq.Queue(ts.QueueFor(t.Priority)), asynq.MaxRetry(t.MaxRetryCount)}, opts...)
+	return ts.EnqueueTask(BotMsgType, payload.WithRunID(runID).WithRetry(t).Encode(), opts...)
+}
+
+// EnqueueRun 立即入队DB任务的一次运行（手动触发等）
+func (ts *JobService) EnqueueRun(t *model.Task, source string, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	runID := newRunID(source, t.ID)
+	payload := NewJobPayload(source, t.ID, t.ExpireTime).WithRunID(runID).WithRetry(t).Encode()
+	opts = append([]asynq.Option{asynq.TaskID(runID), asynq.Queue(ts.QueueFor(t.Priority)), asynq.MaxRetry(t.MaxRetryCount)}, opts...)
+	return ts.EnqueueTask(BotMsgType, payload, opts...)
+}
@@ -0,0 +1,212 @@
+package job
+
+import (
+	"app/tools/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// 迁移完成标记（全局一次）
+const payloadMigratedKey = "job:payload:migrated:v2"
+
+var legacyTaskIDPattern = regexp.MustCompile(`任务ID[：:]\s*(\d+)`)
+
+// legacyPayload 旧版 payload：{msg_type, content, taskId|task_id, expireTime}
+type legacyPayload struct {
+	MsgType    string `json:"msg_type"`
+	Content    string `json:"content"`
+	TaskID     uint64 `json:"taskId"`
+	TaskIDOld  uint64 `json:"task_id"`
+	ExpireTime string `json:"expireTime"`
+}
+
+// upgradeLegacyPayload 将旧版 payload 转换为当前版本；无法关联DB任务时返回 false
+// 仅用于迁移与迁移完成前仍在途的任务
+func upgradeLegacyPayload(data []byte) (*JobPayload, bool) {
+	var old legacyPayload
+	if err := json.Unmarshal(data, &old); err != nil {
+		return nil, false
+	}
+	taskID := old.TaskID
+	if taskID == 0 {
+		taskID = old.TaskIDOld
+	}
+	if taskID == 0 {
+		if m := legacyTaskIDPattern.FindStringSubmatch(old.Content); len(m) == 2 {
+			_, _ = fmt.Sscanf(m[1], "%d", &taskID)
+		}
+	}
+	if taskID == 0 {
+		return nil, false
+	}
+	source := old.MsgType
+	if source == "" {
+		source = BotMsgType
+	}
+	p := &JobPayload{Type: source, Version: PayloadVersion, TaskID: taskID}
+	if s := strings.TrimSpace(old.ExpireTime); s != "" {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			p.ExpireTime = t.Format(time.RFC3339)
+		} else if t, err := time.ParseInLocation("2006-01-02 15:04:05", s, time.Local); err == nil {
+			p.ExpireTime = t.Format(time.RFC3339)
+		}
+	}
+	return p, true
+}
+
+// migrateLegacyPayloads 将Redis中旧版 payload 的队列任务改写为当前版本（全局只执行一次）
+// asynq 不支持修改 payload，队列任务以新ID重新入队后再删除原任务；归档任务重新入队并归档后再删除原任务
+// 周期条目不在此迁移：条目属于各实例内存中的 asynq.Scheduler，无法替换其他进程的条目，
+// 当选后 restoreTasks 会按DB重新注册全部周期任务。因此升级前必须先停掉所有旧版实例，
+// 否则旧实例的条目会与新leader的条目同时触发，导致重复发送
+func (ts *JobService) migrateLegacyPayloads() {
+	ctx := context.Background()
+	if n, err := ts.rdb.Exists(ctx, payloadMigratedKey).Result(); err == nil && n > 0 {
+		return
+	}
+	inspector := ts.NewInspector()
+	defer inspector.Close()
+
+	tasks, failed := 0, 0
+	queues, err := inspector.Queues()
+	if err != nil {
+		logger.Error("payload迁移：读取队列失败", "error", err)
+		return
+	}
+	for _, queue := range queues {
+		for _, state := range []struct {
+			name string
+			list func(string, ...asynq.ListOption) ([]*asynq.TaskInfo, error)
+		}{
+			{"pending", inspector.ListPendingTasks},
+			{"scheduled", inspector.ListScheduledTasks},
+			{"retry", inspector.ListRetryTasks},
+			{"archived", inspector.ListArchivedTasks},
+		} {
+			n, f := ts.migrateLegacyTasks(inspector, queue, state.name, state.list)
+			tasks += n
+			failed += f
+		}
+	}
+	if failed > 0 {
+		// 保留未完成标记，下次当选时重试
+		logger.Error("payload迁移部分失败，下次当选时重试", "tasks", tasks, "failed", failed)
+		return
+	}
+	ts.rdb.Set(ctx, payloadMigratedKey, time.Now().Format(time.RFC3339), 0)
+	logger.System("payload迁移完成", "tasks", tasks)
+}
+
+// migrateLegacyTasks 改写某一状态下的旧版队列任务
+func (ts *JobService) migrateLegacyTasks(inspector *asynq.Inspector, queue, state string, list func(string, ...asynq.ListOption) ([]*asynq.TaskInfo, error)) (migrated, failed int) {
+	// 改写会改变列表顺序，先收集再处理
+	var legacy []*asynq.TaskInfo
+	for page := 1; ; page++ {
+		items, err := list(queue, asynq.Page(page), asynq.PageSize(100))
+		if err != nil {
+			logger.Error("payload迁移：扫描队列失败", "error", err, "queue", queue, "state", state)
+			return 0, 1
+		}
+		for _, ti := range items {
+			if ti.Type != BotMsgType {
+				continue
+			}
+			if _, err := DecodeJobPayload(ti.Payload); err != nil {
+				legacy = append(legacy, ti)
+			}
+		}
+		if len(items) < 100 {
+			break
+		}
+	}
+
+	for _, ti := range legacy {
+		p, ok := upgradeLegacyPayload(ti.Payload)
+		if !ok {
+			logger.Error("payload迁移：无法识别的队列任务，已删除", "queue", queue, "id", ti.ID, "payload", string(ti.Payload))
+			_ = inspector.DeleteTask(queue, ti.ID)
+			continue
+		}
+		// 以新ID入队成功后再删除旧任务，任一步失败时旧任务仍保留，下次当选时重试
+		newID := migratedRunID(ti.ID)
+		p.RunID = newID
+		// 保留已重试次数：asynq 入队时无法设置，记入 payload 并只给剩余的重试次数
+		p.Attempt = ti.Retried
+		maxRetry := ti.MaxRetry - ti.Retried
+		if maxRetry < 0 {
+			maxRetry = 0
+		}
+		opts := []asynq.Option{asynq.Queue(queue), asynq.TaskID(newID), asynq.MaxRetry(maxRetry)}
+		switch state {
+		case "scheduled", "retry":
+			opts = append(opts, asynq.ProcessAt(ti.NextProcessAt))
+		case "archived":
+			// 先以远期定时入队，再归档
+			opts = append(opts, asynq.ProcessIn(24*time.Hour))
+		}
+		_, err := ts.client.Enqueue(asynq.NewTask(ti.Type, []byte(p.Encode())), opts...)
+		switch {
+		case errors.Is(err, asynq.ErrTaskIDConflict):
+			// 上次迁移已入队新任务但未删除旧任务
+		case err != nil:
+			logger.Error("payload迁移：新任务入队失败", "error", err, "queue", queue, "id", ti.ID, "taskID", p.TaskID)
+			failed++
+			continue
+		}
+		if state == "archived" {
+			if err := archiveMigrated(inspector, queue, newID); err != nil {
+				// 归档失败的新任务会在24小时后执行，删除后保留旧任务
+				logger.Error("payload迁移：新任务归档失败", "error", err, "queue", queue, "id", newID)
+				if err := inspector.DeleteTask(queue, newID); err != nil {
+					logger.Error("payload迁移：删除未归档的新任务失败", "error", err, "queue", queue, "id", newID)
+				}
+				failed++
+				continue
+			}
+		}
+		if err := inspector.DeleteTask(queue, ti.ID); err != nil && !errors.Is(err, asynq.ErrTaskNotFound) {
+			logger.Error("payload迁移：删除旧任务失败", "error", err, "queue", queue, "id", ti.ID)
+			failed++
+			continue
+		}
+		migrated++
+	}
+	return migrated, failed
+}
+
+// migratedRunID 迁移后队列任务的ID；固定且与原ID不同，迁移中断后重跑时按ID冲突识别已入队的新任务
+func migratedRunID(id string) string {
+	return id + ":v2"
+}
+
+// archiveMigrated 归档迁移入队的新任务，已归档时直接返回
+func archiveMigrated(inspector *asynq.Inspector, queue, id string) error {
+	info, err := inspector.GetTaskInfo(queue, id)
+	if err != nil {
+		return err
+	}
+	if info.State == asynq.TaskStateArchived {
+		return nil
+	}
+	return inspector.ArchiveTask(queue, id)
+}
+
+// migratedScheduleQueued 一次性任务迁移后的队列任务（schedule:<id>:v2）是否仍在等待或执行
+// 迁移后的任务不再占用固定TaskID，按固定TaskID入队前需检查，避免重复执行
+func migratedScheduleQueued(inspector *asynq.Inspector, queues []string, taskID uint64) bool {
+	id := migratedRunID(scheduleRunID(taskID))
+	for _, queue := range queues {
+		info, err := inspector.GetTaskInfo(queue, id)
+		if err == nil && info.State != asynq.TaskStateCompleted && info.State != asynq.TaskStateArchived {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package job
+
+import (
+	"app/internal/model"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+func TestJobServiceRetryDelay(t *testing.T) {
+	task := &model.Task{ID: 1, RetryPolicy: model.RetryPolicyLinear, RetryBaseSeconds: 10, RetryMaxSeconds: 60}
+	retried := NewJobPayload(MsgTypeRetryFailed, task.ID, nil).WithRetry(task)
+	retried.Attempt = 2
+	tests := []struct {
+		name    string
+		payload string
+		n       int
+		want    time.Duration
+	}{
+		{"按payload中的策略", NewJobPayload(BotMsgType, task.ID, nil).WithRetry(task).Encode(), 0, 10 * time.Second},
+		{"第3次重试", NewJobPayload(BotMsgType, task.ID, nil).WithRetry(task).Encode(), 2, 30 * time.Second},
+		{"失败补发延续已消耗的重试次数", retried.Encode(), 0, 30 * time.Second},
+		{"不超过上限", NewJobPayload(BotMsgType, task.ID, nil).WithRetry(task).Encode(), 20, 60 * time.Second},
+		{"未记录策略时按默认指数退避", NewJobPayload(BotMsgType, task.ID, nil).Encode(), 1, 2 * model.DefaultRetryBaseSeconds * time.Second},
+	}
+	// 不连接DB：间隔只由 payload 决定
+	ts := &JobService{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ts.retryDelay(tt.n, nil, asynq.NewTask(BotMsgType, []byte(tt.payload))); got != tt.want {
+				t.Errorf("retryDelay(%d) = %v, want %v", tt.n, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package job
+
+import (
+	"app/internal/model"
+	toolsCron "app/tools/cron"
+	"app/tools/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// ReconcileType 对账任务类型
+const ReconcileType = "reconcile"
+
+// 每5分钟对账一次
+const reconcileSpec = "*/5 * * * *"
+
+// 最近一次对账报告（多实例共享）
+const reconcileReportKey = "job:reconcile:last-report"
+
+// 执行中的一次性任务超过该时长仍无队列任务，视为执行中断
+const reconcileStuckAfter = 30 * time.Minute
+
+// ReconcileAction 对账中执行的一项修正
+type ReconcileAction struct {
+	TaskID uint64 `json:"taskId"`
+	Kind   string `json:"kind"` // orphan_entry / orphan_scheduled / missing_entry / missing_scheduled / stale_next / expired / lost / stuck
+	Detail string `json:"detail"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReconcileReport 对账报告
+type ReconcileReport struct {
+	StartedAt        time.Time         `json:"startedAt"`
+	FinishedAt       time.Time         `json:"finishedAt"`
+	DurationMs       int64             `json:"durationMs"`
+	Instance         string            `json:"instance"`
+	ActiveCronTasks  int               `json:"activeCronTasks"`
+	ActiveSchedules  int               `json:"activeSchedules"`
+	SchedulerEntries int               `json:"schedulerEntries"`
+	ScheduledTasks   int               `json:"scheduledTasks"`
+	Actions          []ReconcileAction `json:"actions"`
+	Errors           []string          `json:"errors"`
+}
+
+func (r *ReconcileReport) add(taskID uint64, kind, detail string, err error) {
+	a := ReconcileAction{TaskID: taskID, Kind: kind, Detail: detail}
+	if err != nil {
+		a.Error = err.Error()
+	}
+	r.Actions = append(r.Actions, a)
+}
+
+// ReconcileHandler 定期对比 MySQL 任务与 asynq 调度条目/定时任务，修正两者的偏差
+type ReconcileHandler struct {
+	db *gorm.DB
+	js *JobService
+}
+
+func NewReconcileHandler(jobService *JobService, db *gorm.DB) {
+	handler := &ReconcileHandler{db: db, js: jobService}
+	jobService.RegisterHandler(handler)
+	// 不重试；上一轮未结束时不重复入队
+	jobService.RegisterSystemCron(reconcileSpec, ReconcileType, asynq.MaxRetry(0), asynq.Unique(4*time.Minute))
+}
+
+func (h *ReconcileHandler) TaskType() string {
+	return ReconcileType
+}
+
+func (h *ReconcileHandler) Process(ctx context.Context, payload []byte) error {
+	report := h.reconcile()
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	if err := h.js.rdb.Set(ctx, reconcileReportKey, data, 0).Err(); err != nil {
+		logger.Error("保存对账报告失败", "error", err)
+	}
+	logger.System("任务对账完成", "actions", len(report.Actions), "errors", len(report.Errors), "耗时(ms)", report.DurationMs)
+	return nil
+}
+
+// LastReconcileReport 读取最近一次对账报告；尚未对账时返回 nil
+func (ts *JobService) LastReconcileReport(ctx context.Context) (*ReconcileReport, error) {
+	data, err := ts.rdb.Get(ctx, reconcileReportKey).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var report ReconcileReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// TriggerReconcile 立即入队一次对账
+func (ts *JobService) TriggerReconcile() (*asynq.TaskInfo, error) {
+	return ts.EnqueueTask(ReconcileType, "", asynq.MaxRetry(0), asynq.Unique(time.Minute))
+}
+
+func (h *ReconcileHandler) reconcile() *ReconcileReport {
+	now := time.Now()
+	report := &ReconcileReport{StartedAt: now, Instance: h.js.instanceID, Actions: []ReconcileAction{}, Errors: []string{}}
+	defer func() {
+		report.FinishedAt = time.Now()
+		report.DurationMs = report.FinishedAt.Sub(now).Milliseconds()
+	}()
+
+	// 调度中的DB任务：待执行/执行中/失败待重试
+	var tasks []model.Task
+	if err := h.db.Where("status IN ? AND is_delete = 0", []int{0, 1, 3}).Find(&tasks).Error; err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("查询任务失败: %v", err))
+		return report
+	}
+	cronTasks := make(map[uint64]*model.Task)
+	schedules := make(map[uint64]*model.Task)
+	// 本轮已到期并卸载的周期任务，其条目不再按孤儿重复卸载
+	expired := make(map[uint64]bool)
+	for i := range tasks {
+		t := &tasks[i]
+		switch {
+		case t.TriggerType == model.TriggerTypeCron && t.CronExpression != "":
+			if t.ExpireTime != nil && !now.Before(*t.ExpireTime) {
+				h.js.markExpiredAndCleanup(t.ID, "任务已到期")
+				report.add(t.ID, "expired", "周期任务已到期，标记完成并卸载", nil)
+				expired[t.ID] = true
+				continue
+			}
+			cronTasks[t.ID] = t
+		case t.TriggerType == model.TriggerTypeSchedule:
+			schedules[t.ID] = t
+		}
+	}
+	report.ActiveCronTasks = len(cronTasks)
+
+	inspector := h.js.NewInspector()
+	defer inspector.Close()
+
+	h.reconcileCronEntries(inspector, cronTasks, expired, report)
+	h.reconcileScheduled(inspector, schedules, cronTasks, now, report)
+	h.fixCronNextExecuteAt(cronTasks, now, report)
+	return report
+}
+
+// reconcileCronEntries 对比 Scheduler 条目：按条目ID卸载孤儿条目，补注册缺失或表达式不一致的条目
+func (h *ReconcileHandler) reconcileCronEntries(inspector *asynq.Inspector, cronTasks map[uint64]*model.Task, expired map[uint64]bool, report *ReconcileReport) {
+	entries, err := inspector.SchedulerEntries()
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("读取Scheduler条目失败: %v", err))
+		return
+	}
+	// 每个调度中任务的条目ID与表达式不一致的条目
+	entryIDs := make(map[uint64][]string)
+	mismatch := make(map[uint64]string)
+	removed := make(map[string]bool)
+	for _, e := range entries {
+		if e.Task == nil || e.Task.Type() != BotMsgType {
+			continue
+		}
+		report.SchedulerEntries++
+		id := payloadTaskID(e.Task.Payload())
+		if id == 0 {
+			// 无法解析任务ID（如尚未迁移的旧版payload）：无法判断归属，不作为孤儿卸载
+			continue
+		}
+		t, ok := cronTasks[id]
+		if !ok {
+			if expired[id] || removed[e.ID] {
+				continue
+			}
+			removed[e.ID] = true
+			err := h.js.UnregisterCronEntry(e.ID)
+			report.add(id, "orphan_entry", fmt.Sprintf("卸载无对应调度中任务的条目 %s（%s）", e.ID, e.Spec), err)
+			continue
+		}
+		entryIDs[id] = append(entryIDs[id], e.ID)
+		if want := CronSpec(t.CronExpression, t.Timezone); e.Spec != want {
+			mismatch[id] = fmt.Sprintf("条目表达式 %s 与任务 %s 不一致，重新注册", e.Spec, want)
+		}
+	}
+	if len(entries) == 0 && !h.js.IsSchedulerLeader() {
+		// 没有任何条目时可能是leader尚未写入心跳，跳过补注册，避免对空集合误判
+		if _, err := h.js.rdb.Get(context.Background(), schedulerLeaderKey).Result(); err != nil {
+			report.Errors = append(report.Errors, "当前无调度器leader，跳过条目补注册")
+			return
+		}
+	}
+	for id, t := range cronTasks {
+		ids := entryIDs[id]
+		if len(ids) == 0 {
+			err := h.js.RegisterCronTask(NewCronRegistration(t, ReconcileType))
+			report.add(id, "missing_entry", "缺少调度条目，补注册", err)
+			continue
+		}
+		reason, mismatched := mismatch[id]
+		if len(ids) > 1 {
+			reason = fmt.Sprintf("存在%d个重复条目，全部卸载后重新注册", len(ids))
+		} else if !mismatched {
+			continue
+		}
+		// 按条目ID逐个卸载（重复条目可能不在leader的本地登记中，重新注册只会替换登记的那一个）
+		var errs []error
+		for _, entryID := range ids {
+			if err := h.js.UnregisterCronEntry(entryID); err != nil {
+				errs = append(errs, fmt.Errorf("卸载条目%s失败: %w", entryID, err))
+			}
+		}
+		if err := h.js.RegisterCronTask(NewCronRegistration(t, ReconcileType)); err != nil {
+			errs = append(errs, err)
+		}
+		report.add(id, "missing_entry", reason, errors.Join(errs...))
+	}
+}
+
+// reconcileScheduled 对比定时队列：删除孤儿任务，补入队缺失的一次性任务，修正丢失/中断的任务状态
+func (h *ReconcileHandler) reconcileScheduled(inspector *asynq.Inspector, schedules, cronTasks map[uint64]*model.Task, now time.Time, report *ReconcileReport) {
+	queued := make(map[uint64]bool)
+	queues := h.js.queueNames(inspector)
+	for _, queue := range queues {
+		for page := 1; ; page++ {
+			items, err := inspector.ListScheduledTasks(queue, asynq.Page(page), asynq.PageSize(100))
+			if err != nil {
+				// 定时队列不完整时不做判定，避免误标丢失
+				report.Errors = append(report.Errors, fmt.Sprintf("读取定时队列%s失败: %v", queue, err))
+				return
+			}
+			for _, ti := range items {
+				if ti.Type != BotMsgType {
+					continue
+				}
+				report.ScheduledTasks++
+				if p, err := DecodeJobPayload(ti.Payload); err == nil && (IsDeferredSource(p.Type) || len(p.Targets) > 0) {
+					// 限流/静默期/失败补发属于已开始的运行，与任务当前状态无关（一次性任务执行后即为已完成），不判定为孤儿
+					continue
+				}
+				id := payloadTaskID(ti.Payload)
+				if _, ok := schedules[id]; ok {
+					queued[id] = true
+					continue
+				}
+				if _, ok := cronTasks[id]; ok {
+					// 周期任务的补执行
+					continue
+				}
+				err := inspector.DeleteTask(ti.Queue, ti.ID)
+				report.add(id, "orphan_scheduled", fmt.Sprintf("删除无对应调度中任务的定时任务 %s", ti.ID), err)
+			}
+			if len(items) < 100 {
+				break
+			}
+		}
+	}
+
+	activeSchedules := 0
+	for id, t := range schedules {
+		if t.ScheduleTime == nil {
+			continue
+		}
+		if queued[id] {
+			activeSchedules++
+			if t.Status == 0 && (t.NextExecuteAt == nil || !t.NextExecuteAt.Equal(*t.ScheduleTime)) {
+				err := h.db.Model(&model.Task{}).Where("id = ?", id).Update("next_execute_at", t.ScheduleTime).Error
+				report.add(id, "stale_next", "下次执行时间与执行时间不一致，已修正", err)
+			}
+			continue
+		}
+		// 不在定时队列中：可能已入待执行/执行中/重试队列
+		if scheduleInFlight(inspector, queues, id) {
+			activeSchedules++
+			continue
+		}
+		switch {
+		case t.Status == 0 && t.ScheduleTime.After(now):
+			err := h.js.EnqueueSchedule(t, *t.ScheduleTime, ReconcileType)
+			if errors.Is(err, asynq.ErrTaskIDConflict) {
+				err = nil
+			}
+			report.add(id, "missing_scheduled", "定时任务丢失，按原执行时间补入队", err)
+			activeSchedules++
+		case t.Status == 0 && t.ScheduleTime.Before(now.Add(-misfireGrace)):
+			err := h.markLost(id, "执行时间已过但队列中无对应任务（调度丢失）")
+			report.add(id, "lost", "执行时间已过且队列中无任务，标记失败", err)
+		case t.Status == 1 && t.LastExecutedAt != nil && t.LastExecutedAt.Before(now.Add(-reconcileStuckAfter)) &&
+			(t.NextExecuteAt == nil || t.NextExecuteAt.Before(now.Add(-misfireGrace))):
+			// 下次执行时间未到的为部分投递失败后等待补发，补发任务不使用固定TaskID，不算执行中断
+			err := h.markLost(id, "执行中断：队列中已无对应任务")
+			report.add(id, "stuck", "长时间处于执行中且队列中无任务，标记失败", err)
+		}
+	}
+	report.ActiveSchedules = activeSchedules
+}
+
+// scheduleInFlight 一次性任务是否仍在任一队列中等待或执行
+func scheduleInFlight(inspector *asynq.Inspector, queues []string, taskID uint64) bool {
+	for _, queue := range queues {
+		info, err := inspector.GetTaskInfo(queue, scheduleRunID(taskID))
+		if err == nil && info.State != asynq.TaskStateCompleted && info.State != asynq.TaskStateArchived {
+			return true
+		}
+	}
+	return migratedScheduleQueued(inspector, queues, taskID)
+}
+
+func (h *ReconcileHandler) markLost(taskID uint64, reason string) error {
+	return h.db.Model(&model.Task{}).Where("id = ? AND is_delete = 0", taskID).Updates(map[string]interface{}{
+		"status":          3,
+		"next_execute_at": nil,
+		"error_message":   reason,
+		"update_time":     time.Now(),
+	}).Error
+}
+
+// fixCronNextExecuteAt 修正周期任务缺失或已过期的下次执行时间
+func (h *ReconcileHandler) fixCronNextExecuteAt(cronTasks map[uint64]*model.Task, now time.Time, report *ReconcileReport) {
+	cu := toolsCron.NewCronUtils()
+	for id, t := range cronTasks {
+		if t.NextExecuteAt != nil && t.NextExecuteAt.After(now.Add(-misfireGrace)) {
+			continue
+		}
+		next, err := cu.CalculateNextExecution(t.CronExpression, now.In(t.Location()))
+		if err != nil {
+			report.add(id, "stale_next", "计算下次执行时间失败", err)
+			continue
+		}
+		err = h.db.Model(&model.Task{}).Where("id = ?", id).Update("next_execute_at", next).Error
+		report.add(id, "stale_next", fmt.Sprintf("下次执行时间修正为 %s", next.In(t.Location()).Format("2006-01-02 15:04:05")), err)
+	}
+}
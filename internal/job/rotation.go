@@ -0,0 +1,222 @@
+package job
+
+import (
+	"app/internal/model"
+	"app/tools/logger"
+	"app/tools/random"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 消息轮换状态（Redis哈希 task:rotation:<taskID>，多实例共享）
+//   - next: 下一次执行要发送的消息ID（详情预览与实际发送一致）
+//   - run/sent: 最近一次运行的asynq任务ID及其发送的消息ID，重试时沿用同一条消息
+const (
+	rotationKeyPrefix = "task:rotation:"
+	rotationStateTTL  = 30 * 24 * time.Hour
+	// 并发更新冲突时的重试次数
+	rotationCASRetries = 5
+)
+
+// rotationCASScript 比较并设置轮换状态：next 仍为读取时的值才写入（可同时写入 run/sent），
+// 多实例/并发运行不会选出同一条消息或互相覆盖轮换进度
+// KEYS[1] 轮换状态key；ARGV: 读取到的next（不存在为空串）, 新next, TTL毫秒[, run, sent]
+var rotationCASScript = redis.NewScript(`
+if (redis.call('HGET', KEYS[1], 'next') or '') ~= ARGV[1] then
+  return 0
+end
+redis.call('HSET', KEYS[1], 'next', ARGV[2])
+if #ARGV >= 5 then
+  redis.call('HSET', KEYS[1], 'run', ARGV[4], 'sent', ARGV[5])
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+// ParseRotationWeights 解析任务的消息权重；未配置时返回空map（权重按1计算）
+func ParseRotationWeights(data model.JSON) map[uint64]int {
+	weights := make(map[uint64]int)
+	if len(data) > 0 {
+		_ = json.Unmarshal(data, &weights)
+	}
+	return weights
+}
+
+func rotationKey(taskID uint64) string {
+	return fmt.Sprintf("%s%d", rotationKeyPrefix, taskID)
+}
+
+// NextRotationMessage 下一次执行将发送的消息ID，只读取轮换状态不写入（供查询接口预览）
+// all 模式或只有一条消息时返回 0；随机/权重模式首次执行前尚未确定，同样返回 0
+func (ts *JobService) NextRotationMessage(ctx context.Context, t *model.Task, messageIDs []uint64) (uint64, error) {
+	if !rotates(t, messageIDs) {
+		return 0, nil
+	}
+	v, err := ts.rdb.HGet(ctx, rotationKey(t.ID), "next").Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+	if next := parseRotationID(v); containsID(messageIDs, next) {
+		return next, nil
+	}
+	// 与 pickRotationMessages 一致：未初始化时轮询模式从第一条开始
+	if t.RotationMode == model.RotationModeRoundRobin {
+		return messageIDs[0], nil
+	}
+	return 0, nil
+}
+
+// pickRotationMessages 按任务轮换模式选出本次运行要发送的消息，并推进轮换状态
+// 同一运行（asynq重试）重复调用时返回同一条消息；Redis不可用时退化为无状态选取
+func (ts *JobService) pickRotationMessages(ctx context.Context, t *model.Task, messageIDs []uint64, runID string) []uint64 {
+	if !rotates(t, messageIDs) {
+		return messageIDs
+	}
+	key := rotationKey(t.ID)
+	for i := 0; i < rotationCASRetries; i++ {
+		v, err := ts.rdb.HMGet(ctx, key, "run", "sent", "next").Result()
+		if err != nil {
+			logger.Error("读取消息轮换状态失败，本次随机选取", "taskID", t.ID, "error", err)
+			return []uint64{chooseRotation(t, messageIDs, 0)}
+		}
+		if runID != "" && v[0] == runID {
+			if sent := parseRotationID(v[1]); containsID(messageIDs, sent) {
+				return []uint64{sent}
+			}
+		}
+		expected, _ := v[2].(string)
+		sent := parseRotationID(v[2])
+		if !containsID(messageIDs, sent) {
+			sent = chooseRotation(t, messageIDs, 0)
+		}
+		next := chooseRotation(t, messageIDs, sent)
+		ok, err := ts.casRotation(ctx, key, expected, next, runID, sent)
+		if err != nil {
+			logger.Error("保存消息轮换状态失败，本次随机选取", "taskID", t.ID, "error", err)
+			return []uint64{chooseRotation(t, messageIDs, 0)}
+		}
+		if ok {
+			return []uint64{sent}
+		}
+	}
+	logger.Error("消息轮换状态并发更新冲突，本次随机选取", "taskID", t.ID)
+	return []uint64{chooseRotation(t, messageIDs, 0)}
+}
+
+// peekRotationMessages 选出下一次执行将发送的消息但不推进轮换状态（手动触发使用，不影响调度执行的轮换顺序）
+func (ts *JobService) peekRotationMessages(ctx context.Context, t *model.Task, messageIDs []uint64) []uint64 {
+	if !rotates(t, messageIDs) {
+		return messageIDs
+	}
+	next, err := ts.peekRotation(ctx, t, messageIDs)
+	if err != nil {
+		logger.Error("读取消息轮换状态失败，本次随机选取", "taskID", t.ID, "error", err)
+		return []uint64{chooseRotation(t, messageIDs, 0)}
+	}
+	return []uint64{next}
+}
+
+// peekRotation 读取下一条消息；未初始化或已不在消息列表中时重新选取
+func (ts *JobService) peekRotation(ctx context.Context, t *model.Task, messageIDs []uint64) (uint64, error) {
+	key := rotationKey(t.ID)
+	for i := 0; i < rotationCASRetries; i++ {
+		v, err := ts.rdb.HGet(ctx, key, "next").Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return 0, err
+		}
+		if next := parseRotationID(v); containsID(messageIDs, next) {
+			return next, nil
+		}
+		next := chooseRotation(t, messageIDs, 0)
+		ok, err := ts.casRotation(ctx, key, v, next)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return next, nil
+		}
+	}
+	return 0, errors.New("消息轮换状态并发更新冲突")
+}
+
+// casRotation 执行 rotationCASScript，返回是否写入成功
+func (ts *JobService) casRotation(ctx context.Context, key, expected string, next uint64, runAndSent ...interface{}) (bool, error) {
+	args := append([]interface{}{expected, next, rotationStateTTL.Milliseconds()}, runAndSent...)
+	n, err := rotationCASScript.Run(ctx, ts.rdb, []string{key}, args...).Int()
+	return n == 1, err
+}
+
+// chooseRotation 根据上一次发送的消息选出下一条（prev 为 0 表示尚未发送过）
+func chooseRotation(t *model.Task, messageIDs []uint64, prev uint64) uint64 {
+	switch t.RotationMode {
+	case model.RotationModeRoundRobin:
+		for i, id := range messageIDs {
+			if id == prev {
+				return messageIDs[(i+1)%len(messageIDs)]
+			}
+		}
+		return messageIDs[0]
+	case model.RotationModeWeighted:
+		weights := ParseRotationWeights(t.RotationWeights)
+		total := 0
+		for _, id := range messageIDs {
+			total += rotationWeight(weights, id)
+		}
+		n := mathrand.Intn(total)
+		for _, id := range messageIDs {
+			if n -= rotationWeight(weights, id); n < 0 {
+				return id
+			}
+		}
+		return messageIDs[len(messageIDs)-1]
+	default:
+		// 随机：排除上一次发送的消息，避免连续重复
+		candidates := make([]uint64, 0, len(messageIDs))
+		for _, id := range messageIDs {
+			if id != prev {
+				candidates = append(candidates, id)
+			}
+		}
+		if len(candidates) == 0 {
+			candidates = messageIDs
+		}
+		id, _ := random.RandSlice(candidates)
+		return id
+	}
+}
+
+func rotates(t *model.Task, messageIDs []uint64) bool {
+	return t.RotationMode != "" && t.RotationMode != model.RotationModeAll && len(messageIDs) > 1
+}
+
+func rotationWeight(weights map[uint64]int, id uint64) int {
+	if w, ok := weights[id]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
+
+func parseRotationID(v interface{}) uint64 {
+	s, _ := v.(string)
+	id, _ := strconv.ParseUint(s, 10, 64)
+	return id
+}
+
+func containsID(ids []uint64, id uint64) bool {
+	if id == 0 {
+		return false
+	}
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
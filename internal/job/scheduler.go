@@ -0,0 +1,446 @@
+package job
+
+import (
+	"app/internal/config"
+	"app/internal/model"
+	"app/tools/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+	"github.com/robfig/cron/v3"
+)
+
+// 多实例部署时只有持有租约的实例（leader）运行 asynq.Scheduler；
+// 周期任务条目按DB任务ID登记，保证集群内每个任务只有一个条目。
+// 非leader实例上的注册/卸载通过 Redis 发布到 leader 执行；只有leader订阅该通道，接收数为0说明leader不在线。
+const (
+	schedulerLeaderKey  = "job:scheduler:leader"
+	cronSyncChannel     = "job:scheduler:cron-sync"
+	leaderLeaseTTL      = 15 * time.Second
+	leaderRenewInterval = 5 * time.Second
+)
+
+// errNoSchedulerLeader 转发注册/卸载时没有在线的leader
+var errNoSchedulerLeader = errors.New("当前没有在线的调度器leader，请稍后重试")
+
+// 仅当租约仍属于自己时续期/释放
+var (
+	renewLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// CronRegistration 周期任务条目注册信息（可序列化，用于转发给leader）
+type CronRegistration struct {
+	TaskID   uint64 `json:"taskId"`
+	Spec     string `json:"spec"` // 5位表达式，可带 CRON_TZ 前缀
+	TaskType string `json:"taskType"`
+	Payload  string `json:"payload"`
+	MaxRetry int    `json:"maxRetry"`
+	Priority string `json:"priority"` // 任务优先级，由leader按配置映射为队列
+}
+
+func (r CronRegistration) options() []asynq.Option {
+	opts := make([]asynq.Option, 0, 1)
+	if r.MaxRetry > 0 {
+		opts = append(opts, asynq.MaxRetry(r.MaxRetry))
+	}
+	return opts
+}
+
+// systemCron 系统周期任务，不关联DB任务
+type systemCron struct {
+	spec     string
+	taskType string
+	opts     []asynq.Option
+}
+
+// NewCronRegistration 按DB任务生成周期条目注册信息（带任务时区、到期时间、重试次数与退避策略）
+func NewCronRegistration(t *model.Task, source string) CronRegistration {
+	return CronRegistration{
+		TaskID:   t.ID,
+		Spec:     CronSpec(t.CronExpression, t.Timezone),
+		TaskType: BotMsgType,
+		Payload:  NewJobPayload(source, t.ID, t.ExpireTime).WithRetry(t).Encode(),
+		MaxRetry: t.MaxRetryCount,
+		Priority: t.Priority,
+	}
+}
+
+type cronSyncMessage struct {
+	Op           string            `json:"op"` // register / unregister / unregister_entry
+	From         string            `json:"from"`
+	TaskID       uint64            `json:"taskId"`
+	EntryID      string            `json:"entryId,omitempty"`
+	Registration *CronRegistration `json:"registration,omitempty"`
+}
+
+// newInstanceID 实例标识：IP-PID-启动时间
+func newInstanceID() string {
+	return fmt.Sprintf("%s-%d-%d", config.LocalIp, os.Getpid(), time.Now().UnixNano())
+}
+
+// IsSchedulerLeader 当前实例是否运行调度器
+func (ts *JobService) IsSchedulerLeader() bool {
+	ts.schedMu.Lock()
+	defer ts.schedMu.Unlock()
+	return ts.leader
+}
+
+// OnLeaderElected 注册成为leader后的回调（如从DB恢复周期任务条目）
+func (ts *JobService) OnLeaderElected(fn func()) {
+	ts.schedMu.Lock()
+	defer ts.schedMu.Unlock()
+	ts.leaderHooks = append(ts.leaderHooks, fn)
+}
+
+// RegisterSystemCron 注册系统周期任务：当前及之后每次当选leader时注册到调度器
+func (ts *JobService) RegisterSystemCron(spec, taskType string, opts ...asynq.Option) {
+	ts.schedMu.Lock()
+	defer ts.schedMu.Unlock()
+	sc := systemCron{spec: spec, taskType: taskType, opts: opts}
+	ts.systemCrons = append(ts.systemCrons, sc)
+	if ts.scheduler != nil {
+		ts.registerSystemCronLocked(sc)
+	}
+}
+
+func (ts *JobService) registerSystemCronLocked(sc systemCron) {
+	entryID, err := ts.scheduler.Register(sc.spec, asynq.NewTask(sc.taskType, nil), sc.opts...)
+	if err != nil {
+		logger.Error("注册系统周期任务失败", "error", err, "spec", sc.spec, "taskType", sc.taskType)
+		return
+	}
+	logger.System("注册系统周期任务成功", "spec", sc.spec, "taskType", sc.taskType, "entryID", entryID)
+}
+
+// runLeaderElection 竞选/续约调度器租约，直到 ctx 结束
+func (ts *JobService) runLeaderElection(ctx context.Context) {
+	ticker := time.NewTicker(leaderRenewInterval)
+	defer ticker.Stop()
+	for {
+		ts.tryLead(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ts *JobService) tryLead(ctx context.Context) {
+	// 在发起请求前取时间：租约的过期时间不早于此刻+TTL
+	startedAt := time.Now()
+	if ts.IsSchedulerLeader() {
+		n, err := renewLeaseScript.Run(ctx, ts.rdb, []string{schedulerLeaderKey}, ts.instanceID, leaderLeaseTTL.Milliseconds()).Int()
+		switch {
+		case err == nil && n == 0:
+			// 租约已被其他实例持有
+			logger.Error("调度器租约已丢失，退出leader", "instance", ts.instanceID)
+			ts.stepDown(false)
+		case err != nil && time.Since(ts.leaseRenewedAt)+leaderRenewInterval >= leaderLeaseTTL:
+			// 下次续期前租约可能已过期，其他实例随时可能接管：立即退出，避免两个leader同时调度
+			logger.Error("调度器租约续期持续失败，退出leader", "instance", ts.instanceID, "error", err)
+			ts.stepDown(true)
+		case err != nil:
+			// 临时错误（如Redis超时）：下次续期前租约仍有效，继续持有并在下次续期时重试
+			logger.Error("调度器租约续期失败，下次重试", "instance", ts.instanceID, "error", err)
+		default:
+			ts.leaseRenewedAt = startedAt
+		}
+		return
+	}
+	ok, err := ts.rdb.SetNX(ctx, schedulerLeaderKey, ts.instanceID, leaderLeaseTTL).Result()
+	if err != nil {
+		logger.Error("竞选调度器租约失败", "instance", ts.instanceID, "error", err)
+		return
+	}
+	if ok {
+		ts.becomeLeader(startedAt)
+	}
+}
+
+// becomeLeader 获得租约：启动新的调度器并执行回调；acquiredAt 为发起竞选请求的时间
+func (ts *JobService) becomeLeader(acquiredAt time.Time) {
+	ts.schedMu.Lock()
+	scheduler := asynq.NewScheduler(ts.redisOpt, &asynq.SchedulerOpts{Location: time.Local})
+	if err := scheduler.Start(); err != nil {
+		ts.schedMu.Unlock()
+		logger.Error("调度器启动失败", "error", err)
+		_, _ = releaseLeaseScript.Run(context.Background(), ts.rdb, []string{schedulerLeaderKey}, ts.instanceID).Result()
+		return
+	}
+	ts.scheduler = scheduler
+	ts.cronEntries = make(map[uint64]string)
+	ts.leader = true
+	ts.leaseRenewedAt = acquiredAt
+	for _, sc := range ts.systemCrons {
+		ts.registerSystemCronLocked(sc)
+	}
+	hooks := append([]func(){}, ts.leaderHooks...)
+	ts.schedMu.Unlock()
+
+	stopSync := ts.subscribeCronSync()
+	ts.schedMu.Lock()
+	if ts.leader && ts.stopSync == nil {
+		ts.stopSync = stopSync
+		stopSync = nil
+	}
+	ts.schedMu.Unlock()
+	if stopSync != nil {
+		// 订阅期间已退出leader
+		stopSync()
+		return
+	}
+
+	logger.System("已成为调度器leader", "instance", ts.instanceID, "当前时间", time.Now().Format("2006-01-02 15:04:05"))
+	for _, fn := range hooks {
+		go fn()
+	}
+}
+
+// stepDown 停止调度器；release 为 true 时主动释放租约（正常停机）
+func (ts *JobService) stepDown(release bool) {
+	ts.schedMu.Lock()
+	scheduler := ts.scheduler
+	wasLeader := ts.leader
+	stopSync := ts.stopSync
+	ts.stopSync = nil
+	ts.scheduler = nil
+	ts.cronEntries = make(map[uint64]string)
+	ts.leader = false
+	ts.schedMu.Unlock()
+
+	if stopSync != nil {
+		stopSync()
+	}
+	if scheduler != nil {
+		scheduler.Shutdown()
+		logger.System("调度器已停止", "instance", ts.instanceID)
+	}
+	if release && wasLeader {
+		if _, err := releaseLeaseScript.Run(context.Background(), ts.rdb, []string{schedulerLeaderKey}, ts.instanceID).Result(); err != nil {
+			logger.Error("释放调度器租约失败", "error", err)
+		}
+	}
+}
+
+// subscribeCronSync 成为leader时订阅转发通道（等待订阅确认后返回），返回的函数用于退出leader时取消订阅
+func (ts *JobService) subscribeCronSync() context.CancelFunc {
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := ts.rdb.Subscribe(ctx, cronSyncChannel)
+	if _, err := sub.Receive(ctx); err != nil {
+		logger.Error("订阅cron同步通道失败", "error", err)
+	}
+	go ts.consumeCronSync(ctx, sub)
+	return cancel
+}
+
+// consumeCronSync 处理其他实例转发的注册/卸载请求，直到 ctx 结束
+func (ts *JobService) consumeCronSync(ctx context.Context, sub *redis.PubSub) {
+	defer sub.Close()
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-ch:
+			if !ok {
+				return
+			}
+			var msg cronSyncMessage
+			if err := json.Unmarshal([]byte(m.Payload), &msg); err != nil {
+				logger.Error("解析cron同步消息失败", "error", err, "payload", m.Payload)
+				continue
+			}
+			if msg.From == ts.instanceID || !ts.IsSchedulerLeader() {
+				continue
+			}
+			switch msg.Op {
+			case "register":
+				if msg.Registration == nil {
+					continue
+				}
+				if err := ts.registerCronLocal(*msg.Registration); err != nil {
+					logger.Error("处理转发的cron注册失败", "error", err, "taskID", msg.TaskID, "from", msg.From)
+				}
+			case "unregister":
+				if _, err := ts.unregisterCronLocal(msg.TaskID); err != nil {
+					logger.Error("处理转发的cron卸载失败", "error", err, "taskID", msg.TaskID, "from", msg.From)
+				}
+			case "unregister_entry":
+				if err := ts.unregisterEntryLocal(msg.EntryID); err != nil {
+					logger.Error("处理转发的cron条目卸载失败", "error", err, "entryID", msg.EntryID, "from", msg.From)
+				}
+			}
+		}
+	}
+}
+
+func (ts *JobService) publishCronSync(msg cronSyncMessage) error {
+	msg.From = ts.instanceID
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	receivers, err := ts.rdb.Publish(context.Background(), cronSyncChannel, data).Result()
+	if err != nil {
+		return err
+	}
+	if receivers == 0 {
+		// 没有leader接收：请求不会被执行，交由调用方失败处理（无leader期间的条目由新leader从DB恢复）
+		logger.Error("cron同步消息无接收方，当前没有在线的调度器leader", "op", msg.Op, "taskID", msg.TaskID)
+		return errNoSchedulerLeader
+	}
+	return nil
+}
+
+// RegisterCronTask 注册（或替换）DB任务的周期条目；非leader实例转发给leader执行
+// 转发时只保证leader已收到请求：leader注册失败只记录日志，不会返回给调用方，由对账（缺少条目时补注册）修复
+func (ts *JobService) RegisterCronTask(reg CronRegistration) error {
+	if reg.TaskID == 0 {
+		return fmt.Errorf("缺少任务ID")
+	}
+	if err := validateCronSpec(reg.Spec); err != nil {
+		return err
+	}
+	// 验证 Handler 是否已注册
+	if _, ok := ts.GetHandler(reg.TaskType); !ok {
+		return fmt.Errorf("no handler registered for task type: %s", reg.TaskType)
+	}
+	if ts.IsSchedulerLeader() {
+		return ts.registerCronLocal(reg)
+	}
+	if err := ts.publishCronSync(cronSyncMessage{Op: "register", TaskID: reg.TaskID, Registration: &reg}); err != nil {
+		return fmt.Errorf("转发cron注册失败: %w", err)
+	}
+	logger.System("已转发cron注册到leader", "taskID", reg.TaskID, "spec", reg.Spec)
+	return nil
+}
+
+// UnregisterCronTask 卸载DB任务的周期条目；非leader实例转发给leader执行
+func (ts *JobService) UnregisterCronTask(dbTaskID uint64) error {
+	if ts.IsSchedulerLeader() {
+		_, err := ts.unregisterCronLocal(dbTaskID)
+		return err
+	}
+	if err := ts.publishCronSync(cronSyncMessage{Op: "unregister", TaskID: dbTaskID}); err != nil {
+		return fmt.Errorf("转发cron卸载失败: %w", err)
+	}
+	return nil
+}
+
+// UnregisterCronEntry 按条目ID卸载周期条目（对账清理孤儿条目，条目可能不在本地登记中）；非leader实例转发给leader执行
+func (ts *JobService) UnregisterCronEntry(entryID string) error {
+	if ts.IsSchedulerLeader() {
+		return ts.unregisterEntryLocal(entryID)
+	}
+	if err := ts.publishCronSync(cronSyncMessage{Op: "unregister_entry", EntryID: entryID}); err != nil {
+		return fmt.Errorf("转发cron条目卸载失败: %w", err)
+	}
+	return nil
+}
+
+func (ts *JobService) registerCronLocal(reg CronRegistration) error {
+	ts.schedMu.Lock()
+	defer ts.schedMu.Unlock()
+	if ts.scheduler == nil {
+		return fmt.Errorf("scheduler not initialized")
+	}
+	// 同一任务只保留一个条目：先卸载旧条目
+	if old, ok := ts.cronEntries[reg.TaskID]; ok {
+		if err := ts.scheduler.Unregister(old); err != nil {
+			logger.Error("替换cron条目时卸载旧条目失败", "error", err, "taskID", reg.TaskID, "entryID", old)
+		}
+		delete(ts.cronEntries, reg.TaskID)
+	}
+	task := asynq.NewTask(reg.TaskType, []byte(reg.Payload))
+	opts := append(reg.options(), asynq.Queue(ts.QueueFor(reg.Priority)))
+	entryID, err := ts.scheduler.Register(reg.Spec, task, opts...)
+	if err != nil {
+		logger.System("注册周期任务失败", "error", err, "cronExpr", reg.Spec, "taskID", reg.TaskID)
+		return fmt.Errorf("register periodic task failed: %w", err)
+	}
+	ts.cronEntries[reg.TaskID] = entryID
+	logger.System("注册周期任务成功", "cronExpr", reg.Spec, "taskID", reg.TaskID, "entryID", entryID)
+	return nil
+}
+
+func (ts *JobService) unregisterCronLocal(dbTaskID uint64) (bool, error) {
+	ts.schedMu.Lock()
+	defer ts.schedMu.Unlock()
+	if ts.scheduler == nil {
+		return false, fmt.Errorf("scheduler not initialized")
+	}
+	entryID, ok := ts.cronEntries[dbTaskID]
+	if !ok {
+		return false, fmt.Errorf("未找到匹配的cron条目")
+	}
+	if err := ts.scheduler.Unregister(entryID); err != nil {
+		return false, fmt.Errorf("unregister periodic task failed: %w", err)
+	}
+	delete(ts.cronEntries, dbTaskID)
+	logger.System("已卸载cron条目", "taskID", dbTaskID, "entryID", entryID)
+	return true, nil
+}
+
+func (ts *JobService) unregisterEntryLocal(entryID string) error {
+	ts.schedMu.Lock()
+	defer ts.schedMu.Unlock()
+	if ts.scheduler == nil {
+		return fmt.Errorf("scheduler not initialized")
+	}
+	if err := ts.scheduler.Unregister(entryID); err != nil {
+		return fmt.Errorf("unregister periodic task failed: %w", err)
+	}
+	for id, e := range ts.cronEntries {
+		if e == entryID {
+			delete(ts.cronEntries, id)
+		}
+	}
+	logger.System("已卸载cron条目", "entryID", entryID)
+	return nil
+}
+
+// CronEntryID 当前实例登记的DB任务条目ID（仅leader有值）
+func (ts *JobService) CronEntryID(dbTaskID uint64) (string, bool) {
+	ts.schedMu.Lock()
+	defer ts.schedMu.Unlock()
+	id, ok := ts.cronEntries[dbTaskID]
+	return id, ok
+}
+
+// validateCronSpec 严格只支持5字段（分钟 小时 日 月 周），可带 CRON_TZ 前缀
+func validateCronSpec(spec string) error {
+	timezone, expr := splitCronSpec(spec)
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return fmt.Errorf("无效的时区: %s", timezone)
+		}
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return fmt.Errorf("cron表达式格式错误: 仅支持标准5字段，实际%d字段: %s", len(fields), expr)
+	}
+	// 使用robfig/cron进行5字段语法校验，提前拦截包含 '?' 等Quartz语法
+	p := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+	if _, err := p.Parse(spec); err != nil {
+		logger.System("cron表达式解析失败", "error", err, "cronExpr", spec)
+		return fmt.Errorf("无效的cron表达式: %v", err)
+	}
+	return nil
+}
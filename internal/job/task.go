@@ -6,16 +6,14 @@ import (
     toolsCron "app/tools/cron"
     "app/tools/logger"
     "context"
-    "encoding/json"
+	"errors"
     "fmt"
-    "regexp"
     "strings"
     "sync"
     "time"
 
-    "github.com/robfig/cron/v3"
-
-    "github.com/hibiken/asynq"
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
     "go.uber.org/fx"
     "gorm.io/gorm"
 )
@@ -26,41 +24,47 @@ type JobHandler interface {
 	TaskType() string
 }
 
-// TaskConfig 任务配置
-type TaskConfig struct {
-	RedisAddr   string
-	Concurrency int
-}
-
 type JobService struct {
     handlers     map[string]JobHandler
     handlersLock sync.RWMutex
     client       *asynq.Client
     server       *asynq.Server
-    scheduler    *asynq.Scheduler
     mux          *asynq.ServeMux
-    config       *TaskConfig
+	jobConf      *config.JobConf
     redisConf    *config.RedisConf
+	redisOpt     asynq.RedisClientOpt
     db           *gorm.DB
+	rdb          *redis.Client
+
+	// 调度器仅在leader实例上运行，见 scheduler.go
+	instanceID     string
+	schedMu        sync.Mutex
+	scheduler      *asynq.Scheduler
+	leader         bool
+	leaseRenewedAt time.Time         // 当选或最近一次续期成功时发起请求的时间，仅在竞选协程中读写
+	cronEntries    map[uint64]string // DB任务ID -> Scheduler条目ID
+	leaderHooks    []func()
+	systemCrons    []systemCron       // 系统周期任务（如对账），随leader注册
+	stopSync       context.CancelFunc // 停止接收转发的注册/卸载请求（仅leader订阅）
+
+	webhookMaxRetry int // webhook投递最大重试次数，见 webhook.go
 }
 
-func NewJobService(db *gorm.DB, redisConf *config.RedisConf, lc fx.Lifecycle) *JobService {
+func NewJobService(db *gorm.DB, rdb *redis.Client, redisConf *config.RedisConf, jobConf *config.JobConf, lc fx.Lifecycle) *JobService {
 	// 从配置中读取 Redis 信息
 	redisAddr := fmt.Sprintf("%s:%s", redisConf.Ip, redisConf.Port)
 
-	taskConfig := &TaskConfig{
-		RedisAddr:   redisAddr,
-		Concurrency: 10, // 默认并发数
-	}
-
     ts := &JobService{
-        handlers: make(map[string]JobHandler),
-        config:   taskConfig,
-        db:       db,
+		handlers:    make(map[string]JobHandler),
+		jobConf:     jobConf,
+		db:          db,
+		rdb:         rdb,
+		instanceID:  newInstanceID(),
+		cronEntries: make(map[uint64]string),
     }
 
 	// 初始化 asynq client
-	redisOpt := asynq.RedisClientOpt{
+	ts.redisOpt = asynq.RedisClientOpt{
 		Addr:     redisAddr,
 		Username: redisConf.Username,
 		Password: redisConf.Password,
@@ -69,29 +73,17 @@ func NewJobService(db *gorm.DB, redisConf *config.RedisConf, lc fx.Lifecycle) *J
 	}
 
 	// 创建客户端并测试连接
-	ts.client = asynq.NewClient(redisOpt)
+	ts.client = asynq.NewClient(ts.redisOpt)
 	ts.redisConf = redisConf
 
-	// 初始化调度器，设置时区为上海时间
-	location, err := time.LoadLocation("Asia/Shanghai")
-	if err != nil {
-		logger.System("无法加载Asia/Shanghai时区，使用备选方案", "error", err)
-		location = time.FixedZone("Asia/Shanghai", 8*60*60) // 备选方案
-	}
-
-	// 记录时区配置信息
+	// 调度器默认使用服务时区，任务自身时区通过 CRON_TZ 前缀在条目上指定（见 CronSpec）
 	now := time.Now()
-	nowInLocation := now.In(location)
 	logger.System("时区配置详情",
 		"系统时间", now.Format("2006-01-02 15:04:05"),
-		"系统时区", now.Location().String(),
-		"调度器时区", location.String(),
-		"调度器时区时间", nowInLocation.Format("2006-01-02 15:04:05"),
-		"时区偏移", location.String())
-	schedulerOpt := &asynq.SchedulerOpts{
-		Location: location,
-	}
-	ts.scheduler = asynq.NewScheduler(redisOpt, schedulerOpt)
+		"调度器时区", time.Local.String(),
+		"实例", ts.instanceID)
+
+	electionCtx, stopElection := context.WithCancel(context.Background())
 
 	// FX 生命周期管理
 	lc.Append(fx.Hook{
@@ -107,29 +99,16 @@ func NewJobService(db *gorm.DB, redisConf *config.RedisConf, lc fx.Lifecycle) *J
 			// 等待 Worker 启动
 			time.Sleep(200 * time.Millisecond)
 
-			// 异步启动 Scheduler
-			go func() {
-				logger.System("正在启动调度器...")
-
-				if err := ts.scheduler.Start(); err != nil {
-					logger.System("调度器启动失败", "error", err)
-				} else {
-					logger.System("调度器启动成功", "当前时间", time.Now().Format("2006-01-02 15:04:05"))
-				}
-			}()
-
-			// 等待调度器启动
-			time.Sleep(100 * time.Millisecond)
+			// 竞选调度器leader，只有leader运行 Scheduler 并接收转发的注册/卸载请求
+			go ts.runLeaderElection(electionCtx)
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
 			logger.System("任务服务停止中...")
 
-			// 先停止调度器，避免新任务入队
-			if ts.scheduler != nil {
-				ts.scheduler.Shutdown()
-				logger.System("调度器已停止")
-			}
+			// 先停止调度器并释放租约，避免新任务入队
+			stopElection()
+			ts.stepDown(true)
 
 			// 再停止 Worker，处理完剩余任务
 			ts.Stop()
@@ -144,10 +123,7 @@ func NewJobService(db *gorm.DB, redisConf *config.RedisConf, lc fx.Lifecycle) *J
 
 // StartWorker 启动任务工作进程
 func (ts *JobService) StartWorker() error {
-	concurrency := ts.config.Concurrency
-	if concurrency <= 0 {
-		concurrency = 10
-	}
+	concurrency := ts.jobConf.Concurrency
 
 	// 从配置中读取 Redis 信息
 	redisAddr := fmt.Sprintf("%s:%s", ts.redisConf.Ip, ts.redisConf.Port)
@@ -161,7 +137,11 @@ func (ts *JobService) StartWorker() error {
 	}
 
 	ts.server = asynq.NewServer(redisOpt, asynq.Config{
-		Concurrency: concurrency,
+		Concurrency:     concurrency,
+		Queues:          ts.jobConf.Queues,
+		StrictPriority:  ts.jobConf.StrictPriority,
+		ShutdownTimeout: ts.jobConf.ShutdownTimeout,
+		RetryDelayFunc:  ts.retryDelay,
 	})
 
 	// 构建ServeMux并注册当前已知的任务类型
@@ -173,7 +153,7 @@ func (ts *JobService) StartWorker() error {
 	}
 	ts.handlersLock.RUnlock()
 
-	logger.System("启动 asynq worker", "concurrency", concurrency, "redisAddr", redisAddr)
+	logger.System("启动 asynq worker", "concurrency", concurrency, "queues", ts.jobConf.Queues, "strictPriority", ts.jobConf.StrictPriority, "redisAddr", redisAddr)
 
 	// 这是阻塞调用，会一直运行直到服务停止
 	err := ts.server.Start(ts.mux)
@@ -235,8 +215,8 @@ func (ts *JobService) GetHandler(taskType string) (JobHandler, bool) {
 	return handler, ok
 }
 
-// helper: new asynq Inspector with current redis config
-func (ts *JobService) newInspector() *asynq.Inspector {
+// NewInspector 创建asynq Inspector（调用方负责Close）
+func (ts *JobService) NewInspector() *asynq.Inspector {
     redisAddr := fmt.Sprintf("%s:%s", ts.redisConf.Ip, ts.redisConf.Port)
     redisOpt := asynq.RedisClientOpt{
         Addr:     redisAddr,
@@ -253,7 +233,18 @@ func (ts *JobService) newInspector() *asynq.Inspector {
 // - active: 发送取消信号（最佳努力）
 // 返回删除数量与取消中的数量
 func (ts *JobService) PurgeQueuesByDBTaskID(dbTaskID uint64) (int, int, error) {
-    inspector := ts.newInspector()
+	return ts.purgeQueues(dbTaskID, false)
+}
+
+// PurgeWaitingByDBTaskID 只移除与DB任务关联的等待中任务（pending/scheduled/retry，含失败补发与延后投递），
+// 保留归档任务与执行中的运行（暂停任务时使用）；返回删除数量
+func (ts *JobService) PurgeWaitingByDBTaskID(dbTaskID uint64) (int, error) {
+	removed, _, err := ts.purgeQueues(dbTaskID, true)
+	return removed, err
+}
+
+func (ts *JobService) purgeQueues(dbTaskID uint64, waitingOnly bool) (int, int, error) {
+	inspector := ts.NewInspector()
     defer inspector.Close()
     if inspector == nil {
         return 0, 0, fmt.Errorf("inspector init failed")
@@ -261,7 +252,6 @@ func (ts *JobService) PurgeQueuesByDBTaskID(dbTaskID uint64) (int, int, error) {
 
     removed := 0
     canceled := 0
-    queue := "default"
 
     // helper: 删除匹配任务
     deleteMatches := func(tasks []*asynq.TaskInfo) {
@@ -269,7 +259,7 @@ func (ts *JobService) PurgeQueuesByDBTaskID(dbTaskID uint64) (int, int, error) {
             if ti == nil || ti.Type != BotMsgType || len(ti.Payload) == 0 {
                 continue
             }
-            if parseTaskIDFromPayload(ti.Payload) == dbTaskID || strings.Contains(string(ti.Payload), fmt.Sprintf("任务ID：%d", dbTaskID)) {
+			if payloadTaskID(ti.Payload) == dbTaskID {
                 if err := inspector.DeleteTask(ti.Queue, ti.ID); err == nil {
                     removed++
                 } else {
@@ -280,7 +270,7 @@ func (ts *JobService) PurgeQueuesByDBTaskID(dbTaskID uint64) (int, int, error) {
     }
 
     // helper: 分页扫描方法
-    scan := func(list func(string, ...asynq.ListOption) ([]*asynq.TaskInfo, error)) {
+	scan := func(queue string, list func(string, ...asynq.ListOption) ([]*asynq.TaskInfo, error)) {
         page := 1
         for {
             items, err := list(queue, asynq.Page(page), asynq.PageSize(100))
@@ -299,118 +289,148 @@ func (ts *JobService) PurgeQueuesByDBTaskID(dbTaskID uint64) (int, int, error) {
         }
     }
 
-    // pending
-    scan(inspector.ListPendingTasks)
-    // scheduled
-    scan(inspector.ListScheduledTasks)
-    // retry
-    scan(inspector.ListRetryTasks)
-    // archived
-    scan(inspector.ListArchivedTasks)
-    // completed
-    scan(inspector.ListCompletedTasks)
-
-    // active（无法直接删除，仅发送取消信号）
-    {
-        page := 1
-        for {
-            items, err := inspector.ListActiveTasks(queue, asynq.Page(page), asynq.PageSize(100))
-            if err != nil {
-                logger.Error("扫描active队列失败", "error", err)
-                break
-            }
-            if len(items) == 0 {
-                break
-            }
-            for _, ti := range items {
-                if ti == nil || ti.Type != BotMsgType || len(ti.Payload) == 0 {
-                    continue
+	for _, queue := range ts.queueNames(inspector) {
+		// pending
+		scan(queue, inspector.ListPendingTasks)
+		// scheduled
+		scan(queue, inspector.ListScheduledTasks)
+		// retry
+		scan(queue, inspector.ListRetryTasks)
+		if waitingOnly {
+			continue
+		}
+		// archived
+		scan(queue, inspector.ListArchivedTasks)
+		// completed
+		scan(queue, inspector.ListCompletedTasks)
+
+		// active（无法直接删除，仅发送取消信号）
+		{
+			page := 1
+			for {
+				items, err := inspector.ListActiveTasks(queue, asynq.Page(page), asynq.PageSize(100))
+				if err != nil {
+					logger.Error("扫描active队列失败", "error", err)
+					break
                 }
-                if parseTaskIDFromPayload(ti.Payload) == dbTaskID || strings.Contains(string(ti.Payload), fmt.Sprintf("任务ID：%d", dbTaskID)) {
-                    if err := inspector.CancelProcessing(ti.ID); err == nil {
-                        canceled++
-                    } else {
-                        logger.Error("取消active任务失败", "error", err, "id", ti.ID)
+				if len(items) == 0 {
+					break
+				}
+				for _, ti := range items {
+					if ti == nil || ti.Type != BotMsgType || len(ti.Payload) == 0 {
+						continue
+					}
+					if payloadTaskID(ti.Payload) == dbTaskID {
+						if err := inspector.CancelProcessing(ti.ID); err == nil {
+							canceled++
+						} else {
+							logger.Error("取消active任务失败", "error", err, "id", ti.ID)
+						}
                     }
                 }
+				if len(items) < 100 {
+					break
+				}
+				page++
             }
-            if len(items) < 100 {
-                break
-            }
-            page++
         }
     }
 
     return removed, canceled, nil
 }
 
+// HasActiveRun DB任务是否有正在执行的运行（任一队列的active任务中存在关联该任务的payload）
+// 周期任务执行后保持状态1，不能仅凭状态判断是否执行中
+func (ts *JobService) HasActiveRun(dbTaskID uint64) (bool, error) {
+	inspector := ts.NewInspector()
+	defer inspector.Close()
+	for _, queue := range ts.queueNames(inspector) {
+		page := 1
+		for {
+			items, err := inspector.ListActiveTasks(queue, asynq.Page(page), asynq.PageSize(100))
+			if err != nil {
+				return false, err
+			}
+			for _, ti := range items {
+				if ti != nil && ti.Type == BotMsgType && payloadTaskID(ti.Payload) == dbTaskID {
+					return true, nil
+				}
+			}
+			if len(items) < 100 {
+				break
+			}
+			page++
+		}
+	}
+	return false, nil
+}
+
 // DeleteScheduledByDBTaskID 删除一次性定时任务（Scheduled队列）
 func (ts *JobService) DeleteScheduledByDBTaskID(dbTaskID uint64) error {
-    inspector := ts.newInspector()
+	inspector := ts.NewInspector()
     defer inspector.Close()
-    // 优先按固定TaskID删除（新版本使用 TaskID("schedule:<id>")）
-    taskID := fmt.Sprintf("schedule:%d", dbTaskID)
-    if err := inspector.DeleteTask("default", taskID); err == nil {
-        logger.System("已按TaskID删除一次性定时任务", "taskID", taskID)
-        return nil
-    }
-    // 兼容旧版本：遍历Scheduled任务，按payload中的 任务ID 匹配
-    tasks, err := inspector.ListScheduledTasks("default")
-    if err != nil {
-        return err
-    }
-    needle := fmt.Sprintf("任务ID：%d", dbTaskID)
-    for _, t := range tasks {
-        if t.Type == BotMsgType && strings.Contains(string(t.Payload), needle) {
-            if err := inspector.DeleteTask("default", t.ID); err == nil {
-                logger.System("已按payload匹配删除一次性定时任务", "deleted_id", t.ID)
-                return nil
+	// 优先按固定TaskID删除（新版本使用 TaskID("schedule:<id>")）；优先级可能已变更，逐个队列查找
+	taskID := scheduleRunID(dbTaskID)
+	queues := ts.queueNames(inspector)
+	for _, queue := range queues {
+		if err := inspector.DeleteTask(queue, taskID); err == nil {
+			logger.System("已按TaskID删除一次性定时任务", "taskID", taskID, "queue", queue)
+			return nil
+		}
+    }
+	// 未使用固定TaskID入队的：遍历Scheduled任务，按payload中的任务ID精确匹配
+	for _, queue := range queues {
+		tasks, err := inspector.ListScheduledTasks(queue)
+		if err != nil {
+			logger.Error("扫描Scheduled队列失败", "error", err, "queue", queue)
+			continue
+		}
+		for _, t := range tasks {
+			if t.Type == BotMsgType && payloadTaskID(t.Payload) == dbTaskID {
+				if err := inspector.DeleteTask(queue, t.ID); err == nil {
+					logger.System("已按payload匹配删除一次性定时任务", "deleted_id", t.ID)
+					return nil
+				}
             }
         }
     }
     return fmt.Errorf("未找到待删除的一次性定时任务: %s", taskID)
 }
 
-// UnregisterCronByTask 精确卸载与DB任务关联的cron条目
-func (ts *JobService) UnregisterCronByTask(cronExpr string, dbTaskID uint64) (int, error) {
-    if ts.scheduler == nil {
-        return 0, fmt.Errorf("scheduler not initialized")
-    }
-    inspector := ts.newInspector()
-    defer inspector.Close()
-    entries, err := inspector.SchedulerEntries()
-    if err != nil {
-        return 0, err
-    }
-    match := 0
-    needle := fmt.Sprintf("任务ID：%d", dbTaskID)
-    for _, e := range entries {
-        if e.Spec == cronExpr && e.Task != nil && e.Task.Type() == BotMsgType {
-            // 通过结构化task_id或payload包含中文提示精确定位
-            matched := false
-            var p BotMsgPayload
-            if json.Unmarshal(e.Task.Payload(), &p) == nil {
-                if p.TaskID == dbTaskID {
-                    matched = true
-                }
-            }
-            if !matched && strings.Contains(string(e.Task.Payload()), needle) {
-                matched = true
-            }
-            if matched {
-                if err := ts.scheduler.Unregister(e.ID); err != nil {
-                    logger.Error("卸载cron条目失败", "error", err, "entryID", e.ID)
-                } else {
-                    logger.System("已卸载cron条目", "entryID", e.ID, "spec", e.Spec)
-                    match++
-                }
-            }
-        }
-    }
-    if match == 0 {
-        return 0, fmt.Errorf("未找到匹配的cron条目")
-    }
-    return match, nil
+// QueueFor 任务优先级对应的队列
+func (ts *JobService) QueueFor(priority string) string {
+	return ts.jobConf.QueueFor(priority)
+}
+
+// queueNames Redis中已存在的队列（含配置变更前遗留的队列）；读取失败时使用已配置的队列
+func (ts *JobService) queueNames(inspector *asynq.Inspector) []string {
+	if existing, err := inspector.Queues(); err == nil {
+		return existing
+	}
+	return ts.jobConf.QueueNames()
+}
+
+// CronSpec 生成带时区的调度条目：CRON_TZ=<时区> <5位表达式>；时区为空时使用调度器默认时区
+func CronSpec(cronExpr, timezone string) string {
+	cronExpr = strings.TrimSpace(cronExpr)
+	if timezone == "" || strings.HasPrefix(cronExpr, "CRON_TZ=") || strings.HasPrefix(cronExpr, "TZ=") {
+		return cronExpr
+	}
+	return fmt.Sprintf("CRON_TZ=%s %s", timezone, cronExpr)
+}
+
+// splitCronSpec 拆分调度条目中的时区前缀与表达式
+func splitCronSpec(spec string) (timezone, cronExpr string) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		idx := strings.Index(spec, " ")
+		if idx < 0 {
+			return "", spec
+		}
+		timezone = spec[strings.Index(spec, "=")+1 : idx]
+		return timezone, strings.TrimSpace(spec[idx:])
+	}
+	return "", spec
 }
 
 // processTask 统一任务处理函数
@@ -427,29 +447,56 @@ func (ts *JobService) processTask(ctx context.Context, task *asynq.Task) error {
     payload := task.Payload()
     logger.System("开始处理任务", "taskType", taskType, "payload", string(payload), "开始时间", startTime.Format("2006-01-02 15:04:05"))
 
-    // 从payload解析 DB 任务ID
-    dbTaskID := parseTaskIDFromPayload(payload)
+	// 解析统一 payload；系统任务（如对账）无 payload
+	env := &JobPayload{}
+	if len(payload) > 0 {
+		p, err := DecodeJobPayload(payload)
+		if errors.Is(err, ErrLegacyPayload) {
+			// 迁移完成前仍在途的旧版任务：转换后交给Handler
+			if legacy, ok := upgradeLegacyPayload(payload); ok {
+				logger.System("旧版payload已转换", "taskType", taskType, "taskID", legacy.TaskID)
+				p, err = legacy, nil
+				payload = []byte(legacy.Encode())
+			}
+		}
+		if err != nil {
+			logger.Error("payload解析失败", "taskType", taskType, "error", err, "payload", string(payload))
+			return fmt.Errorf("payload解析失败: %v: %w", err, asynq.SkipRetry)
+		}
+		env = p
+	}
+
+	dbTaskID := env.TaskID
+	msgType := env.Type
+	// 手动触发：照常记录执行，但不改变任务状态与下一次执行时间
+	manual := msgType == MsgTypeManualTrigger
+	// 限流/静默期/失败补发：只投递上次延后或失败的部分，不重新标记执行中
+	deferred := IsDeferredSource(msgType)
+	// 是否回写任务状态：失败补发属于原运行的重试，原运行为调度执行时与原运行一样回写
+	tracked := tracksTaskStatus(env.RunOrigin())
 
     // 过期检查
-    var expireAt *time.Time
     var requiresExpire bool
     var dbTask model.Task
-    if t, ok := parseExpireTimeFromPayload(payload); ok {
-        expireAt = t
-    }
+	expireAt := env.Expire()
     if dbTaskID > 0 && ts.db != nil {
         // 读取任务类型与DB到期时间
-        if err := ts.db.Select("trigger_type", "expire_time", "cron_expression").Where("id = ? AND is_delete = 0", dbTaskID).First(&dbTask).Error; err == nil {
+		if err := ts.db.Select("id", "admin_id", "status", "trigger_type", "expire_time", "cron_expression").Where("id = ? AND is_delete = 0", dbTaskID).First(&dbTask).Error; err == nil {
             // 仅周期任务需要强制过期检查；定时执行任务不需要到期日期
             requiresExpire = dbTask.TriggerType == model.TriggerTypeCron
             if expireAt == nil && dbTask.ExpireTime != nil {
                 expireAt = dbTask.ExpireTime
             }
         }
+	}
+	// 已暂停的任务：跳过执行（卸载前已入队或卸载失败的残留任务，以及暂停前已入队的手动触发）
+	if dbTask.ID > 0 && dbTask.Status == 4 {
+		logger.System("任务已暂停，跳过执行", "taskID", dbTaskID, "taskType", taskType)
+		return nil
     }
     if dbTaskID > 0 {
-        // 仅在需要时（cron）进行过期校验
-        if requiresExpire {
+		// 仅在需要时（cron）进行过期校验；补发属于已开始的运行，不再校验
+		if requiresExpire && !deferred {
             if expireAt == nil {
                 ts.markExpiredAndCleanup(dbTaskID, "缺少ExpireTime")
                 return nil
@@ -460,67 +507,52 @@ func (ts *JobService) processTask(ctx context.Context, task *asynq.Task) error {
             }
         }
         // 标记执行中
-        ts.updateTaskExecuting(dbTaskID)
+		if !manual && !deferred {
+			ts.updateTaskExecuting(dbTaskID)
+		}
     }
 
+	// 创建执行记录，Handler 通过 ctx 写入投递明细
+	var rec *ExecutionRecorder
+	if dbTask.ID > 0 {
+		rec = ts.startExecution(ctx, &dbTask, env)
+		ctx = withExecutionRecorder(ctx, rec)
+	}
+
     err := handler.Process(ctx, payload)
     duration := time.Since(startTime)
+	ts.finishExecution(rec, err)
     if err != nil {
         logger.System("任务处理失败", "taskType", taskType, "error", err, "耗时", duration.String())
         if dbTaskID > 0 {
-            ts.updateTaskOnFailure(dbTaskID, err)
+			var pending *PendingRetryError
+			switch {
+			case tracked && errors.As(err, &pending):
+				ts.updateTaskOnPendingRetry(dbTaskID, err, pending)
+			case tracked:
+				ts.updateTaskOnFailure(ctx, dbTaskID, err, env.Attempt)
+			case manual:
+				ts.updateTaskOnManualRun(dbTaskID, err)
+			default:
+				ts.updateTaskOnDeferredRun(dbTaskID, err)
+			}
         }
         return err
     }
     logger.System("任务处理成功", "taskType", taskType, "耗时", duration.String())
     if dbTaskID > 0 {
-        ts.updateTaskOnSuccess(dbTaskID)
+		switch {
+		case tracked:
+			ts.updateTaskOnSuccess(dbTaskID)
+		case manual:
+			ts.updateTaskOnManualRun(dbTaskID, nil)
+		default:
+			ts.updateTaskOnDeferredRun(dbTaskID, nil)
+		}
     }
     return nil
 }
 
-// parseTaskIDFromPayload: 优先解析结构化 taskId 字段；兼容旧版 task_id；最后回退从文本中提取中文“任务ID”后的数字
-func parseTaskIDFromPayload(payload []byte) uint64 {
-    // 新版：taskId
-    {
-        var obj struct{ TaskID uint64 `json:"taskId"` }
-        if err := json.Unmarshal(payload, &obj); err == nil && obj.TaskID > 0 {
-            return obj.TaskID
-        }
-    }
-    // 兼容旧版：task_id
-    {
-        var obj struct{ TaskID uint64 `json:"task_id"` }
-        if err := json.Unmarshal(payload, &obj); err == nil && obj.TaskID > 0 {
-            return obj.TaskID
-        }
-    }
-    s := string(payload)
-    re := regexp.MustCompile(`(?m)(任务ID[：:](\s*))(\d+)`)
-    m := re.FindStringSubmatch(s)
-    if len(m) == 4 {
-        var id uint64
-        _, _ = fmt.Sscanf(m[3], "%d", &id)
-        return id
-    }
-    return 0
-}
-
-// parseExpireTimeFromPayload 解析 payload 中的 expireTime 字段
-func parseExpireTimeFromPayload(payload []byte) (*time.Time, bool) {
-    var obj struct{ ExpireTime string `json:"expireTime"` }
-    if err := json.Unmarshal(payload, &obj); err != nil {
-        return nil, false
-    }
-    if strings.TrimSpace(obj.ExpireTime) == "" {
-        return nil, false
-    }
-    if t, err := time.ParseInLocation("2006-01-02 15:04:05", obj.ExpireTime, time.Local); err == nil {
-        return &t, true
-    }
-    return nil, false
-}
-
 // 标记任务为过期失败，并做清理（cron卸载）
 func (ts *JobService) markExpiredAndCleanup(taskID uint64, msg string) {
     if ts.db == nil {
@@ -529,7 +561,7 @@ func (ts *JobService) markExpiredAndCleanup(taskID uint64, msg string) {
     now := time.Now()
     // 先读取任务类型，以确定过期后的状态
     var t model.Task
-    _ = ts.db.Select("trigger_type", "cron_expression").Where("id = ? AND is_delete = 0", taskID).First(&t).Error
+	_ = ts.db.Select("id", "admin_id", "task_name", "trigger_type", "cron_expression").Where("id = ? AND is_delete = 0", taskID).First(&t).Error
 
     updates := map[string]interface{}{
         "next_execute_at":  nil,
@@ -551,8 +583,15 @@ func (ts *JobService) markExpiredAndCleanup(taskID uint64, msg string) {
 
     // 周期任务到期需要卸载后续调度
     if t.TriggerType == model.TriggerTypeCron && t.CronExpression != "" {
-        _, _ = ts.UnregisterCronByTask(t.CronExpression, taskID)
+		_ = ts.UnregisterCronTask(taskID)
+	}
+	if t.TriggerType == model.TriggerTypeCron {
+		ts.notifyTaskExpired(&t)
     }
+	if t.ID > 0 {
+		t.Status = updates["status"].(int)
+		ts.EmitTaskEvent(&t, model.WebhookEventTaskExpired, map[string]interface{}{"reason": msg})
+	}
 }
 
 func (ts *JobService) updateTaskExecuting(taskID uint64) {
@@ -567,6 +606,11 @@ func (ts *JobService) updateTaskExecuting(taskID uint64) {
             "last_executed_at": &now,
             "update_time":      now,
         }).Error
+
+	var t model.Task
+	if err := ts.db.Select("id", "admin_id", "task_name", "trigger_type", "status").Where("id = ?", taskID).First(&t).Error; err == nil {
+		ts.EmitTaskEvent(&t, model.WebhookEventTaskStarted, map[string]interface{}{"startedAt": now})
+	}
 }
 
 func (ts *JobService) updateTaskOnSuccess(taskID uint64) {
@@ -586,7 +630,9 @@ func (ts *JobService) updateTaskOnSuccess(taskID uint64) {
         "last_executed_at": &now,
         "update_time":      now,
     }
-    if t.TriggerType == model.TriggerTypeSchedule {
+	if t.Status == 4 {
+		// 执行期间被暂停：只记录执行结果，保持暂停状态
+	} else if t.TriggerType == model.TriggerTypeSchedule {
         // 一次性任务：成功后置为完成
         updates["status"] = 2
         updates["next_execute_at"] = nil
@@ -594,14 +640,24 @@ func (ts *JobService) updateTaskOnSuccess(taskID uint64) {
         // 周期任务：保持执行中，计算下一次执行时间
         updates["status"] = 1
         cu := toolsCron.NewCronUtils()
-        if next, err := cu.CalculateNextExecution(t.CronExpression, now); err == nil {
+		if next, err := cu.CalculateNextExecution(t.CronExpression, now.In(t.Location())); err == nil {
             updates["next_execute_at"] = next
         }
     }
     _ = ts.db.Model(&model.Task{}).Where("id = ?", taskID).Updates(updates).Error
+	ts.resetFailureStreak(taskID)
+
+	if status, ok := updates["status"].(int); ok {
+		t.Status = status
+	}
+	ts.EmitTaskEvent(&t, model.WebhookEventTaskSucceeded, map[string]interface{}{
+		"executeCount":  t.ExecuteCount + 1,
+		"nextExecuteAt": updates["next_execute_at"],
+	})
 }
 
-func (ts *JobService) updateTaskOnFailure(taskID uint64, execErr error) {
+// updateTaskOnFailure 运行失败：attempts 为失败补发之前原运行已消耗的重试次数，普通运行为0
+func (ts *JobService) updateTaskOnFailure(ctx context.Context, taskID uint64, execErr error, attempts int) {
     if ts.db == nil {
         return
     }
@@ -610,56 +666,131 @@ func (ts *JobService) updateTaskOnFailure(taskID uint64, execErr error) {
         return
     }
     now := time.Now()
-    newRetry := t.RetryCount + 1
+	// 与 asynq 保持一致：retried 为本次运行已重试的次数，仍可重试时下一次执行时间即 asynq 的重试时间
+	retried, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	retried += attempts
+	maxRetry += attempts
+	willRetry := retried < maxRetry && !errors.Is(execErr, asynq.SkipRetry)
     updates := map[string]interface{}{
-        "status":        3,
-        "retry_count":   newRetry,
-        "error_message": fmt.Sprintf("%v", execErr),
+		"status":           3,
+		"retry_count":      retried + 1,
+		"error_message":    fmt.Sprintf("%v", execErr),
         "last_executed_at": &now,
-        "update_time":   now,
-    }
-    // 一次性定时任务：不再设置下一次执行时间
-    if t.TriggerType == model.TriggerTypeSchedule {
+		"update_time":      now,
+	}
+	if t.Status == 4 {
+		// 执行期间被暂停：只记录失败信息，保持暂停状态
+		delete(updates, "status")
+		updates["next_execute_at"] = nil
+	} else if willRetry {
+		next := now.Add(t.RetryDelay(retried + 1))
+		updates["next_execute_at"] = &next
+	} else if t.TriggerType == model.TriggerTypeCron && t.CronExpression != "" {
+		// 周期任务重试耗尽：等待下一个周期
+		cu := toolsCron.NewCronUtils()
+		if next, err := cu.CalculateNextExecution(t.CronExpression, now.In(t.Location())); err == nil {
+			updates["next_execute_at"] = next
+		}
+	} else {
+		// 一次性定时任务重试耗尽：不再执行
         updates["next_execute_at"] = nil
-    } else {
-        // 周期任务失败：按退避计算下一次执行时间
-        backoff := computeBackoff(newRetry)
-        next := now.Add(backoff)
-        updates["next_execute_at"] = &next
     }
     _ = ts.db.Model(&model.Task{}).Where("id = ?", taskID).Updates(updates).Error
+
+	// 本次运行最终失败（不再重试）时按管理员规则告警
+	if !willRetry && t.Status != 4 {
+		ts.notifyTaskFailure(&t, execErr)
+	}
+
+	if status, ok := updates["status"].(int); ok {
+		t.Status = status
+	}
+	ts.EmitTaskEvent(&t, model.WebhookEventTaskFailed, map[string]interface{}{
+		"error":         fmt.Sprintf("%v", execErr),
+		"attempt":       retried + 1,
+		"willRetry":     willRetry,
+		"nextExecuteAt": updates["next_execute_at"],
+	})
 }
 
-func computeBackoff(retry int) time.Duration {
-    if retry <= 0 {
-        return time.Minute
-    }
-    base := time.Minute
-    // 指数退避，最大 32 分钟
-    var shift uint
-    if retry-1 < 0 {
-        shift = 0
-    } else if retry-1 > 5 {
-        shift = 5
-    } else {
-        shift = uint(retry - 1)
-    }
-    d := base * time.Duration(1<<shift)
-    max := time.Hour
-    if d > max {
-        d = max
-    }
-    return d
+// updateTaskOnPendingRetry 部分投递失败且失败部分已转入补发：运行尚未结束，保持任务状态，
+// 记录重试次数、错误与补发的执行时间；不告警、不发送失败事件，由补发结束时回写结果
+func (ts *JobService) updateTaskOnPendingRetry(taskID uint64, execErr error, pending *PendingRetryError) {
+	if ts.db == nil {
+		return
+	}
+	var t model.Task
+	if err := ts.db.Select("id", "status").Where("id = ? AND is_delete = 0", taskID).First(&t).Error; err != nil {
+		return
+	}
+	now := time.Now()
+	updates := map[string]interface{}{
+		"retry_count":      pending.Attempt,
+		"error_message":    fmt.Sprintf("%v", execErr),
+		"next_execute_at":  &pending.RetryAt,
+		"last_executed_at": &now,
+		"update_time":      now,
+	}
+	if t.Status == 4 {
+		// 执行期间被暂停：补发执行时会被跳过
+		updates["next_execute_at"] = nil
+	}
+	_ = ts.db.Model(&model.Task{}).Where("id = ?", taskID).Updates(updates).Error
 }
 
-// EnqueueTask 添加任务到队列
-func (ts *JobService) EnqueueTask(taskType string, payload string) (*asynq.TaskInfo, error) {
+// updateTaskOnManualRun 手动触发执行结束：只记录执行次数/时间与错误，不改变状态、重试计数和下一次执行时间
+func (ts *JobService) updateTaskOnManualRun(taskID uint64, execErr error) {
+	if ts.db == nil {
+		return
+	}
+	now := time.Now()
+	updates := map[string]interface{}{
+		"last_executed_at": &now,
+		"update_time":      now,
+	}
+	if execErr != nil {
+		updates["error_message"] = fmt.Sprintf("手动触发失败: %v", execErr)
+	} else {
+		updates["execute_count"] = gorm.Expr("execute_count + 1")
+	}
+	_ = ts.db.Model(&model.Task{}).Where("id = ? AND is_delete = 0", taskID).Updates(updates).Error
+}
+
+// updateTaskOnDeferredRun 限流/静默期补发及手动触发的失败补发结束：补发属于原运行的一部分，只在失败时记录错误
+func (ts *JobService) updateTaskOnDeferredRun(taskID uint64, execErr error) {
+	if ts.db == nil {
+		return
+	}
+	if execErr == nil {
+		// 补发成功即原运行最终成功：清零连续失败次数
+		ts.resetFailureStreak(taskID)
+		return
+	}
+	now := time.Now()
+	updates := map[string]interface{}{
+		"error_message": fmt.Sprintf("延后补发失败: %v", execErr),
+		"update_time":   now,
+	}
+	_ = ts.db.Model(&model.Task{}).Where("id = ? AND is_delete = 0", taskID).Updates(updates).Error
+}
+
+// retryDelay asynq 重试间隔：按 payload 中记录的任务重试策略计算，失败补发延续原运行的重试次数；系统任务使用 asynq 默认策略
+func (ts *JobService) retryDelay(n int, e error, task *asynq.Task) time.Duration {
+	if p, err := DecodeJobPayload(task.Payload()); err == nil && p.TaskID > 0 {
+		return p.RetryDelay(n)
+	}
+	return asynq.DefaultRetryDelayFunc(n, e, task)
+}
+
+// EnqueueTask 添加任务到队列（立即执行）
+func (ts *JobService) EnqueueTask(taskType string, payload string, opts ...asynq.Option) (*asynq.TaskInfo, error) {
 	if ts.client == nil {
 		return nil, fmt.Errorf("client not initialized")
 	}
 
 	task := asynq.NewTask(taskType, []byte(payload))
-	return ts.client.Enqueue(task)
+	return ts.client.Enqueue(task, opts...)
 }
 
 // ScheduleTask 计划任务
@@ -686,66 +817,3 @@ func (ts *JobService) ScheduleTaskWithID(taskType string, payload string, proces
     options = append(options, opts...)
     return ts.client.Enqueue(task, options...)
 }
-
-// AddCronTask 添加周期性任务
-func (ts *JobService) AddCronTask(cronExpr, taskType string, payload string, opts ...asynq.Option) (string, error) {
-	if ts.scheduler == nil {
-		return "", fmt.Errorf("scheduler not initialized")
-	}
-
-	// 严格只支持5字段（分钟 小时 日 月 周），不再自动兼容6字段
-	fields := strings.Fields(cronExpr)
-	if len(fields) != 5 {
-		return "", fmt.Errorf("cron表达式格式错误: 仅支持标准5字段，实际%d字段: %s", len(fields), cronExpr)
-	}
-
-	// 使用robfig/cron进行5字段语法校验，提前拦截包含 '?' 等Quartz语法
-	p := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
-	if _, err := p.Parse(cronExpr); err != nil {
-		logger.System("cron表达式解析失败", "error", err, "cronExpr", cronExpr)
-		return "", fmt.Errorf("无效的cron表达式: %v", err)
-	}
-
-    task := asynq.NewTask(taskType, []byte(payload))
-    entryID, err := ts.scheduler.Register(cronExpr, task, opts...)
-	if err != nil {
-		logger.System("注册周期任务失败", "error", err, "cronExpr", cronExpr, "taskType", taskType)
-		return "", fmt.Errorf("register periodic task failed: %w", err)
-	}
-
-	logger.System("注册周期任务成功", "cronExpr", cronExpr, "taskType", taskType, "entryID", entryID)
-
-	// 验证 Handler 是否已注册
-	if _, ok := ts.GetHandler(taskType); !ok {
-		logger.System("错误: 任务类型 %s 没有对应的 Handler，cron 任务将无法执行", taskType)
-		// 移除刚注册的任务
-		ts.scheduler.Unregister(entryID)
-		return "", fmt.Errorf("no handler registered for task type: %s", taskType)
-	}
-
-	return entryID, nil
-}
-
-// RemoveCronTask 移除周期性任务
-func (ts *JobService) RemoveCronTask(entryID string) error {
-	if ts.scheduler == nil {
-		return fmt.Errorf("scheduler not initialized")
-	}
-
-	err := ts.scheduler.Unregister(entryID)
-	if err != nil {
-		return fmt.Errorf("unregister periodic task failed: %w", err)
-	}
-
-	logger.System("移除周期任务成功", "entryID", entryID)
-	return nil
-}
-
-// CreateJSONPayload 创建JSON格式的payload字符串
-func CreateJSONPayload(data interface{}) (string, error) {
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return "", fmt.Errorf("marshal payload to JSON failed: %w", err)
-	}
-	return string(jsonData), nil
-}
@@ -0,0 +1,164 @@
+package job
+
+import (
+	"app/tools/telegram"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Telegram 限流默认值：单个机器人约30条/秒，单个群组约20条/分钟
+const (
+	defaultBotRatePerSecond   = 30
+	defaultChatRatePerMinute  = 20
+	defaultThrottleMaxWait    = 5 * time.Second
+	rateLimitKeyPrefix        = "tg:ratelimit:"
+	inlineRetryAfterThreshold = 3 * time.Second
+)
+
+// 令牌桶：同时检查机器人与群组两个桶及群组冷却期（429 retry_after），全部可用时各扣 n 个令牌（超过桶容量时按容量计）
+// KEYS[1] 机器人桶 KEYS[2] 群组桶 KEYS[3] 群组冷却
+// ARGV: now_ms, bot_rate(个/ms), bot_burst, chat_rate(个/ms), chat_burst, n
+// 返回需等待的毫秒数，0 表示已获取
+var tokenBucketScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local n = tonumber(ARGV[6]) or 1
+local wait = 0
+local cooldown = redis.call("PTTL", KEYS[3])
+if cooldown > 0 then wait = cooldown end
+local state = {}
+for i = 1, 2 do
+  local rate = tonumber(ARGV[i * 2])
+  local burst = tonumber(ARGV[i * 2 + 1])
+  local need = math.min(n, burst)
+  local v = redis.call("HMGET", KEYS[i], "tokens", "ts")
+  local tokens = tonumber(v[1]) or burst
+  local ts = tonumber(v[2]) or now
+  tokens = math.min(burst, tokens + (now - ts) * rate)
+  if tokens < need then
+    wait = math.max(wait, math.ceil((need - tokens) / rate))
+  end
+  state[i] = {tokens, rate, burst, need}
+end
+if wait > 0 then return wait end
+for i = 1, 2 do
+  redis.call("HSET", KEYS[i], "tokens", state[i][1] - state[i][4], "ts", now)
+  redis.call("PEXPIRE", KEYS[i], math.ceil(state[i][3] / state[i][2]) + 1000)
+end
+return 0`)
+
+// RateLimitedError 投递被限流，需在 RetryAfter 后重新投递
+type RateLimitedError struct {
+	RetryAfter time.Duration
+	Reason     string
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("%s，%s后重新投递", e.Reason, e.RetryAfter.Round(time.Second))
+}
+
+// deliveryThrottler 基于Redis的发送限流器，按机器人token与群组ID分别限流（多实例共享）
+type deliveryThrottler struct {
+	rdb       *redis.Client
+	botRate   float64 // 每毫秒令牌数
+	botBurst  int
+	chatRate  float64
+	chatBurst int
+	maxWait   time.Duration // 单次发送最多等待的时长，超过则延后投递
+}
+
+func newDeliveryThrottler(rdb *redis.Client, botPerSecond, chatPerMinute int, maxWait time.Duration) *deliveryThrottler {
+	if botPerSecond <= 0 {
+		botPerSecond = defaultBotRatePerSecond
+	}
+	if chatPerMinute <= 0 {
+		chatPerMinute = defaultChatRatePerMinute
+	}
+	if maxWait <= 0 {
+		maxWait = defaultThrottleMaxWait
+	}
+	return &deliveryThrottler{
+		rdb:       rdb,
+		botRate:   float64(botPerSecond) / 1000,
+		botBurst:  botPerSecond,
+		chatRate:  float64(chatPerMinute) / 60000,
+		chatBurst: chatPerMinute,
+		maxWait:   maxWait,
+	}
+}
+
+// botKey 机器人标识：token 中冒号前的 bot id，避免在Redis键中保存完整token
+func botKey(token string) string {
+	if i := strings.Index(token, ":"); i > 0 {
+		return token[:i]
+	}
+	return token
+}
+
+func (t *deliveryThrottler) keys(token string, chatID int64) []string {
+	bot := botKey(token)
+	return []string{
+		rateLimitKeyPrefix + "bot:" + bot,
+		fmt.Sprintf("%schat:%s:%d", rateLimitKeyPrefix, bot, chatID),
+		fmt.Sprintf("%scooldown:%s:%d", rateLimitKeyPrefix, bot, chatID),
+	}
+}
+
+// Wait 获取 n 条消息的发送配额（媒体组按媒体数量计算）；需等待的时长不超过 maxWait 时阻塞等待，否则返回 *RateLimitedError
+// Redis不可用时不限流，避免阻断投递
+func (t *deliveryThrottler) Wait(ctx context.Context, token string, chatID int64, n int) error {
+	if t == nil || t.rdb == nil {
+		return nil
+	}
+	keys := t.keys(token, chatID)
+	deadline := time.Now().Add(t.maxWait)
+	for {
+		wait, err := tokenBucketScript.Run(ctx, t.rdb, keys, time.Now().UnixMilli(), t.botRate, t.botBurst, t.chatRate, t.chatBurst, n).Int64()
+		if err != nil {
+			return nil
+		}
+		if wait <= 0 {
+			return nil
+		}
+		d := time.Duration(wait) * time.Millisecond
+		if time.Now().Add(d).After(deadline) {
+			return &RateLimitedError{RetryAfter: d, Reason: "发送频率超过限制"}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
+	}
+}
+
+// Cooldown 记录 Telegram 返回的 retry_after，期间该群组的发送全部等待
+func (t *deliveryThrottler) Cooldown(ctx context.Context, token string, chatID int64, d time.Duration) {
+	if t == nil || t.rdb == nil || d <= 0 {
+		return
+	}
+	t.rdb.Set(ctx, t.keys(token, chatID)[2], 1, d)
+}
+
+// Call 在限流下执行一次发送 n 条消息的 Bot API 调用；遇到 429 时记录冷却期，短冷却原地等待重试一次，否则返回 *RateLimitedError
+func (t *deliveryThrottler) Call(ctx context.Context, token string, chatID int64, n int, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		if err := t.Wait(ctx, token, chatID, n); err != nil {
+			return err
+		}
+		err := fn()
+		var apiErr *telegram.APIError
+		if !errors.As(err, &apiErr) || apiErr.RetryAfter <= 0 {
+			return err
+		}
+		retryAfter := time.Duration(apiErr.RetryAfter) * time.Second
+		t.Cooldown(ctx, token, chatID, retryAfter)
+		if attempt > 0 || retryAfter > inlineRetryAfterThreshold {
+			return &RateLimitedError{RetryAfter: retryAfter, Reason: fmt.Sprintf("Telegram限流(retry_after=%ds)", apiErr.RetryAfter)}
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package job
+
+import (
+	"app/internal/config"
+	"app/internal/model"
+	"app/tools/logger"
+	"app/tools/random"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+// WebhookType webhook 投递任务类型
+const WebhookType = "webhook_delivery"
+
+// 签名相关请求头：X-Webhook-Signature = "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body))
+const (
+	WebhookHeaderEvent     = "X-Webhook-Event"
+	WebhookHeaderEventID   = "X-Webhook-Event-Id"
+	WebhookHeaderDelivery  = "X-Webhook-Delivery"
+	WebhookHeaderTimestamp = "X-Webhook-Timestamp"
+	WebhookHeaderSignature = "X-Webhook-Signature"
+)
+
+const (
+	defaultWebhookTimeout  = 10
+	defaultWebhookMaxRetry = 5
+	webhookResponseMaxLen  = 1000
+)
+
+// WebhookEvent 推送给订阅方的请求体
+type WebhookEvent struct {
+	ID         string                 `json:"id"`
+	Event      string                 `json:"event"`
+	OccurredAt time.Time              `json:"occurredAt"`
+	Task       WebhookTask            `json:"task"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+}
+
+// WebhookTask 事件中的任务摘要
+type WebhookTask struct {
+	ID          uint64 `json:"id"`
+	Name        string `json:"name"`
+	TriggerType string `json:"triggerType"`
+	Status      int    `json:"status"`
+}
+
+// errWebhookBlockedAddress 回调地址解析到内网/回环/链路本地等地址
+var errWebhookBlockedAddress = errors.New("禁止投递到内网地址")
+
+// WebhookHandler 投递 webhook：非 2xx 响应按 asynq 重试，4xx（408/429 除外）视为不可重试
+type WebhookHandler struct {
+	db  *gorm.DB
+	cli *http.Client
+}
+
+func NewWebhookHandler(jobService *JobService, db *gorm.DB, conf *config.Config) {
+	timeout := config.Get[int](conf, "webhook", "timeout")
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	jobService.webhookMaxRetry = config.Get[int](conf, "webhook", "max_retry")
+	if jobService.webhookMaxRetry <= 0 {
+		jobService.webhookMaxRetry = defaultWebhookMaxRetry
+	}
+	dialer := &net.Dialer{Timeout: time.Duration(timeout) * time.Second}
+	if !config.Get[bool](conf, "webhook", "allow_private") {
+		dialer.Control = webhookDialControl
+	}
+	jobService.RegisterHandler(&WebhookHandler{
+		db: db,
+		cli: &http.Client{
+			Timeout: time.Duration(timeout) * time.Second,
+			// 不走环境代理：否则拨号校验的是代理地址
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 10 * time.Second,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+	})
+}
+
+// webhookDialControl 在建立连接前校验实际连接的IP（含重定向），解析结果在校验后无法再被替换，可防御DNS重绑定
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !webhookPublicIP(ip) {
+		return fmt.Errorf("%w: %s", errWebhookBlockedAddress, host)
+	}
+	return nil
+}
+
+// webhookPublicIP 是否为公网地址：排除回环、私有网段、链路本地、组播、未指定地址及运营商级NAT(100.64.0.0/10)
+func webhookPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64 {
+		return false
+	}
+	return true
+}
+
+func (h *WebhookHandler) TaskType() string {
+	return WebhookType
+}
+
+func (h *WebhookHandler) Process(ctx context.Context, payload []byte) error {
+	p, err := DecodeJobPayload(payload)
+	if err != nil || p.Ref == 0 {
+		return fmt.Errorf("webhook payload格式错误: %w", asynq.SkipRetry)
+	}
+	var d model.WebhookDelivery
+	if err := h.db.Where("id = ?", p.Ref).First(&d).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("webhook投递记录不存在(ID=%d): %w", p.Ref, asynq.SkipRetry)
+		}
+		return err
+	}
+	if d.Status == model.WebhookDeliverySuccess {
+		return nil
+	}
+	var hook model.Webhook
+	if err := h.db.Where("id = ? AND is_delete = 0", d.WebhookID).First(&hook).Error; err != nil {
+		h.finish(&d, model.WebhookDeliveryFailed, 0, "", "webhook不存在或已删除", 0)
+		return fmt.Errorf("webhook不存在(ID=%d): %w", d.WebhookID, asynq.SkipRetry)
+	}
+
+	retried, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	code, body, duration, err := h.post(ctx, &hook, &d)
+	if err == nil {
+		h.finish(&d, model.WebhookDeliverySuccess, code, body, "", duration)
+		logger.System("webhook投递成功", "webhookID", hook.ID, "deliveryID", d.ID, "event", d.Event, "code", code)
+		return nil
+	}
+	status := model.WebhookDeliveryPending
+	if retried >= maxRetry || errors.Is(err, asynq.SkipRetry) {
+		status = model.WebhookDeliveryFailed
+	}
+	h.finish(&d, status, code, body, err.Error(), duration)
+	logger.Error("webhook投递失败", "webhookID", hook.ID, "deliveryID", d.ID, "event", d.Event, "code", code, "error", err)
+	return err
+}
+
+// post 发送签名请求，返回响应码、截断后的响应内容与耗时
+func (h *WebhookHandler) post(ctx context.Context, hook *model.Webhook, d *model.WebhookDelivery) (int, string, time.Duration, error) {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader([]byte(d.Payload)))
+	if err != nil {
+		return 0, "", 0, fmt.Errorf("构建请求失败: %v: %w", err, asynq.SkipRetry)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tg-task-webhook/1.0")
+	req.Header.Set(WebhookHeaderEvent, d.Event)
+	req.Header.Set(WebhookHeaderEventID, d.EventID)
+	req.Header.Set(WebhookHeaderDelivery, strconv.FormatUint(d.ID, 10))
+	req.Header.Set(WebhookHeaderTimestamp, ts)
+	req.Header.Set(WebhookHeaderSignature, SignWebhook(hook.Secret, ts, []byte(d.Payload)))
+
+	start := time.Now()
+	res, err := h.cli.Do(req)
+	duration := time.Since(start)
+	if errors.Is(err, errWebhookBlockedAddress) {
+		return 0, "", duration, fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+	if err != nil {
+		return 0, "", duration, err
+	}
+	defer res.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(res.Body, webhookResponseMaxLen*4))
+	body := string(raw)
+	if utf8.RuneCountInString(body) > webhookResponseMaxLen {
+		body = string([]rune(body)[:webhookResponseMaxLen])
+	}
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		return res.StatusCode, body, duration, nil
+	case res.StatusCode >= 400 && res.StatusCode < 500 && res.StatusCode != http.StatusRequestTimeout && res.StatusCode != http.StatusTooManyRequests:
+		return res.StatusCode, body, duration, fmt.Errorf("响应状态码%d: %w", res.StatusCode, asynq.SkipRetry)
+	default:
+		return res.StatusCode, body, duration, fmt.Errorf("响应状态码%d", res.StatusCode)
+	}
+}
+
+func (h *WebhookHandler) finish(d *model.WebhookDelivery, status, code int, body, errMsg string, duration time.Duration) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":        status,
+		"attempts":      gorm.Expr("attempts + 1"),
+		"response_code": code,
+		"response_body": body,
+		"error_message": errMsg,
+		"duration_ms":   duration.Milliseconds(),
+		"update_time":   now,
+	}
+	if status == model.WebhookDeliverySuccess {
+		updates["delivered_at"] = &now
+	}
+	if err := h.db.Model(&model.WebhookDelivery{}).Where("id = ?", d.ID).Updates(updates).Error; err != nil {
+		logger.Error("更新webhook投递记录失败", "deliveryID", d.ID, "error", err)
+	}
+}
+
+// SignWebhook 计算签名：sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookSubscribed 判断 webhook 是否订阅了事件；未指定事件列表表示订阅全部
+func WebhookSubscribed(hook *model.Webhook, event string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+	var events []string
+	if err := json.Unmarshal(hook.Events, &events); err != nil || len(events) == 0 {
+		return true
+	}
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// EmitTaskEvent 向任务所属管理员订阅了该事件的 webhook 投递任务生命周期事件
+func (ts *JobService) EmitTaskEvent(t *model.Task, event string, data map[string]interface{}) {
+	if ts.db == nil || t == nil || t.ID == 0 {
+		return
+	}
+	var hooks []model.Webhook
+	if err := ts.db.Where("admin_id = ? AND enabled = 1 AND is_delete = 0", t.AdminID).Find(&hooks).Error; err != nil {
+		logger.Error("查询webhook失败", "taskID", t.ID, "event", event, "error", err)
+		return
+	}
+	if len(hooks) == 0 {
+		return
+	}
+	eventID, _ := random.GenerateSaltHex(16)
+	body, err := json.Marshal(WebhookEvent{
+		ID:         eventID,
+		Event:      event,
+		OccurredAt: time.Now(),
+		Task: WebhookTask{
+			ID:          t.ID,
+			Name:        t.TaskName,
+			TriggerType: string(t.TriggerType),
+			Status:      t.Status,
+		},
+		Data: data,
+	})
+	if err != nil {
+		logger.Error("webhook事件序列化失败", "taskID", t.ID, "event", event, "error", err)
+		return
+	}
+	for i := range hooks {
+		if !WebhookSubscribed(&hooks[i], event) {
+			continue
+		}
+		now := time.Now()
+		d := &model.WebhookDelivery{
+			WebhookID:  hooks[i].ID,
+			AdminID:    t.AdminID,
+			TaskID:     t.ID,
+			Event:      event,
+			EventID:    eventID,
+			Payload:    string(body),
+			Status:     model.WebhookDeliveryPending,
+			CreateTime: now,
+			UpdateTime: now,
+		}
+		if err := ts.EnqueueWebhookDelivery(d); err != nil {
+			logger.Error("webhook投递入队失败", "webhookID", hooks[i].ID, "taskID", t.ID, "event", event, "error", err)
+		}
+	}
+}
+
+// EnqueueWebhookDelivery 写入投递记录并入队；重放时传入复制了原事件的新记录
+func (ts *JobService) EnqueueWebhookDelivery(d *model.WebhookDelivery) error {
+	if err := ts.db.Create(d).Error; err != nil {
+		return err
+	}
+	maxRetry := ts.webhookMaxRetry
+	if maxRetry <= 0 {
+		maxRetry = defaultWebhookMaxRetry
+	}
+	payload := NewJobPayload(WebhookType, 0, nil)
+	payload.Ref = d.ID
+	if _, err := ts.EnqueueTask(WebhookType, payload.Encode(), asynq.MaxRetry(maxRetry)); err != nil {
+		_ = ts.db.Model(d).Updates(map[string]interface{}{"status": model.WebhookDeliveryFailed, "error_message": "入队失败: " + err.Error()}).Error
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"app/internal/config"
+	"app/tools/resp"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OperatorOnly 仅允许运维管理员访问（需在JWT中间件之后使用）
+func OperatorOnly(adminIDs []uint) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !slices.Contains(adminIDs, c.GetUint(config.CurrentUserId)) {
+			(&resp.JsonResp{Code: resp.ReAuthFail, Msg: "无权限访问运维接口"}).Response()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// 告警类型
+const (
+	AlertKindFailure     = "failure"     // 任务执行失败（本次运行重试耗尽）
+	AlertKindConsecutive = "consecutive" // 任务连续失败达到阈值
+	AlertKindExpired     = "expired"     // 周期任务到期
+	AlertKindDigest      = "digest"      // 每日汇总
+	AlertKindTest        = "test"        // 测试告警
+)
+
+// 告警发送状态
+const (
+	AlertStatusPending    = 0 // 待发送
+	AlertStatusSent       = 1 // 已发送
+	AlertStatusFailed     = 2 // 发送失败
+	AlertStatusSuppressed = 3 // 超过频率上限，未发送
+)
+
+// AdminAlertConfig 管理员告警配置：通过自己的机器人私聊接收告警
+type AdminAlertConfig struct {
+	ID                  uint64    `json:"id" gorm:"primaryKey;type:BIGINT UNSIGNED NOT NULL AUTO_INCREMENT;comment:主键ID"`
+	AdminID             uint      `json:"adminId" gorm:"type:BIGINT NOT NULL;uniqueIndex;comment:管理员ID"`
+	BotConfigID         uint      `json:"botConfigId" gorm:"type:BIGINT UNSIGNED NOT NULL;comment:用于发送告警的机器人配置ID"`
+	ChatID              int64     `json:"chatId" gorm:"type:BIGINT NOT NULL;comment:接收告警的私聊ID（需先与机器人私聊）"`
+	Enabled             bool      `json:"enabled" gorm:"type:TINYINT(1) NOT NULL;default:1;comment:是否启用"`
+	OnFailure           bool      `json:"onFailure" gorm:"type:TINYINT(1) NOT NULL;default:1;comment:任务执行失败时告警"`
+	ConsecutiveFailures int       `json:"consecutiveFailures" gorm:"type:INT NOT NULL;default:0;comment:连续失败N次时告警，0-不启用"`
+	OnExpire            bool      `json:"onExpire" gorm:"type:TINYINT(1) NOT NULL;default:0;comment:周期任务到期时告警"`
+	DailyDigest         bool      `json:"dailyDigest" gorm:"type:TINYINT(1) NOT NULL;default:0;comment:是否发送每日汇总"`
+	DigestHour          int       `json:"digestHour" gorm:"type:INT NOT NULL;default:9;comment:每日汇总发送时间（0-23点，服务时区）"`
+	CreateTime          time.Time `json:"createTime" gorm:"type:DATETIME NOT NULL;comment:创建时间"`
+	UpdateTime          time.Time `json:"updateTime" gorm:"type:DATETIME NOT NULL;comment:更新时间"`
+}
+
+func (AdminAlertConfig) TableName() string {
+	return "admin_alert_config"
+}
+
+// AdminAlert 告警记录
+type AdminAlert struct {
+	ID           uint64     `json:"id" gorm:"primaryKey;type:BIGINT UNSIGNED NOT NULL AUTO_INCREMENT;comment:主键ID"`
+	AdminID      uint       `json:"adminId" gorm:"type:BIGINT NOT NULL;comment:管理员ID"`
+	TaskID       uint64     `json:"taskId" gorm:"type:BIGINT UNSIGNED NOT NULL;default:0;comment:关联任务ID，汇总/测试告警为0"`
+	Kind         string     `json:"kind" gorm:"type:VARCHAR(32) NOT NULL;comment:告警类型：failure/consecutive/expired/digest/test"`
+	Content      string     `json:"content" gorm:"type:TEXT;comment:告警内容"`
+	Status       int        `json:"status" gorm:"type:INT NOT NULL;default:0;comment:状态：0-待发送，1-已发送，2-发送失败，3-超过频率上限"`
+	ErrorMessage string     `json:"errorMessage" gorm:"type:TEXT;comment:发送失败原因"`
+	SentAt       *time.Time `json:"sentAt" gorm:"type:DATETIME;comment:发送时间"`
+	CreateTime   time.Time  `json:"createTime" gorm:"type:DATETIME NOT NULL;comment:创建时间"`
+}
+
+func (AdminAlert) TableName() string {
+	return "admin_alert"
+}
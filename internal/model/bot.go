@@ -15,6 +15,7 @@ type BotConfig struct {
 	GroupID    int64          `gorm:"uniqueIndex;not null"`
 	Config     datatypes.JSON `gorm:"type:json column:config;default:{}"`
 	Features   datatypes.JSON `gorm:"type:json column:features;default:[]"`
+	SendWindow datatypes.JSON `gorm:"type:json;column:send_window"` // 发送时段与静默期（SendWindow），为空表示不限制
 	CreateTime time.Time      `gorm:"column:create_time;autoCreateTime"`
 	UpdateTime time.Time      `gorm:"column:update_time;autoUpdateTime"`
 }
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 静默期内到点的投递处理策略
+const (
+	QuietPolicyDefer = "defer" // 延后到下一个可发送时间
+	QuietPolicySkip  = "skip"  // 跳过本次投递
+)
+
+// TimeRange 每日时段（HH:MM，左闭右开）；开始晚于结束表示跨零点，如 22:00-06:00
+type TimeRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// SendWindow 群组的发送时段与静默期，按群组所在时区计算
+//   - Windows: 允许发送的时段，为空表示全天可发送
+//   - Blackouts: 静默时段，优先于 Windows
+type SendWindow struct {
+	Timezone  string      `json:"timezone"`
+	Windows   []TimeRange `json:"windows"`
+	Blackouts []TimeRange `json:"blackouts"`
+	Policy    string      `json:"policy"`
+}
+
+// parseClock 解析 HH:MM 为当天分钟数
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("时间格式错误（应为HH:MM）: %s", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (r TimeRange) bounds() (int, int, error) {
+	start, err := parseClock(r.Start)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(r.End)
+	if err != nil {
+		return 0, 0, err
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("时段开始与结束时间相同: %s-%s", r.Start, r.End)
+	}
+	return start, end, nil
+}
+
+func (r TimeRange) contains(minute int) bool {
+	start, end, err := r.bounds()
+	if err != nil {
+		return false
+	}
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// Validate 校验时段格式、时区与策略；策略为空时默认 defer
+func (w *SendWindow) Validate() error {
+	for _, r := range append(append([]TimeRange{}, w.Windows...), w.Blackouts...) {
+		if _, _, err := r.bounds(); err != nil {
+			return err
+		}
+	}
+	if w.Timezone != "" {
+		if _, err := time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("无效的时区: %s", w.Timezone)
+		}
+	}
+	switch w.Policy {
+	case "":
+		w.Policy = QuietPolicyDefer
+	case QuietPolicyDefer, QuietPolicySkip:
+	default:
+		return fmt.Errorf("无效的静默期策略: %s", w.Policy)
+	}
+	if _, ok := w.NextAllowed(time.Now()); !ok {
+		return errors.New("发送时段被静默期完全覆盖，群组将无法接收消息")
+	}
+	return nil
+}
+
+// Allowed 判断 t 时刻是否允许发送
+func (w *SendWindow) Allowed(t time.Time) bool {
+	local := t.In(LoadLocation(w.Timezone))
+	minute := local.Hour()*60 + local.Minute()
+	for _, r := range w.Blackouts {
+		if r.contains(minute) {
+			return false
+		}
+	}
+	if len(w.Windows) == 0 {
+		return true
+	}
+	for _, r := range w.Windows {
+		if r.contains(minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextAllowed 从 now 起最近的可发送时间；now 即可发送时返回 now，两天内都不可发送时返回 false
+func (w *SendWindow) NextAllowed(now time.Time) (time.Time, bool) {
+	if w.Allowed(now) {
+		return now, true
+	}
+	// 可发送状态只会在发送时段开始或静默期结束时改变
+	loc := LoadLocation(w.Timezone)
+	local := now.In(loc)
+	var candidates []time.Time
+	for day := 0; day <= 2; day++ {
+		date := local.AddDate(0, 0, day)
+		for _, r := range w.Windows {
+			if m, err := parseClock(r.Start); err == nil {
+				candidates = append(candidates, wallClock(date, m, loc))
+			}
+		}
+		for _, r := range w.Blackouts {
+			if m, err := parseClock(r.End); err == nil {
+				candidates = append(candidates, wallClock(date, m, loc))
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	for _, c := range candidates {
+		if c.After(now) && w.Allowed(c) {
+			return c, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// wallClock date 当天第 m 分钟对应的时刻；该时刻因夏令时跳变不存在时（如 02:30）取跳变发生的时刻
+func wallClock(date time.Time, m int, loc *time.Location) time.Time {
+	c := time.Date(date.Year(), date.Month(), date.Day(), m/60, m%60, 0, 0, loc)
+	want := time.Date(date.Year(), date.Month(), date.Day(), m/60, m%60, 0, 0, time.UTC)
+	got := time.Date(c.Year(), c.Month(), c.Day(), c.Hour(), c.Minute(), 0, 0, time.UTC)
+	if got.Equal(want) {
+		return c
+	}
+	start, end := c.ZoneBounds()
+	if got.After(want) {
+		return start
+	}
+	return end
+}
@@ -0,0 +1,196 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("时区数据不可用: %s", name)
+	}
+	return loc
+}
+
+func TestSendWindowAllowed(t *testing.T) {
+	shanghai := mustLoadLocation(t, "Asia/Shanghai")
+	newYork := mustLoadLocation(t, "America/New_York")
+	at := func(loc *time.Location, hour, minute int) time.Time {
+		return time.Date(2026, 1, 15, hour, minute, 0, 0, loc)
+	}
+
+	workHours := SendWindow{Timezone: "Asia/Shanghai", Windows: []TimeRange{{"09:00", "18:00"}}}
+	nightBlackout := SendWindow{Timezone: "Asia/Shanghai", Blackouts: []TimeRange{{"22:00", "06:00"}}}
+	lunchBlackout := SendWindow{Timezone: "Asia/Shanghai", Windows: []TimeRange{{"08:00", "23:00"}}, Blackouts: []TimeRange{{"12:00", "13:00"}}}
+	nightWindow := SendWindow{Timezone: "Asia/Shanghai", Windows: []TimeRange{{"22:00", "02:00"}}}
+	newYorkHours := SendWindow{Timezone: "America/New_York", Windows: []TimeRange{{"09:00", "18:00"}}}
+
+	tests := []struct {
+		name   string
+		window SendWindow
+		t      time.Time
+		want   bool
+	}{
+		{"未配置全天可发送", SendWindow{}, at(shanghai, 3, 0), true},
+		{"发送时段开始前", workHours, at(shanghai, 8, 59), false},
+		{"发送时段开始（左闭）", workHours, at(shanghai, 9, 0), true},
+		{"发送时段结束前", workHours, at(shanghai, 17, 59), true},
+		{"发送时段结束（右开）", workHours, at(shanghai, 18, 0), false},
+		{"跨零点静默期开始前", nightBlackout, at(shanghai, 21, 59), true},
+		{"跨零点静默期开始", nightBlackout, at(shanghai, 22, 0), false},
+		{"跨零点静默期零点", nightBlackout, at(shanghai, 0, 0), false},
+		{"跨零点静默期结束前", nightBlackout, at(shanghai, 5, 59), false},
+		{"跨零点静默期结束", nightBlackout, at(shanghai, 6, 0), true},
+		{"静默期优先于发送时段", lunchBlackout, at(shanghai, 12, 30), false},
+		{"静默期结束后回到发送时段", lunchBlackout, at(shanghai, 13, 0), true},
+		{"跨零点发送时段零点前", nightWindow, at(shanghai, 23, 0), true},
+		{"跨零点发送时段零点后", nightWindow, at(shanghai, 1, 59), true},
+		{"跨零点发送时段结束", nightWindow, at(shanghai, 2, 0), false},
+		{"按群组时区计算（冬令时）", newYorkHours, time.Date(2026, 1, 15, 14, 0, 0, 0, time.UTC), true},
+		{"按群组时区计算（冬令时开始前）", newYorkHours, time.Date(2026, 1, 15, 13, 59, 0, 0, time.UTC), false},
+		{"按群组时区计算（夏令时）", newYorkHours, time.Date(2026, 7, 15, 13, 0, 0, 0, time.UTC), true},
+		{"其他时区的时刻", newYorkHours, at(newYork, 8, 0).In(shanghai), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Allowed(tt.t); got != tt.want {
+				t.Errorf("Allowed(%s) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendWindowNextAllowed(t *testing.T) {
+	shanghai := mustLoadLocation(t, "Asia/Shanghai")
+	newYork := mustLoadLocation(t, "America/New_York")
+	santiago := mustLoadLocation(t, "America/Santiago")
+
+	tests := []struct {
+		name   string
+		window SendWindow
+		now    time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		{
+			name:   "当前可发送",
+			window: SendWindow{Timezone: "Asia/Shanghai", Windows: []TimeRange{{"09:00", "18:00"}}},
+			now:    time.Date(2026, 1, 15, 10, 0, 0, 0, shanghai),
+			want:   time.Date(2026, 1, 15, 10, 0, 0, 0, shanghai),
+			wantOK: true,
+		},
+		{
+			name:   "发送时段开始前顺延到当天",
+			window: SendWindow{Timezone: "Asia/Shanghai", Windows: []TimeRange{{"09:00", "18:00"}}},
+			now:    time.Date(2026, 1, 15, 8, 0, 0, 0, shanghai),
+			want:   time.Date(2026, 1, 15, 9, 0, 0, 0, shanghai),
+			wantOK: true,
+		},
+		{
+			name:   "发送时段结束后顺延到次日",
+			window: SendWindow{Timezone: "Asia/Shanghai", Windows: []TimeRange{{"09:00", "18:00"}}},
+			now:    time.Date(2026, 1, 15, 18, 30, 0, 0, shanghai),
+			want:   time.Date(2026, 1, 16, 9, 0, 0, 0, shanghai),
+			wantOK: true,
+		},
+		{
+			name:   "跨零点静默期顺延到次日结束时间",
+			window: SendWindow{Timezone: "Asia/Shanghai", Blackouts: []TimeRange{{"22:00", "06:00"}}},
+			now:    time.Date(2026, 1, 15, 23, 30, 0, 0, shanghai),
+			want:   time.Date(2026, 1, 16, 6, 0, 0, 0, shanghai),
+			wantOK: true,
+		},
+		{
+			name:   "跨零点静默期跨年",
+			window: SendWindow{Timezone: "Asia/Shanghai", Blackouts: []TimeRange{{"22:00", "06:00"}}},
+			now:    time.Date(2026, 12, 31, 23, 30, 0, 0, shanghai),
+			want:   time.Date(2027, 1, 1, 6, 0, 0, 0, shanghai),
+			wantOK: true,
+		},
+		{
+			name:   "静默期结束仍不在发送时段",
+			window: SendWindow{Timezone: "Asia/Shanghai", Windows: []TimeRange{{"09:00", "18:00"}}, Blackouts: []TimeRange{{"22:00", "06:00"}}},
+			now:    time.Date(2026, 1, 15, 23, 0, 0, 0, shanghai),
+			want:   time.Date(2026, 1, 16, 9, 0, 0, 0, shanghai),
+			wantOK: true,
+		},
+		{
+			name:   "发送时段被静默期完全覆盖",
+			window: SendWindow{Timezone: "Asia/Shanghai", Windows: []TimeRange{{"09:00", "18:00"}}, Blackouts: []TimeRange{{"08:00", "19:00"}}},
+			now:    time.Date(2026, 1, 15, 10, 0, 0, 0, shanghai),
+			wantOK: false,
+		},
+		{
+			// 2026-03-08 02:00 EST 跳至 03:00 EDT，02:30 不存在，静默期在时钟跳变时即已结束
+			name:   "夏令时开始时静默期结束于跳过的时刻",
+			window: SendWindow{Timezone: "America/New_York", Blackouts: []TimeRange{{"00:00", "02:30"}}},
+			now:    time.Date(2026, 3, 8, 1, 30, 0, 0, newYork),
+			want:   time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "夏令时开始时发送时段开始于跳过的时刻",
+			window: SendWindow{Timezone: "America/New_York", Windows: []TimeRange{{"02:15", "05:00"}}},
+			now:    time.Date(2026, 3, 8, 1, 0, 0, 0, newYork),
+			want:   time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			// 2026-11-01 02:00 EDT 回拨至 01:00 EST，01:30 出现两次，取第一次
+			name:   "夏令时结束时静默期结束于重复的时刻",
+			window: SendWindow{Timezone: "America/New_York", Blackouts: []TimeRange{{"00:00", "01:30"}}},
+			now:    time.Date(2026, 11, 1, 0, 30, 0, 0, newYork),
+			want:   time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			// 圣地亚哥在零点进入夏令时，2026-09-06 00:00 直接跳至 01:00
+			name:   "零点跳变时静默期结束于跳过的零点",
+			window: SendWindow{Timezone: "America/Santiago", Blackouts: []TimeRange{{"22:00", "00:00"}}},
+			now:    time.Date(2026, 9, 5, 23, 0, 0, 0, santiago),
+			want:   time.Date(2026, 9, 6, 4, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.window.NextAllowed(tt.now)
+			if ok != tt.wantOK {
+				t.Fatalf("NextAllowed(%s) ok = %v, want %v", tt.now, ok, tt.wantOK)
+			}
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("NextAllowed(%s) = %s, want %s", tt.now, got.UTC(), tt.want.UTC())
+			}
+		})
+	}
+}
+
+func TestSendWindowValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  SendWindow
+		wantErr bool
+	}{
+		{"默认策略", SendWindow{Windows: []TimeRange{{"09:00", "18:00"}}}, false},
+		{"跨零点时段", SendWindow{Blackouts: []TimeRange{{"22:00", "06:00"}}, Policy: QuietPolicySkip}, false},
+		{"时间格式错误", SendWindow{Windows: []TimeRange{{"9点", "18:00"}}}, true},
+		{"24:00无效", SendWindow{Windows: []TimeRange{{"09:00", "24:00"}}}, true},
+		{"开始与结束相同", SendWindow{Blackouts: []TimeRange{{"09:00", "09:00"}}}, true},
+		{"无效时区", SendWindow{Timezone: "Mars/Base"}, true},
+		{"无效策略", SendWindow{Policy: "drop"}, true},
+		{"完全覆盖", SendWindow{Windows: []TimeRange{{"09:00", "18:00"}}, Blackouts: []TimeRange{{"00:00", "23:59"}, {"23:59", "00:00"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && tt.window.Policy == "" {
+				t.Errorf("Validate() 未设置默认策略")
+			}
+		})
+	}
+}
@@ -23,30 +23,124 @@ const (
 	CronPatternCustom  CronPatternType = "custom"
 )
 
+// 错过执行（停机/Redis不可用期间到点未执行）的处理策略
+const (
+	MisfirePolicySkip     = "skip"      // 跳过错过的执行，等待下一次
+	MisfirePolicyFireOnce = "fire_once" // 立即补执行一次
+	MisfirePolicyCatchUp  = "catch_up"  // 按错过次数补执行，不超过上限
+)
+
+// MisfireCatchUpMax 补执行次数上限的最大允许值
+const MisfireCatchUpMax = 20
+
+// 失败重试的退避策略：第 n 次重试的等待时间
+const (
+	RetryPolicyFixed       = "fixed"       // 固定为 base
+	RetryPolicyLinear      = "linear"      // base * n
+	RetryPolicyExponential = "exponential" // base * 2^(n-1)
+)
+
+// 默认退避：指数退避，1分钟起，最长32分钟
+const (
+	DefaultRetryBaseSeconds = 60
+	DefaultRetryMaxSeconds  = 1920
+)
+
+// 消息轮换模式：每次执行从任务消息列表中选取哪些消息发送
+const (
+	RotationModeAll        = "all"         // 每次发送全部消息
+	RotationModeRoundRobin = "round_robin" // 每次按顺序发送一条
+	RotationModeRandom     = "random"      // 每次随机发送一条，不与上一次重复
+	RotationModeWeighted   = "weighted"    // 每次按权重随机发送一条
+)
+
 type Task struct {
-    *MysqlBaseModel `gorm:"-:all"`
-    ID              uint64          `json:"id" gorm:"primaryKey;type:BIGINT UNSIGNED NOT NULL AUTO_INCREMENT;comment:主键ID"`
-    TaskName        string          `json:"taskName" gorm:"type:VARCHAR(50) NOT NULL;comment:任务名称"`
-    Description     string          `json:"description" gorm:"type:TEXT;comment:任务描述"`
-    Status          int             `json:"status" gorm:"type:INT NOT NULL;default:0;comment:任务状态：-1-待提交，0-待执行，1-执行中，2-已完成，3-执行失败"`
-    AdminID         uint            `json:"adminId" gorm:"type:BIGINT NOT NULL;comment:创建者ID"`
-	GroupIDs        JSON            `json:"groupIds" gorm:"type:JSON NOT NULL;comment:群组ID列表，JSON格式存储"`
-	MessageIDs      JSON            `json:"messageIds" gorm:"type:JSON NOT NULL;comment:消息ID列表，JSON格式存储"`
-	TriggerType     TriggerType     `json:"triggerType" gorm:"type:ENUM('schedule','cron') NOT NULL;comment:触发类型：schedule-定时执行，cron-周期执行"`
-	ScheduleTime    *time.Time      `json:"scheduleTime" gorm:"type:DATETIME;comment:定时执行时间，当trigger_type=schedule时使用"`
-	ExpireTime      *time.Time      `json:"expireTime" gorm:"type:DATETIME;comment:任务到期日期"`
-	CronExpression  string          `json:"cronExpression" gorm:"type:VARCHAR(100) NOT NULL;comment:Cron表达式，统一存储所有类型的执行规则"`
-	CronPatternType *CronPatternType `json:"cronPatternType" gorm:"type:ENUM('minute','hour','daily','weekly','monthly','custom');comment:Cron模式类型，用于编辑时回显"`
-	CronConfig      JSON            `json:"cronConfig" gorm:"type:JSON;comment:Cron配置快照，用于编辑时精确回显表单数据"`
-	LastExecutedAt  *time.Time      `json:"lastExecutedAt" gorm:"type:DATETIME;comment:上次执行时间"`
-	NextExecuteAt   *time.Time      `json:"nextExecuteAt" gorm:"type:DATETIME;comment:下次执行时间，由调度系统计算"`
-	ExecuteCount    int             `json:"executeCount" gorm:"type:INT NOT NULL;default:0;comment:已执行次数"`
-	RetryCount      int             `json:"retryCount" gorm:"type:INT NOT NULL;default:0;comment:当前重试次数"`
-	MaxRetryCount   int             `json:"maxRetryCount" gorm:"type:INT NOT NULL;default:3;comment:最大重试次数"`
-    ErrorMessage    string          `json:"errorMessage" gorm:"type:TEXT;comment:错误信息，执行失败时记录"`
-    IsDelete        int             `json:"isDelete" gorm:"type:INT NOT NULL DEFAULT 0;comment:是否删除 0:正常 1:删除"`
-    CreateTime      time.Time       `json:"createTime" gorm:"type:DATETIME NOT NULL;comment:创建时间"`
-    UpdateTime      time.Time       `json:"updateTime" gorm:"type:DATETIME NOT NULL ON UPDATE CURRENT_TIMESTAMP;comment:更新时间"`
+	*MysqlBaseModel     `gorm:"-:all"`
+	ID                  uint64           `json:"id" gorm:"primaryKey;type:BIGINT UNSIGNED NOT NULL AUTO_INCREMENT;comment:主键ID"`
+	TaskName            string           `json:"taskName" gorm:"type:VARCHAR(50) NOT NULL;comment:任务名称"`
+	Description         string           `json:"description" gorm:"type:TEXT;comment:任务描述"`
+	Status              int              `json:"status" gorm:"type:INT NOT NULL;default:0;comment:任务状态：-1-待提交，0-待执行，1-执行中，2-已完成，3-执行失败，4-已暂停"`
+	AdminID             uint             `json:"adminId" gorm:"type:BIGINT NOT NULL;comment:创建者ID"`
+	GroupIDs            JSON             `json:"groupIds" gorm:"type:JSON NOT NULL;comment:群组ID列表，JSON格式存储"`
+	MessageIDs          JSON             `json:"messageIds" gorm:"type:JSON NOT NULL;comment:消息ID列表，JSON格式存储"`
+	TriggerType         TriggerType      `json:"triggerType" gorm:"type:ENUM('schedule','cron') NOT NULL;comment:触发类型：schedule-定时执行，cron-周期执行"`
+	ScheduleTime        *time.Time       `json:"scheduleTime" gorm:"type:DATETIME;comment:定时执行时间，当trigger_type=schedule时使用"`
+	ExpireTime          *time.Time       `json:"expireTime" gorm:"type:DATETIME;comment:任务到期日期"`
+	Timezone            string           `json:"timezone" gorm:"type:VARCHAR(64) NOT NULL;default:'Asia/Shanghai';comment:任务时区（IANA名称），用于Cron计算与时间回显；时间字段统一以UTC存储"`
+	CronExpression      string           `json:"cronExpression" gorm:"type:VARCHAR(100) NOT NULL;comment:Cron表达式，统一存储所有类型的执行规则"`
+	CronPatternType     *CronPatternType `json:"cronPatternType" gorm:"type:ENUM('minute','hour','daily','weekly','monthly','custom');comment:Cron模式类型，用于编辑时回显"`
+	CronConfig          JSON             `json:"cronConfig" gorm:"type:JSON;comment:Cron配置快照，用于编辑时精确回显表单数据"`
+	LastExecutedAt      *time.Time       `json:"lastExecutedAt" gorm:"type:DATETIME;comment:上次执行时间"`
+	NextExecuteAt       *time.Time       `json:"nextExecuteAt" gorm:"type:DATETIME;comment:下次执行时间，由调度系统计算"`
+	ExecuteCount        int              `json:"executeCount" gorm:"type:INT NOT NULL;default:0;comment:已执行次数"`
+	RetryCount          int              `json:"retryCount" gorm:"type:INT NOT NULL;default:0;comment:当前重试次数"`
+	MaxRetryCount       int              `json:"maxRetryCount" gorm:"type:INT NOT NULL;default:3;comment:最大重试次数"`
+	MisfirePolicy       string           `json:"misfirePolicy" gorm:"type:VARCHAR(16) NOT NULL;default:'fire_once';comment:错过执行策略：skip-跳过，fire_once-补执行一次，catch_up-按次补执行"`
+	MisfireCatchUpLimit int              `json:"misfireCatchUpLimit" gorm:"type:INT NOT NULL;default:5;comment:catch_up策略下的补执行次数上限"`
+	Priority            string           `json:"priority" gorm:"type:VARCHAR(16) NOT NULL;default:'normal';comment:优先级：high-高，normal-普通，low-低，对应不同的asynq队列"`
+	RetryPolicy         string           `json:"retryPolicy" gorm:"type:VARCHAR(16) NOT NULL;default:'exponential';comment:重试退避策略：fixed-固定，linear-线性，exponential-指数"`
+	RetryBaseSeconds    int              `json:"retryBaseSeconds" gorm:"type:INT NOT NULL;default:60;comment:重试退避基准时长（秒）"`
+	RetryMaxSeconds     int              `json:"retryMaxSeconds" gorm:"type:INT NOT NULL;default:1920;comment:重试退避上限（秒）"`
+	RotationMode        string           `json:"rotationMode" gorm:"type:VARCHAR(16) NOT NULL;default:'all';comment:消息轮换模式：all-全部发送，round_robin-顺序轮换，random-随机（不连续重复），weighted-按权重随机"`
+	RotationWeights     JSON             `json:"rotationWeights" gorm:"type:JSON;comment:weighted模式下各消息的权重，JSON格式存储：{消息ID: 权重}"`
+	ErrorMessage        string           `json:"errorMessage" gorm:"type:TEXT;comment:错误信息，执行失败时记录"`
+	IsDelete            int              `json:"isDelete" gorm:"type:INT NOT NULL DEFAULT 0;comment:是否删除 0:正常 1:删除"`
+	CreateTime          time.Time        `json:"createTime" gorm:"type:DATETIME NOT NULL;comment:创建时间"`
+	UpdateTime          time.Time        `json:"updateTime" gorm:"type:DATETIME NOT NULL ON UPDATE CURRENT_TIMESTAMP;comment:更新时间"`
+}
+
+// Location 任务时区；未设置或无法识别时使用服务默认时区（time.Local）
+func (t *Task) Location() *time.Location {
+	return LoadLocation(t.Timezone)
+}
+
+// RetryDelay 第 attempt 次重试（从1开始）前的等待时长，不超过上限
+func (t *Task) RetryDelay(attempt int) time.Duration {
+	base := time.Duration(t.RetryBaseSeconds) * time.Second
+	if base <= 0 {
+		base = DefaultRetryBaseSeconds * time.Second
+	}
+	max := time.Duration(t.RetryMaxSeconds) * time.Second
+	if max <= 0 {
+		max = DefaultRetryMaxSeconds * time.Second
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+	var d time.Duration
+	switch t.RetryPolicy {
+	case RetryPolicyFixed:
+		d = base
+	case RetryPolicyLinear:
+		// 先与上限比较，避免次数过大时相乘溢出
+		if time.Duration(attempt) > max/base {
+			d = max
+		} else {
+			d = base * time.Duration(attempt)
+		}
+	default:
+		// 指数退避：达到上限后不再翻倍，避免溢出
+		d = base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// LoadLocation 按IANA名称加载时区，空值或无法识别时返回 time.Local
+func LoadLocation(name string) *time.Location {
+	if name == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Local
+	}
+	return loc
 }
 
 // JSON 自定义类型用于处理 JSON 字段
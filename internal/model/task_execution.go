@@ -0,0 +1,68 @@
+package model
+
+import "time"
+
+// 执行记录状态
+const (
+	ExecutionStatusRunning = 0 // 执行中
+	ExecutionStatusSuccess = 1 // 全部成功
+	ExecutionStatusFailed  = 2 // 全部失败
+	ExecutionStatusPartial = 3 // 部分失败
+	ExecutionStatusMissed  = 4 // 错过执行（记录错过执行的处理决定，不产生投递）
+)
+
+// 投递明细状态
+const (
+	DeliveryStatusSuccess  = 1 // 发送成功
+	DeliveryStatusFailed   = 2 // 发送失败
+	DeliveryStatusDeferred = 3 // 触发限流或处于静默期，已延后重新投递
+	DeliveryStatusSkipped  = 4 // 处于静默期，按策略跳过
+)
+
+// TaskExecution 任务单次执行记录
+type TaskExecution struct {
+	*MysqlBaseModel `gorm:"-:all"`
+	ID              uint64     `json:"id" gorm:"primaryKey;type:BIGINT UNSIGNED NOT NULL AUTO_INCREMENT;comment:主键ID"`
+	TaskID          uint64     `json:"taskId" gorm:"type:BIGINT UNSIGNED NOT NULL;index:idx_task_id;comment:任务ID"`
+	AdminID         uint       `json:"adminId" gorm:"type:BIGINT NOT NULL;comment:任务创建者ID"`
+	RunID           string     `json:"runId" gorm:"type:VARCHAR(128) NOT NULL;default:'';comment:asynq任务ID"`
+	Source          string     `json:"source" gorm:"type:VARCHAR(32) NOT NULL;default:'';comment:触发来源（payload type；misfire-错过执行处理，rate_limited-限流补发，send_window-静默期结束后补发）"`
+	Attempt         int        `json:"attempt" gorm:"type:INT NOT NULL;default:0;comment:asynq重试次数"`
+	Status          int        `json:"status" gorm:"type:INT NOT NULL;default:0;comment:状态：0-执行中，1-成功，2-失败，3-部分失败，4-错过执行"`
+	TotalCount      int        `json:"totalCount" gorm:"type:INT NOT NULL;default:0;comment:投递总数"`
+	SuccessCount    int        `json:"successCount" gorm:"type:INT NOT NULL;default:0;comment:成功数"`
+	FailedCount     int        `json:"failedCount" gorm:"type:INT NOT NULL;default:0;comment:失败数"`
+	DeferredCount   int        `json:"deferredCount" gorm:"type:INT NOT NULL;default:0;comment:因限流或静默期延后投递数"`
+	SkippedCount    int        `json:"skippedCount" gorm:"type:INT NOT NULL;default:0;comment:因静默期跳过投递数"`
+	ErrorMessage    string     `json:"errorMessage" gorm:"type:TEXT;comment:错误信息"`
+	StartedAt       time.Time  `json:"startedAt" gorm:"type:DATETIME NOT NULL;comment:开始时间"`
+	FinishedAt      *time.Time `json:"finishedAt" gorm:"type:DATETIME;comment:结束时间"`
+	DurationMs      int64      `json:"durationMs" gorm:"type:BIGINT NOT NULL;default:0;comment:耗时（毫秒）"`
+	CreateTime      time.Time  `json:"createTime" gorm:"type:DATETIME NOT NULL;comment:创建时间"`
+}
+
+// TableName 指定表名
+func (TaskExecution) TableName() string {
+	return "task_execution"
+}
+
+// TaskDelivery 单次执行中每个群组/消息的投递明细
+type TaskDelivery struct {
+	*MysqlBaseModel `gorm:"-:all"`
+	ID              uint64    `json:"id" gorm:"primaryKey;type:BIGINT UNSIGNED NOT NULL AUTO_INCREMENT;comment:主键ID"`
+	ExecutionID     uint64    `json:"executionId" gorm:"type:BIGINT UNSIGNED NOT NULL;index:idx_execution_id;comment:执行记录ID"`
+	TaskID          uint64    `json:"taskId" gorm:"type:BIGINT UNSIGNED NOT NULL;comment:任务ID"`
+	GroupID         int64     `json:"groupId" gorm:"type:BIGINT NOT NULL;comment:群组ID"`
+	MessageID       uint64    `json:"messageId" gorm:"type:BIGINT UNSIGNED NOT NULL;comment:消息ID"`
+	Status          int       `json:"status" gorm:"type:INT NOT NULL;comment:状态：1-成功，2-失败，3-延后，4-静默期跳过"`
+	TgMessageID     int64     `json:"tgMessageId" gorm:"type:BIGINT NOT NULL;default:0;comment:Telegram首条消息ID"`
+	TgMessageIDs    JSON      `json:"tgMessageIds" gorm:"type:JSON;comment:Telegram消息ID列表（媒体组会产生多条）"`
+	LatencyMs       int64     `json:"latencyMs" gorm:"type:BIGINT NOT NULL;default:0;comment:发送耗时（毫秒）"`
+	ErrorMessage    string    `json:"errorMessage" gorm:"type:TEXT;comment:错误信息"`
+	CreateTime      time.Time `json:"createTime" gorm:"type:DATETIME NOT NULL;comment:创建时间"`
+}
+
+// TableName 指定表名
+func (TaskDelivery) TableName() string {
+	return "task_delivery"
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// TaskTemplate 任务模板：保存一份创建任务的配置快照，可按模板创建新的待提交任务
+type TaskTemplate struct {
+	ID           uint64    `json:"id" gorm:"primaryKey;type:BIGINT UNSIGNED NOT NULL AUTO_INCREMENT;comment:主键ID"`
+	AdminID      uint      `json:"adminId" gorm:"type:BIGINT NOT NULL;index;comment:创建者ID"`
+	Name         string    `json:"name" gorm:"type:VARCHAR(64) NOT NULL;comment:模板名称，同一管理员下唯一"`
+	Description  string    `json:"description" gorm:"type:TEXT;comment:模板描述"`
+	Config       JSON      `json:"config" gorm:"type:JSON NOT NULL;comment:任务配置快照（与创建任务请求结构一致），JSON格式存储"`
+	SourceTaskID uint64    `json:"sourceTaskId" gorm:"type:BIGINT UNSIGNED NOT NULL;default:0;comment:由任务保存时的来源任务ID，0-手动创建"`
+	IsDelete     int       `json:"isDelete" gorm:"type:INT NOT NULL DEFAULT 0;comment:是否删除 0:正常 1:删除"`
+	CreateTime   time.Time `json:"createTime" gorm:"type:DATETIME NOT NULL;comment:创建时间"`
+	UpdateTime   time.Time `json:"updateTime" gorm:"type:DATETIME NOT NULL;comment:更新时间"`
+}
+
+func (TaskTemplate) TableName() string {
+	return "task_template"
+}
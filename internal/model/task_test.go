@@ -0,0 +1,43 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTaskRetryDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		task    Task
+		attempt int
+		want    time.Duration
+	}{
+		{"固定间隔第1次", Task{RetryPolicy: RetryPolicyFixed, RetryBaseSeconds: 10, RetryMaxSeconds: 60}, 1, 10 * time.Second},
+		{"固定间隔第5次", Task{RetryPolicy: RetryPolicyFixed, RetryBaseSeconds: 10, RetryMaxSeconds: 60}, 5, 10 * time.Second},
+		{"线性第1次", Task{RetryPolicy: RetryPolicyLinear, RetryBaseSeconds: 10, RetryMaxSeconds: 60}, 1, 10 * time.Second},
+		{"线性第3次", Task{RetryPolicy: RetryPolicyLinear, RetryBaseSeconds: 10, RetryMaxSeconds: 60}, 3, 30 * time.Second},
+		{"线性恰好达到上限", Task{RetryPolicy: RetryPolicyLinear, RetryBaseSeconds: 10, RetryMaxSeconds: 60}, 6, 60 * time.Second},
+		{"线性超过上限", Task{RetryPolicy: RetryPolicyLinear, RetryBaseSeconds: 10, RetryMaxSeconds: 60}, 7, 60 * time.Second},
+		{"线性次数极大不溢出", Task{RetryPolicy: RetryPolicyLinear, RetryBaseSeconds: 10, RetryMaxSeconds: 60}, math.MaxInt, 60 * time.Second},
+		{"指数第1次", Task{RetryPolicy: RetryPolicyExponential, RetryBaseSeconds: 10, RetryMaxSeconds: 300}, 1, 10 * time.Second},
+		{"指数第2次", Task{RetryPolicy: RetryPolicyExponential, RetryBaseSeconds: 10, RetryMaxSeconds: 300}, 2, 20 * time.Second},
+		{"指数第4次", Task{RetryPolicy: RetryPolicyExponential, RetryBaseSeconds: 10, RetryMaxSeconds: 300}, 4, 80 * time.Second},
+		{"指数超过上限", Task{RetryPolicy: RetryPolicyExponential, RetryBaseSeconds: 10, RetryMaxSeconds: 300}, 6, 300 * time.Second},
+		{"指数次数极大不溢出", Task{RetryPolicy: RetryPolicyExponential, RetryBaseSeconds: 10, RetryMaxSeconds: 300}, math.MaxInt, 300 * time.Second},
+		{"未设置策略按指数", Task{RetryBaseSeconds: 10, RetryMaxSeconds: 300}, 3, 40 * time.Second},
+		{"次数为0按第1次", Task{RetryPolicy: RetryPolicyLinear, RetryBaseSeconds: 10, RetryMaxSeconds: 60}, 0, 10 * time.Second},
+		{"次数为负按第1次", Task{RetryPolicy: RetryPolicyExponential, RetryBaseSeconds: 10, RetryMaxSeconds: 300}, -3, 10 * time.Second},
+		{"基数大于上限", Task{RetryPolicy: RetryPolicyFixed, RetryBaseSeconds: 120, RetryMaxSeconds: 60}, 1, 60 * time.Second},
+		{"默认基数", Task{RetryPolicy: RetryPolicyFixed}, 1, DefaultRetryBaseSeconds * time.Second},
+		{"默认上限", Task{RetryPolicy: RetryPolicyExponential}, 100, DefaultRetryMaxSeconds * time.Second},
+		{"负数配置使用默认值", Task{RetryPolicy: RetryPolicyLinear, RetryBaseSeconds: -1, RetryMaxSeconds: -1}, 2, 2 * DefaultRetryBaseSeconds * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.task.RetryDelay(tt.attempt); got != tt.want {
+				t.Errorf("RetryDelay(%d) = %s, want %s", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
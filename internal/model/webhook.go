@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// 任务生命周期事件
+const (
+	WebhookEventTaskSubmitted = "task.submitted" // 任务提交（待提交 -> 待执行）
+	WebhookEventTaskStarted   = "task.started"   // 任务开始执行
+	WebhookEventTaskSucceeded = "task.succeeded" // 任务执行成功
+	WebhookEventTaskFailed    = "task.failed"    // 任务执行失败（每次失败，含仍会重试的情况）
+	WebhookEventTaskExpired   = "task.expired"   // 任务到期
+)
+
+// WebhookEvents 全部可订阅事件
+var WebhookEvents = []string{
+	WebhookEventTaskSubmitted,
+	WebhookEventTaskStarted,
+	WebhookEventTaskSucceeded,
+	WebhookEventTaskFailed,
+	WebhookEventTaskExpired,
+}
+
+// 投递状态
+const (
+	WebhookDeliveryPending = 0 // 待投递/重试中
+	WebhookDeliverySuccess = 1 // 投递成功（2xx）
+	WebhookDeliveryFailed  = 2 // 投递失败（重试耗尽或不可重试）
+)
+
+// Webhook 管理员的 webhook 订阅
+type Webhook struct {
+	ID         uint64         `json:"id" gorm:"primaryKey;type:BIGINT UNSIGNED NOT NULL AUTO_INCREMENT;comment:主键ID"`
+	AdminID    uint           `json:"adminId" gorm:"type:BIGINT NOT NULL;index;comment:管理员ID"`
+	Name       string         `json:"name" gorm:"type:VARCHAR(64) NOT NULL;comment:名称"`
+	URL        string         `json:"url" gorm:"type:VARCHAR(512) NOT NULL;comment:回调地址"`
+	Secret     string         `json:"-" gorm:"type:VARCHAR(128) NOT NULL;comment:HMAC-SHA256签名密钥"`
+	Events     datatypes.JSON `json:"events" gorm:"type:JSON;comment:订阅的事件列表，JSON格式存储，为空表示全部事件"`
+	Enabled    bool           `json:"enabled" gorm:"type:TINYINT(1) NOT NULL;default:1;comment:是否启用"`
+	IsDelete   int            `json:"isDelete" gorm:"type:INT NOT NULL DEFAULT 0;comment:是否删除 0:正常 1:删除"`
+	CreateTime time.Time      `json:"createTime" gorm:"type:DATETIME NOT NULL;comment:创建时间"`
+	UpdateTime time.Time      `json:"updateTime" gorm:"type:DATETIME NOT NULL;comment:更新时间"`
+}
+
+func (Webhook) TableName() string {
+	return "webhook"
+}
+
+// WebhookDelivery webhook 投递记录；重放会创建新的投递记录并通过 ReplayOf 关联原记录
+type WebhookDelivery struct {
+	ID           uint64     `json:"id" gorm:"primaryKey;type:BIGINT UNSIGNED NOT NULL AUTO_INCREMENT;comment:主键ID"`
+	WebhookID    uint64     `json:"webhookId" gorm:"type:BIGINT UNSIGNED NOT NULL;index;comment:webhook ID"`
+	AdminID      uint       `json:"adminId" gorm:"type:BIGINT NOT NULL;comment:管理员ID"`
+	TaskID       uint64     `json:"taskId" gorm:"type:BIGINT UNSIGNED NOT NULL;default:0;comment:关联任务ID"`
+	Event        string     `json:"event" gorm:"type:VARCHAR(32) NOT NULL;comment:事件类型"`
+	EventID      string     `json:"eventId" gorm:"type:VARCHAR(64) NOT NULL;comment:事件ID，重放时保持不变，供接收方去重"`
+	Payload      string     `json:"payload" gorm:"type:MEDIUMTEXT;comment:请求体"`
+	Status       int        `json:"status" gorm:"type:INT NOT NULL;default:0;comment:状态：0-待投递，1-成功，2-失败"`
+	Attempts     int        `json:"attempts" gorm:"type:INT NOT NULL;default:0;comment:已尝试次数"`
+	ResponseCode int        `json:"responseCode" gorm:"type:INT NOT NULL;default:0;comment:最近一次响应状态码"`
+	ResponseBody string     `json:"responseBody" gorm:"type:TEXT;comment:最近一次响应内容（截断）"`
+	ErrorMessage string     `json:"errorMessage" gorm:"type:TEXT;comment:最近一次错误信息"`
+	DurationMs   int64      `json:"durationMs" gorm:"type:BIGINT NOT NULL;default:0;comment:最近一次耗时（毫秒）"`
+	ReplayOf     uint64     `json:"replayOf" gorm:"type:BIGINT UNSIGNED NOT NULL;default:0;comment:重放的原投递记录ID，0-非重放"`
+	DeliveredAt  *time.Time `json:"deliveredAt" gorm:"type:DATETIME;comment:投递成功时间"`
+	CreateTime   time.Time  `json:"createTime" gorm:"type:DATETIME NOT NULL;comment:创建时间"`
+	UpdateTime   time.Time  `json:"updateTime" gorm:"type:DATETIME NOT NULL;comment:更新时间"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_delivery"
+}
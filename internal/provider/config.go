@@ -5,6 +5,7 @@ import (
 	"app/tools/logger"
 	"context"
 	"path/filepath"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"go.uber.org/fx"
@@ -48,6 +49,42 @@ func NewRedisConfig(conf *config.Config) *config.RedisConf {
 	}
 }
 
+// NewJobConfig 创建异步任务Worker配置，未配置的项使用默认值
+func NewJobConfig(conf *config.Config) *config.JobConf {
+	jobConf := &config.JobConf{
+		Concurrency:     config.Get[int](conf, "job", "concurrency"),
+		Queues:          config.ParseQueueWeights(config.Get[string](conf, "job", "queues")),
+		StrictPriority:  config.Get[bool](conf, "job", "strict_priority"),
+		ShutdownTimeout: time.Duration(config.Get[int](conf, "job", "shutdown_timeout")) * time.Second,
+		PriorityQueues: map[string]string{
+			config.PriorityHigh:   config.Get[string](conf, "job", "queue_high"),
+			config.PriorityNormal: config.Get[string](conf, "job", "queue_normal"),
+			config.PriorityLow:    config.Get[string](conf, "job", "queue_low"),
+		},
+		OperatorAdmins: config.ParseAdminIDs(config.Get[string](conf, "job", "operator_admins")),
+	}
+	if jobConf.Concurrency <= 0 {
+		jobConf.Concurrency = 10
+	}
+	if len(jobConf.Queues) == 0 {
+		jobConf.Queues = map[string]int{"critical": 6, config.DefaultQueue: 3, "low": 1}
+	}
+	// 默认队列始终参与消费（系统任务与未指定优先级的任务）
+	if _, ok := jobConf.Queues[config.DefaultQueue]; !ok {
+		jobConf.Queues[config.DefaultQueue] = 1
+	}
+	if jobConf.ShutdownTimeout <= 0 {
+		jobConf.ShutdownTimeout = 8 * time.Second
+	}
+	defaults := map[string]string{config.PriorityHigh: "critical", config.PriorityNormal: config.DefaultQueue, config.PriorityLow: "low"}
+	for p, q := range jobConf.PriorityQueues {
+		if q == "" {
+			jobConf.PriorityQueues[p] = defaults[p]
+		}
+	}
+	return jobConf
+}
+
 // ConfigWatcher 配置文件监听器
 type ConfigWatcher struct {
 	config  *config.Config
@@ -17,7 +17,8 @@ import (
 
 // NewDatabase 创建数据库连接
 func NewDatabase(lc fx.Lifecycle, dbConf *config.DbConf) (*gorm.DB, error) {
-	dns := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+	// 时间统一以UTC存储：驱动按UTC读写，会话时区设为+00:00（CURRENT_TIMESTAMP 同样为UTC）
+	dns := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=UTC&time_zone=%%27%%2B00%%3A00%%27",
 		dbConf.UserName,
 		dbConf.Password,
 		dbConf.Ip,
@@ -2,13 +2,16 @@ package provider
 
 import (
 	"app/internal/controller/admin"
+	"app/internal/controller/alert"
 	"app/internal/controller/bot"
 	"app/internal/controller/evaluate"
 	"app/internal/controller/file"
 	"app/internal/controller/group"
+	jobController "app/internal/controller/job"
 	"app/internal/controller/message"
 	"app/internal/controller/task"
 	"app/internal/controller/user"
+	"app/internal/controller/webhook"
 	"app/internal/job"
 
 	"go.uber.org/fx"
@@ -20,6 +23,7 @@ var InfrastructureModule = fx.Options(
         NewConfig,
         NewDatabaseConfig,
         NewRedisConfig,
+		NewJobConfig,
         NewConfigWatcher,
         NewDatabase,
         NewRedis,
@@ -33,11 +37,18 @@ var InfrastructureModule = fx.Options(
 		NewGroupService,
 		NewMessageService,
 		NewFileService,
+		NewBotFileReader,
 		NewTaskService,
+		NewJobAdminService,
+		NewAlertService,
+		NewWebhookService,
     ),
     fx.Invoke(
-        job.NewBotMsgHandler, // 注册Bot消息处理器
-        job.NewTaskRestorer,  // 启动时恢复任务
+		job.NewBotMsgHandler,    // 注册Bot消息处理器
+		job.NewTaskRestorer,     // 当选调度器leader时恢复任务
+		job.NewReconcileHandler, // 定期对账DB与asynq调度状态
+		job.NewAlertHandler,     // 管理员告警发送与每日汇总
+		job.NewWebhookHandler,   // 任务生命周期事件webhook投递
     ),
 )
 
@@ -53,6 +64,9 @@ var ControllerModule = fx.Options(
 		message.NewMessageController,
 		file.NewFileController,
 		task.NewTaskController,
+		jobController.NewJobController,
+		alert.NewAlertController,
+		webhook.NewWebhookController,
 	),
 )
 
@@ -68,6 +82,9 @@ var RouterModule = fx.Options(
 		NewMessageRoute,
 		NewFileRoute,
 		NewTaskRoute,
+		NewJobRoute,
+		NewAlertRoute,
+		NewWebhookRoute,
 		NewRouter,
 	),
 )
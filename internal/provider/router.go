@@ -3,13 +3,16 @@ package provider
 import (
 	"app/internal/config"
 	admin_controller "app/internal/controller/admin"
+	alert_controller "app/internal/controller/alert"
 	group_controller "app/internal/controller/group"
 	controller "app/internal/controller/bot"
 	evaluate_controller "app/internal/controller/evaluate"
 	file_controller "app/internal/controller/file"
 	message_controller "app/internal/controller/message"
+	job_controller "app/internal/controller/job"
 	task_controller "app/internal/controller/task"
 	user_controller "app/internal/controller/user"
+	webhook_controller "app/internal/controller/webhook"
 	"app/internal/router"
 	"app/internal/service"
 )
@@ -61,6 +64,21 @@ func NewTaskRoute(taskController *task_controller.TaskController) *router.TaskRo
 	return router.NewTaskRoute(taskController)
 }
 
+// NewJobRoute 创建异步任务运维路由Provider
+func NewJobRoute(jobController *job_controller.JobController, jobConf *config.JobConf) *router.JobRoute {
+	return router.NewJobRoute(jobController, jobConf)
+}
+
+// NewAlertRoute 创建管理员告警路由Provider
+func NewAlertRoute(alertController *alert_controller.AlertController) *router.AlertRoute {
+	return router.NewAlertRoute(alertController)
+}
+
+// NewWebhookRoute 创建webhook路由Provider
+func NewWebhookRoute(webhookController *webhook_controller.WebhookController) *router.WebhookRoute {
+	return router.NewWebhookRoute(webhookController)
+}
+
 // NewRouter 创建主路由Provider
 func NewRouter(
 	adminRoute *router.AdminRoute,
@@ -72,9 +90,12 @@ func NewRouter(
 	messageRoute *router.MessageRoute,
 	fileRoute *router.FileRoute,
 	taskRoute *router.TaskRoute,
+	jobRoute *router.JobRoute,
+	alertRoute *router.AlertRoute,
+	webhookRoute *router.WebhookRoute,
 	conf *config.Config,
 	tokenService service.TokenService,
 	adminService service.AdminService,
 ) *router.Router {
-	return router.NewRouter(adminRoute, groupRoute, userRoute, indexRoute, evaluateRoute, botRoute, messageRoute, fileRoute, taskRoute, conf, tokenService, adminService)
+	return router.NewRouter(adminRoute, groupRoute, userRoute, indexRoute, evaluateRoute, botRoute, messageRoute, fileRoute, taskRoute, jobRoute, alertRoute, webhookRoute, conf, tokenService, adminService)
 }
@@ -53,7 +53,27 @@ func NewFileService(config *config.Config) service.FileService {
 	return service.NewFileService(config)
 }
 
+// NewBotFileReader 以文件服务读取Bot消息引用的文件
+func NewBotFileReader(fileService service.FileService) job.FileReader {
+	return fileService
+}
+
 // NewTaskService 创建任务服务Provider
 func NewTaskService(db *gorm.DB, jobService *job.JobService) service.TaskService {
 	return service.NewTaskService(db, jobService)
 }
+
+// NewJobAdminService 创建异步任务运维服务Provider
+func NewJobAdminService(db *gorm.DB, jobService *job.JobService) service.JobAdminService {
+	return service.NewJobAdminService(db, jobService)
+}
+
+// NewAlertService 创建管理员告警服务Provider
+func NewAlertService(db *gorm.DB, jobService *job.JobService) service.AlertService {
+	return service.NewAlertService(db, jobService)
+}
+
+// NewWebhookService 创建webhook订阅服务Provider
+func NewWebhookService(db *gorm.DB, jobService *job.JobService) service.WebhookService {
+	return service.NewWebhookService(db, jobService)
+}
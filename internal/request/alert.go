@@ -0,0 +1,21 @@
+package request
+
+// AlertConfigSaveRequest 保存告警配置请求
+type AlertConfigSaveRequest struct {
+	BotConfigID         uint  `json:"botConfigId" binding:"required"` // 用于发送告警的机器人配置ID
+	ChatID              int64 `json:"chatId" binding:"required"`      // 接收告警的私聊ID（需先与机器人私聊）
+	Enabled             bool  `json:"enabled"`
+	OnFailure           bool  `json:"onFailure"`           // 任务执行失败（重试耗尽）时告警
+	ConsecutiveFailures int   `json:"consecutiveFailures"` // 连续失败N次时告警，0-不启用
+	OnExpire            bool  `json:"onExpire"`            // 周期任务到期时告警
+	DailyDigest         bool  `json:"dailyDigest"`         // 每日汇总
+	DigestHour          int   `json:"digestHour"`          // 每日汇总发送时间（0-23点）
+}
+
+// AlertListRequest 告警记录列表请求
+type AlertListRequest struct {
+	PageRequest
+	TaskID *uint64 `json:"taskId,omitempty"`
+	Kind   string  `json:"kind,omitempty"`
+	Status *int    `json:"status,omitempty"`
+}
@@ -1,5 +1,7 @@
 package request
 
+import "app/internal/model"
+
 type CreateBotConfigRequest struct {
 	Region           string `json:"region" binding:"required" validate:"required"`
 	Type             *int64 `json:"type" binding:"required" validate:"required"`
@@ -20,6 +22,7 @@ type UpdateBotConfigRequest struct {
 	SubscribeChannel string             `json:"subscribeChannelLink" validate:"required"` // 订阅频道链接
 	GroupNamePrefix  string             `json:"groupNamePrefix" validate:"required"`      // 群组名称前缀
 	BotFeature       *BotFeatureRequest `json:"bot_feature,omitempty"`                    // 机器人功能配置
+	SendWindow       *model.SendWindow  `json:"sendWindow,omitempty"`                     // 发送时段与静默期，时段均为空时清除限制
 }
 
 type GetBotConfigRequest struct {
@@ -0,0 +1,8 @@
+package request
+
+// JobTaskListRequest 队列任务列表请求
+type JobTaskListRequest struct {
+	PageRequest
+	Queue string `json:"queue" form:"queue"` // 队列名称，默认 default
+	State string `json:"state" form:"state"` // pending/active/scheduled/retry/archived/completed
+}
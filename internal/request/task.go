@@ -10,6 +10,16 @@ import (
 // FlexibleTime 支持多种时间格式的自定义时间类型
 type FlexibleTime struct {
 	time.Time
+	zoned bool // 输入是否携带时区；不带时区时按任务时区解释
+}
+
+// In 按指定时区解释时间：输入带时区时保持不变，否则以相同的日期时间在 loc 中重新解析
+func (ft *FlexibleTime) In(loc *time.Location) time.Time {
+	if ft.zoned || loc == nil {
+		return ft.Time
+	}
+	t := ft.Time
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
 }
 
 // UnmarshalJSON 自定义JSON反序列化，支持多种时间格式
@@ -25,15 +35,15 @@ func (ft *FlexibleTime) UnmarshalJSON(data []byte) error {
 
     // 优先解析包含时区的格式
     if t, err := time.Parse("2006-01-02T15:04:05Z07:00", timeStr); err == nil {
-        ft.Time = t
+		ft.Time, ft.zoned = t, true
         return nil
     }
     if t, err := time.Parse("2006-01-02T15:04:05Z", timeStr); err == nil {
-        ft.Time = t
+		ft.Time, ft.zoned = t, true
         return nil
     }
 
-    // 其余不含时区的格式，按本地时区解析（main.go 已设置 Asia/Shanghai）
+	// 其余不含时区的格式，先按服务默认时区解析，使用时通过 In 换算为任务时区
     if t, err := time.ParseInLocation("2006-01-02T15:04:05", timeStr, time.Local); err == nil {
         ft.Time = t
         return nil
@@ -52,18 +62,32 @@ func (ft *FlexibleTime) UnmarshalJSON(data []byte) error {
 
 // CreateTaskRequest 创建任务请求
 type CreateTaskRequest struct {
-    TaskName        string                  `json:"taskName" binding:"required" validate:"required"`
-    Description     string                  `json:"description"`
-    GroupIDs        []int64                `json:"groupIds" binding:"required" validate:"required,min=1"`
-    MessageIDs      []uint64               `json:"messageIds" binding:"required" validate:"required,min=1"`
-    TriggerType     model.TriggerType      `json:"triggerType" binding:"required" validate:"required,oneof=schedule cron"`
-    ScheduleTime    *FlexibleTime          `json:"scheduleTime"`
+	TaskName     string            `json:"taskName" binding:"required" validate:"required"`
+	Description  string            `json:"description"`
+	GroupIDs     []int64           `json:"groupIds" binding:"required" validate:"required,min=1"`
+	MessageIDs   []uint64          `json:"messageIds" binding:"required" validate:"required,min=1"`
+	TriggerType  model.TriggerType `json:"triggerType" binding:"required" validate:"required,oneof=schedule cron"`
+	ScheduleTime *FlexibleTime     `json:"scheduleTime"`
     // 定时执行（schedule）不再要求到期时间；仅周期任务（cron）在服务层校验必填
     ExpireTime      *FlexibleTime          `json:"expireTime"`
     CronExpression  string                 `json:"cronExpression"`
     CronPatternType *model.CronPatternType `json:"cronPatternType"`
     CronConfig      map[string]interface{} `json:"cronConfig"`
     MaxRetryCount   int                    `json:"maxRetryCount" validate:"min=0,max=10"`
+	// 错过执行策略：skip / fire_once / catch_up，为空时默认 fire_once
+	MisfirePolicy       string `json:"misfirePolicy"`
+	MisfireCatchUpLimit int    `json:"misfireCatchUpLimit"`
+	// 优先级：high / normal / low，为空时默认 normal；不同优先级进入不同队列
+	Priority string `json:"priority"`
+	// 重试退避：fixed / linear / exponential，为空时默认 exponential；基准与上限（秒）为0时默认 60 / 1920
+	RetryPolicy      string `json:"retryPolicy"`
+	RetryBaseSeconds int    `json:"retryBaseSeconds"`
+	RetryMaxSeconds  int    `json:"retryMaxSeconds"`
+	// 消息轮换：all / round_robin / random / weighted，为空时默认 all；weighted 时按消息ID配置权重（未配置为1）
+	RotationMode    string         `json:"rotationMode"`
+	RotationWeights map[uint64]int `json:"rotationWeights"`
+	// 任务时区（IANA名称，如 Asia/Shanghai、Europe/London），为空时使用服务默认时区
+	Timezone string `json:"timezone"`
 }
 
 // GetScheduleTime 获取 time.Time 类型的调度时间
@@ -71,7 +95,8 @@ func (req *CreateTaskRequest) GetScheduleTime() *time.Time {
     if req.ScheduleTime == nil {
         return nil
     }
-    return &req.ScheduleTime.Time
+	t := req.ScheduleTime.In(model.LoadLocation(req.Timezone))
+	return &t
 }
 
 // GetExpireTime 获取 time.Time 类型的到期时间
@@ -79,7 +104,8 @@ func (req *CreateTaskRequest) GetExpireTime() *time.Time {
     if req.ExpireTime == nil {
         return nil
     }
-    return &req.ExpireTime.Time
+	t := req.ExpireTime.In(model.LoadLocation(req.Timezone))
+	return &t
 }
 
 // UpdateTaskRequest 更新任务请求
@@ -91,10 +117,25 @@ type UpdateTaskRequest struct {
 	MessageIDs      []uint64               `json:"messageIds" binding:"required" validate:"required,min=1"`
 	TriggerType     model.TriggerType      `json:"triggerType" binding:"required" validate:"required,oneof=schedule cron"`
 	ScheduleTime    *FlexibleTime          `json:"scheduleTime"`
+	ExpireTime      *FlexibleTime          `json:"expireTime"`
 	CronExpression  string                 `json:"cronExpression"`
 	CronPatternType *model.CronPatternType `json:"cronPatternType"`
 	CronConfig      map[string]interface{} `json:"cronConfig"`
 	MaxRetryCount   int                    `json:"maxRetryCount" validate:"min=0,max=10"`
+	// 错过执行策略：skip / fire_once / catch_up，为空时保持原策略
+	MisfirePolicy       string `json:"misfirePolicy"`
+	MisfireCatchUpLimit int    `json:"misfireCatchUpLimit"`
+	// 优先级：high / normal / low，为空时保持原优先级
+	Priority string `json:"priority"`
+	// 重试退避：为空/为0时保持原配置
+	RetryPolicy      string `json:"retryPolicy"`
+	RetryBaseSeconds int    `json:"retryBaseSeconds"`
+	RetryMaxSeconds  int    `json:"retryMaxSeconds"`
+	// 消息轮换：为空时保持原模式；rotationWeights 为空时保持原权重
+	RotationMode    string         `json:"rotationMode"`
+	RotationWeights map[uint64]int `json:"rotationWeights"`
+	// 任务时区（IANA名称），为空时保持任务原时区
+	Timezone string `json:"timezone"`
 }
 
 // GetScheduleTime 获取 time.Time 类型的调度时间
@@ -102,7 +143,17 @@ func (req *UpdateTaskRequest) GetScheduleTime() *time.Time {
 	if req.ScheduleTime == nil {
 		return nil
 	}
-	return &req.ScheduleTime.Time
+	t := req.ScheduleTime.In(model.LoadLocation(req.Timezone))
+	return &t
+}
+
+// GetExpireTime 获取 time.Time 类型的到期时间
+func (req *UpdateTaskRequest) GetExpireTime() *time.Time {
+	if req.ExpireTime == nil {
+		return nil
+	}
+	t := req.ExpireTime.In(model.LoadLocation(req.Timezone))
+	return &t
 }
 
 // TaskListRequest 任务列表请求
@@ -129,3 +180,134 @@ type GetTaskDetailRequest struct {
 type SubmitTaskRequest struct {
     ID uint64 `json:"id" binding:"required" validate:"required"`
 }
+
+// PauseTaskRequest 暂停任务请求
+type PauseTaskRequest struct {
+	ID uint64 `json:"id" binding:"required" validate:"required"`
+}
+
+// ResumeTaskRequest 恢复任务请求
+type ResumeTaskRequest struct {
+	ID uint64 `json:"id" binding:"required" validate:"required"`
+}
+
+// TriggerTaskRequest 手动触发任务请求
+type TriggerTaskRequest struct {
+	ID uint64 `json:"id" binding:"required" validate:"required"`
+}
+
+// TaskExecutionListRequest 任务执行记录列表请求
+type TaskExecutionListRequest struct {
+	PageRequest
+	TaskID uint64 `json:"taskId" binding:"required" validate:"required"`
+	Status *int   `json:"status"`
+}
+
+// TaskExecutionDetailRequest 任务执行记录详情请求
+type TaskExecutionDetailRequest struct {
+	ID uint64 `json:"id" binding:"required" validate:"required"`
+}
+
+// CronPreviewRequest Cron预览请求
+type CronPreviewRequest struct {
+	CronExpression string        `json:"cronExpression" binding:"required" validate:"required"`
+	Count          int           `json:"count" validate:"min=0,max=10"`
+	ExpireTime     *FlexibleTime `json:"expireTime"`
+	Timezone       string        `json:"timezone"` // 预览时区，为空时使用服务默认时区
+}
+
+// GetExpireTime 获取 time.Time 类型的到期时间
+func (req *CronPreviewRequest) GetExpireTime() *time.Time {
+	if req.ExpireTime == nil {
+		return nil
+	}
+	t := req.ExpireTime.In(model.LoadLocation(req.Timezone))
+	return &t
+}
+
+// TaskDeadLetterListRequest 任务归档（重试耗尽）队列任务列表请求
+type TaskDeadLetterListRequest struct {
+	PageRequest
+	TaskID uint64 `json:"taskId" binding:"required" validate:"required"`
+}
+
+// TaskDeadLetterRequeueRequest 重新执行归档任务请求
+type TaskDeadLetterRequeueRequest struct {
+	TaskID  uint64          `json:"taskId" binding:"required" validate:"required"`
+	JobIDs  []string        `json:"jobIds"`  // 为空时处理该任务全部归档任务
+	Payload json.RawMessage `json:"payload"` // 可选：新的 payload（当前版本结构），为空时沿用原 payload
+}
+
+// TaskDeadLetterDeleteRequest 删除归档任务请求
+type TaskDeadLetterDeleteRequest struct {
+	TaskID uint64   `json:"taskId" binding:"required" validate:"required"`
+	JobIDs []string `json:"jobIds"` // 为空时删除该任务全部归档任务
+}
+
+// CloneTaskRequest 复制任务请求：复制为新的待提交任务
+type CloneTaskRequest struct {
+	ID       uint64 `json:"id" binding:"required" validate:"required"`
+	TaskName string `json:"taskName"` // 为空时使用“原名称-副本”
+}
+
+// CreateTaskTemplateRequest 创建任务模板请求：taskId 与 task 二选一，taskId 表示以已有任务的配置保存为模板
+type CreateTaskTemplateRequest struct {
+	Name        string             `json:"name" binding:"required,max=64" validate:"required"`
+	Description string             `json:"description"`
+	TaskID      uint64             `json:"taskId"`
+	Task        *CreateTaskRequest `json:"task"`
+}
+
+// UpdateTaskTemplateRequest 更新任务模板请求，未传的字段保持不变
+type UpdateTaskTemplateRequest struct {
+	ID          uint64             `json:"id" binding:"required" validate:"required"`
+	Name        string             `json:"name" binding:"max=64"`
+	Description *string            `json:"description"`
+	Task        *CreateTaskRequest `json:"task"`
+}
+
+// DeleteTaskTemplateRequest 删除任务模板请求
+type DeleteTaskTemplateRequest struct {
+	ID uint64 `json:"id" binding:"required" validate:"required"`
+}
+
+// GetTaskTemplateDetailRequest 获取任务模板详情请求
+type GetTaskTemplateDetailRequest struct {
+	ID uint64 `json:"id" binding:"required" validate:"required"`
+}
+
+// TaskTemplateListRequest 任务模板列表请求
+type TaskTemplateListRequest struct {
+	PageRequest
+	Name string `json:"name" form:"name"`
+}
+
+// InstantiateTaskTemplateRequest 按模板创建任务请求，传入的字段覆盖模板配置
+type InstantiateTaskTemplateRequest struct {
+	ID         uint64   `json:"id" binding:"required" validate:"required"`
+	TaskName   string   `json:"taskName"`
+	GroupIDs   []int64  `json:"groupIds"`
+	MessageIDs []uint64 `json:"messageIds"`
+	// 调度覆盖：triggerType 变更时按新类型使用下面的执行时间或Cron配置
+	TriggerType  *model.TriggerType `json:"triggerType"`
+	ScheduleTime *FlexibleTime      `json:"scheduleTime"`
+	ExpireTime   *FlexibleTime      `json:"expireTime"`
+	// 传入 cronExpression 或 cronPatternType 时整体替换模板的Cron配置
+	CronExpression  string                 `json:"cronExpression"`
+	CronPatternType *model.CronPatternType `json:"cronPatternType"`
+	CronConfig      map[string]interface{} `json:"cronConfig"`
+	Timezone        string                 `json:"timezone"`
+}
+
+// TaskBatchRequest 批量操作任务请求：ids 与 filter 二选一，优先使用 ids；filter 忽略分页，匹配全部任务
+type TaskBatchRequest struct {
+	IDs    []uint64         `json:"ids"`
+	Filter *TaskListRequest `json:"filter"`
+}
+
+// TaskBatchExtendExpireRequest 批量延长到期时间请求：expireTime 与 extendHours 二选一
+type TaskBatchExtendExpireRequest struct {
+	TaskBatchRequest
+	ExpireTime  *FlexibleTime `json:"expireTime"`  // 新的到期时间，不带时区时按各任务时区解释
+	ExtendHours int           `json:"extendHours"` // 在原到期时间基础上延长的小时数
+}
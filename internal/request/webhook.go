@@ -0,0 +1,44 @@
+package request
+
+// CreateWebhookRequest 创建webhook请求
+type CreateWebhookRequest struct {
+	Name    string   `json:"name" binding:"required,max=64"`
+	URL     string   `json:"url" binding:"required,max=512"`
+	Events  []string `json:"events"`  // 订阅的事件，为空表示全部事件
+	Secret  string   `json:"secret"`  // 签名密钥，为空时自动生成
+	Enabled *bool    `json:"enabled"` // 默认启用
+}
+
+// UpdateWebhookRequest 更新webhook请求，未传的字段保持不变
+type UpdateWebhookRequest struct {
+	ID           uint64    `json:"id" binding:"required"`
+	Name         *string   `json:"name,omitempty"`
+	URL          *string   `json:"url,omitempty"`
+	Events       *[]string `json:"events,omitempty"`
+	Enabled      *bool     `json:"enabled,omitempty"`
+	RotateSecret bool      `json:"rotateSecret"` // 重新生成签名密钥
+}
+
+// DeleteWebhookRequest 删除webhook请求
+type DeleteWebhookRequest struct {
+	ID uint64 `json:"id" binding:"required"`
+}
+
+// WebhookListRequest webhook列表请求
+type WebhookListRequest struct {
+	PageRequest
+}
+
+// WebhookDeliveryListRequest webhook投递记录列表请求
+type WebhookDeliveryListRequest struct {
+	PageRequest
+	WebhookID *uint64 `json:"webhookId,omitempty"`
+	TaskID    *uint64 `json:"taskId,omitempty"`
+	Event     string  `json:"event,omitempty"`
+	Status    *int    `json:"status,omitempty"`
+}
+
+// ReplayWebhookDeliveryRequest 重放webhook投递请求
+type ReplayWebhookDeliveryRequest struct {
+	ID uint64 `json:"id" binding:"required"`
+}
@@ -0,0 +1,37 @@
+package router
+
+import (
+	"app/internal/controller/alert"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AlertRoute 管理员告警路由结构
+type AlertRoute struct {
+	AlertController *alert.AlertController
+}
+
+// NewAlertRoute 创建告警路由实例
+func NewAlertRoute(alertController *alert.AlertController) *AlertRoute {
+	return &AlertRoute{
+		AlertController: alertController,
+	}
+}
+
+// InitRoute 初始化告警路由
+func (ar *AlertRoute) InitRoute(r *gin.Engine) {
+	alertGroup := r.Group("/api/alert")
+	{
+		// 获取告警配置
+		alertGroup.POST("/config", ar.AlertController.GetConfig)
+
+		// 保存告警配置
+		alertGroup.POST("/config/save", ar.AlertController.SaveConfig)
+
+		// 告警记录
+		alertGroup.POST("/list", ar.AlertController.List)
+
+		// 发送测试告警
+		alertGroup.POST("/test", ar.AlertController.SendTest)
+	}
+}
@@ -0,0 +1,47 @@
+package router
+
+import (
+	"app/internal/config"
+	"app/internal/controller/job"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobRoute 异步任务运维路由结构
+type JobRoute struct {
+	JobController *job.JobController
+	jobConf       *config.JobConf
+}
+
+// NewJobRoute 创建异步任务运维路由实例
+func NewJobRoute(jobController *job.JobController, jobConf *config.JobConf) *JobRoute {
+	return &JobRoute{
+		JobController: jobController,
+		jobConf:       jobConf,
+	}
+}
+
+// InitRoute 初始化异步任务运维路由；队列、调度条目与对账涉及所有管理员的任务，仅运维管理员可访问
+func (jr *JobRoute) InitRoute(r *gin.Engine) {
+	jobGroup := r.Group("/api/job", middleware.OperatorOnly(jr.jobConf.OperatorAdmins))
+	{
+		// 队列概况
+		jobGroup.POST("/queues", jr.JobController.Queues)
+
+		// 调度条目
+		jobGroup.POST("/scheduler-entries", jr.JobController.SchedulerEntries)
+
+		// 按状态分页查询队列任务（state=pending|active|scheduled|retry|archived|completed）
+		jobGroup.POST("/tasks", jr.JobController.Tasks)
+
+		// Worker 服务实例
+		jobGroup.POST("/servers", jr.JobController.Servers)
+
+		// 最近一次对账报告
+		jobGroup.POST("/reconcile/report", jr.JobController.ReconcileReport)
+
+		// 立即执行一次对账
+		jobGroup.POST("/reconcile/run", jr.JobController.RunReconcile)
+	}
+}
@@ -25,6 +25,9 @@ type Router struct {
 	MessageRoute  *MessageRoute
 	FileRoute     *FileRoute
 	TaskRoute     *TaskRoute
+	JobRoute      *JobRoute
+	AlertRoute    *AlertRoute
+	WebhookRoute  *WebhookRoute
 	Config        *config.Config
 	TokenService  service.TokenService
 	adminService  service.AdminService
@@ -41,6 +44,9 @@ func NewRouter(
 	messageRoute *MessageRoute,
 	fileRoute *FileRoute,
 	taskRoute *TaskRoute,
+	jobRoute *JobRoute,
+	alertRoute *AlertRoute,
+	webhookRoute *WebhookRoute,
 	conf *config.Config,
 	tokenService service.TokenService,
 	adminService service.AdminService,
@@ -55,6 +61,9 @@ func NewRouter(
 		MessageRoute:  messageRoute,
 		FileRoute:     fileRoute,
 		TaskRoute:     taskRoute,
+		JobRoute:      jobRoute,
+		AlertRoute:    alertRoute,
+		WebhookRoute:  webhookRoute,
 		Config:        conf,
 		TokenService:  tokenService,
 		adminService:  adminService,
@@ -134,6 +143,9 @@ func (router *Router) InitRoutes() {
 	router.MessageRoute.InitRoute(router.Engine)
 	router.FileRoute.InitRoute(router.Engine)
 	router.TaskRoute.InitRoute(router.Engine)
+	router.JobRoute.InitRoute(router.Engine)
+	router.AlertRoute.InitRoute(router.Engine)
+	router.WebhookRoute.InitRoute(router.Engine)
 }
 
 // Run 启动服务器
@@ -29,6 +29,15 @@ func (tr *TaskRoute) InitRoute(r *gin.Engine) {
         // 提交任务
         taskGroup.POST("/submit", tr.TaskController.SubmitTask)
 
+		// 暂停任务
+		taskGroup.POST("/pause", tr.TaskController.PauseTask)
+
+		// 恢复任务
+		taskGroup.POST("/resume", tr.TaskController.ResumeTask)
+
+		// 手动触发（立即执行一次）
+		taskGroup.POST("/trigger", tr.TaskController.TriggerTask)
+
 		// 更新任务
 		taskGroup.POST("/update", tr.TaskController.UpdateTask)
 
@@ -40,5 +49,46 @@ func (tr *TaskRoute) InitRoute(r *gin.Engine) {
 
 		// 任务列表
 		taskGroup.POST("/list", tr.TaskController.TaskList)
+
+		// 执行记录列表
+		taskGroup.POST("/executions", tr.TaskController.ExecutionList)
+
+		// 执行记录详情（含各群组投递明细）
+		taskGroup.POST("/execution/detail", tr.TaskController.ExecutionDetail)
+
+		// Cron表达式校验与执行时间预览
+		taskGroup.POST("/cron/preview", tr.TaskController.CronPreview)
+
+		// 预设的常用Cron表达式
+		taskGroup.POST("/cron/presets", tr.TaskController.CronPresets)
+
+		// 重试耗尽后归档的队列任务
+		taskGroup.POST("/dead-letters", tr.TaskController.DeadLetterList)
+
+		// 重新执行归档任务（可指定新的payload）
+		taskGroup.POST("/dead-letters/requeue", tr.TaskController.RequeueDeadLetters)
+
+		// 删除归档任务
+		taskGroup.POST("/dead-letters/delete", tr.TaskController.DeleteDeadLetters)
+
+		// 复制任务为新的待提交任务
+		taskGroup.POST("/clone", tr.TaskController.CloneTask)
+
+		// 任务模板
+		taskGroup.POST("/template/create", tr.TaskController.CreateTemplate)
+		taskGroup.POST("/template/update", tr.TaskController.UpdateTemplate)
+		taskGroup.POST("/template/delete", tr.TaskController.DeleteTemplate)
+		taskGroup.POST("/template/detail", tr.TaskController.TemplateDetail)
+		taskGroup.POST("/template/list", tr.TaskController.TemplateList)
+
+		// 按模板创建任务（可覆盖群组、调度与到期时间）
+		taskGroup.POST("/template/instantiate", tr.TaskController.InstantiateTemplate)
+
+		// 批量操作（按ID列表或列表筛选条件），返回每个任务的结果
+		taskGroup.POST("/batch/submit", tr.TaskController.BatchSubmit)
+		taskGroup.POST("/batch/pause", tr.TaskController.BatchPause)
+		taskGroup.POST("/batch/resume", tr.TaskController.BatchResume)
+		taskGroup.POST("/batch/delete", tr.TaskController.BatchDelete)
+		taskGroup.POST("/batch/extend-expire", tr.TaskController.BatchExtendExpire)
 	}
 }
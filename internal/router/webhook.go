@@ -0,0 +1,43 @@
+package router
+
+import (
+	"app/internal/controller/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookRoute webhook路由结构
+type WebhookRoute struct {
+	WebhookController *webhook.WebhookController
+}
+
+// NewWebhookRoute 创建webhook路由实例
+func NewWebhookRoute(webhookController *webhook.WebhookController) *WebhookRoute {
+	return &WebhookRoute{
+		WebhookController: webhookController,
+	}
+}
+
+// InitRoute 初始化webhook路由
+func (wr *WebhookRoute) InitRoute(r *gin.Engine) {
+	webhookGroup := r.Group("/api/webhook")
+	{
+		// 创建webhook
+		webhookGroup.POST("/create", wr.WebhookController.Create)
+
+		// 更新webhook
+		webhookGroup.POST("/update", wr.WebhookController.Update)
+
+		// 删除webhook
+		webhookGroup.POST("/delete", wr.WebhookController.Delete)
+
+		// webhook列表
+		webhookGroup.POST("/list", wr.WebhookController.List)
+
+		// 投递记录
+		webhookGroup.POST("/deliveries", wr.WebhookController.Deliveries)
+
+		// 重放投递
+		webhookGroup.POST("/replay", wr.WebhookController.Replay)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"app/internal/model"
 	"app/internal/request"
 	"context"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -63,6 +64,8 @@ func (as *AdminServiceImpl) Login(req request.AdminLoginRequest) (*LoginResult,
 		return nil, err
 	}
 
+	localizeAdmin(admin)
+
 	// 生成JWT
 	token, userJwt := as.tokenLogic.GenerateJwt(admin.Id, 0)
 
@@ -88,6 +91,7 @@ func (as *AdminServiceImpl) GetAdminWithGroups(ctx context.Context, adminId int6
 	if err != nil {
 		return nil, err
 	}
+	localizeAdmin(admin)
 
 	// 获取管理员的群组信息
 	groups, err := as.groupService.GetGroupsByAdminID(ctx, int(adminId))
@@ -123,3 +127,9 @@ func (as *AdminServiceImpl) InitPwd(req request.InitPwdRequest) error {
 
 	return nil
 }
+
+// localizeAdmin 时间以UTC存储，回显时换算为服务默认时区
+func localizeAdmin(admin *model.Admin) {
+	admin.CreatedAt = admin.CreatedAt.In(time.Local)
+	admin.UpdatedAt = admin.UpdatedAt.In(time.Local)
+}
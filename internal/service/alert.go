@@ -0,0 +1,165 @@
+package service
+
+import (
+	"app/internal/job"
+	"app/internal/model"
+	"app/internal/request"
+	"app/internal/vo"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AlertService 管理员告警服务接口
+type AlertService interface {
+	GetConfig(adminID uint) (*vo.AlertConfigVo, error)
+	SaveConfig(req *request.AlertConfigSaveRequest, adminID uint) (*vo.AlertConfigVo, error)
+	ListAlerts(req *request.AlertListRequest, adminID uint) (*vo.PageResultVo[vo.AlertVo], error)
+	SendTest(adminID uint) (*vo.AlertVo, error)
+}
+
+type AlertServiceImpl struct {
+	db         *gorm.DB
+	jobService *job.JobService
+}
+
+// NewAlertService 创建AlertService实例
+func NewAlertService(db *gorm.DB, jobService *job.JobService) AlertService {
+	return &AlertServiceImpl{
+		db:         db,
+		jobService: jobService,
+	}
+}
+
+// GetConfig 获取告警配置；未配置时返回 nil
+func (a *AlertServiceImpl) GetConfig(adminID uint) (*vo.AlertConfigVo, error) {
+	var cfg model.AdminAlertConfig
+	if err := a.db.Where("admin_id = ?", adminID).First(&cfg).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return alertConfigToVO(&cfg), nil
+}
+
+// SaveConfig 保存告警配置（每个管理员一份）
+func (a *AlertServiceImpl) SaveConfig(req *request.AlertConfigSaveRequest, adminID uint) (*vo.AlertConfigVo, error) {
+	if req.ConsecutiveFailures < 0 || req.ConsecutiveFailures > 100 {
+		return nil, errors.New("连续失败次数需在0-100之间")
+	}
+	if req.DigestHour < 0 || req.DigestHour > 23 {
+		return nil, errors.New("每日汇总时间需在0-23点之间")
+	}
+	var count int64
+	if err := a.db.Model(&model.BotConfig{}).Where("id = ? AND admin_id = ?", req.BotConfigID, adminID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("机器人不存在或无权限使用")
+	}
+
+	now := time.Now()
+	var cfg model.AdminAlertConfig
+	err := a.db.Where("admin_id = ?", adminID).First(&cfg).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	cfg.AdminID = adminID
+	cfg.BotConfigID = req.BotConfigID
+	cfg.ChatID = req.ChatID
+	cfg.Enabled = req.Enabled
+	cfg.OnFailure = req.OnFailure
+	cfg.ConsecutiveFailures = req.ConsecutiveFailures
+	cfg.OnExpire = req.OnExpire
+	cfg.DailyDigest = req.DailyDigest
+	cfg.DigestHour = req.DigestHour
+	cfg.UpdateTime = now
+	if cfg.ID == 0 {
+		cfg.CreateTime = now
+	}
+	// Save 会写入零值字段（关闭规则时需要）
+	if err := a.db.Save(&cfg).Error; err != nil {
+		return nil, err
+	}
+	return alertConfigToVO(&cfg), nil
+}
+
+// ListAlerts 分页查询告警记录
+func (a *AlertServiceImpl) ListAlerts(req *request.AlertListRequest, adminID uint) (*vo.PageResultVo[vo.AlertVo], error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 10
+	}
+	query := a.db.Model(&model.AdminAlert{}).Where("admin_id = ?", adminID)
+	if req.TaskID != nil {
+		query = query.Where("task_id = ?", *req.TaskID)
+	}
+	if req.Kind != "" {
+		query = query.Where("kind = ?", req.Kind)
+	}
+	if req.Status != nil {
+		query = query.Where("status = ?", *req.Status)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	var alerts []model.AdminAlert
+	if err := query.Order("id DESC").Offset(req.GetOffset()).Limit(req.Limit).Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	list := make([]vo.AlertVo, len(alerts))
+	for i := range alerts {
+		list[i] = alertToVO(&alerts[i])
+	}
+	return &vo.PageResultVo[vo.AlertVo]{Total: total, List: list}, nil
+}
+
+// SendTest 发送一条测试告警，用于确认机器人与私聊配置正确
+func (a *AlertServiceImpl) SendTest(adminID uint) (*vo.AlertVo, error) {
+	content := "【测试告警】\n告警配置有效，任务失败/到期等通知将发送到此会话。"
+	alert, err := a.jobService.EnqueueAlert(adminID, 0, model.AlertKindTest, content)
+	if err != nil {
+		return nil, err
+	}
+	if alert == nil {
+		return nil, errors.New("未配置告警或告警已停用")
+	}
+	v := alertToVO(alert)
+	return &v, nil
+}
+
+func alertConfigToVO(cfg *model.AdminAlertConfig) *vo.AlertConfigVo {
+	return &vo.AlertConfigVo{
+		BotConfigID:         cfg.BotConfigID,
+		ChatID:              cfg.ChatID,
+		Enabled:             cfg.Enabled,
+		OnFailure:           cfg.OnFailure,
+		ConsecutiveFailures: cfg.ConsecutiveFailures,
+		OnExpire:            cfg.OnExpire,
+		DailyDigest:         cfg.DailyDigest,
+		DigestHour:          cfg.DigestHour,
+		UpdateTime:          &vo.CustomTime{Time: cfg.UpdateTime},
+	}
+}
+
+func alertToVO(a *model.AdminAlert) vo.AlertVo {
+	v := vo.AlertVo{
+		ID:           a.ID,
+		TaskID:       a.TaskID,
+		Kind:         a.Kind,
+		Content:      a.Content,
+		Status:       a.Status,
+		StatusText:   vo.GetAlertStatusText(a.Status),
+		ErrorMessage: a.ErrorMessage,
+		CreateTime:   vo.CustomTime{Time: a.CreateTime},
+	}
+	if a.SentAt != nil {
+		v.SentAt = &vo.CustomTime{Time: *a.SentAt}
+	}
+	return v
+}
@@ -4,6 +4,7 @@ import (
 	bizErrors "app/internal/error"
 	"context"
 	"encoding/json"
+	"time"
 
 	"app/internal/dto"
 	"app/internal/model"
@@ -57,18 +58,37 @@ func (s *BotService) UpdateBotConfig(ctx context.Context, request request.Update
 	// 准备更新的字段
 	updates := map[string]interface{}{}
 
-	// 如果请求中包含 BotFeature，则只更新 Features 字段
+	// 如果请求中包含 BotFeature，则更新 Features 字段
 	if request.BotFeature != nil {
 		featuresJSON, err := json.Marshal(request.BotFeature)
 		if err != nil {
 			return err
 		}
 		updates["features"] = featuresJSON
-		return s.db.WithContext(ctx).Model(&model.BotConfig{}).
-			Where("id = ?", request.Id).
-			Updates(updates).Error
 	}
-	return nil
+
+	// 发送时段与静默期：时段均为空时清除限制
+	if w := request.SendWindow; w != nil {
+		if len(w.Windows) == 0 && len(w.Blackouts) == 0 {
+			updates["send_window"] = nil
+		} else {
+			if err := w.Validate(); err != nil {
+				return err
+			}
+			windowJSON, err := json.Marshal(w)
+			if err != nil {
+				return err
+			}
+			updates["send_window"] = windowJSON
+		}
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Model(&model.BotConfig{}).
+		Where("id = ?", request.Id).
+		Updates(updates).Error
 }
 
 // GetBotConfig retrieves bot configuration by group_id
@@ -123,6 +143,16 @@ func (s *BotService) GetBotConfigData(ctx context.Context, id int64, userId uint
 		}
 	}
 
+	// 解析发送时段
+	if len(botConfig.SendWindow) > 0 && string(botConfig.SendWindow) != "null" {
+		var window model.SendWindow
+		if err := json.Unmarshal(botConfig.SendWindow, &window); err != nil {
+			logger.Error("解析发送时段配置失败", "groupID", botConfig.GroupID, "error", err)
+		} else {
+			configData.SendWindow = &window
+		}
+	}
+
 	return configData, nil
 }
 
@@ -170,7 +200,7 @@ func (s *BotService) SearchBotConfig(ctx context.Context, request request.Search
 		}
 		configData.Name = cfgData.Name
 		configData.GroupNamePrefix = cfgData.GroupNamePrefix
-		configData.CreateTime = botConfig.CreateTime.Format("2006-01-02 15:04:05")
+		configData.CreateTime = botConfig.CreateTime.In(time.Local).Format("2006-01-02 15:04:05")
 
 		// 解析 BotFeature 数据
 		if len(botConfig.Features) > 0 {
@@ -13,6 +13,15 @@ import (
 	"strings"
 )
 
+// ErrFileNotFound 文件不存在（未上传或已删除）；匹配 os.ErrNotExist，无法引用本包的调用方（如异步任务）也可判断
+var ErrFileNotFound error = fileNotFoundError{}
+
+type fileNotFoundError struct{}
+
+func (fileNotFoundError) Error() string { return "文件不存在" }
+
+func (fileNotFoundError) Unwrap() error { return os.ErrNotExist }
+
 // FileService 文件服务接口
 type FileService interface {
 	// UploadFile 上传单个文件，返回文件ID和原始文件名
@@ -160,7 +169,7 @@ func (fs *fileService) GetFileContent(fileID string) ([]byte, string, error) {
 	}
 	
 	if foundFile == "" {
-		return nil, "", errors.New("文件不存在")
+		return nil, "", ErrFileNotFound
 	}
 	
 	// 构建完整文件路径
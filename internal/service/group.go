@@ -6,6 +6,7 @@ import (
 	"app/internal/vo"
 	"context"
 	"strconv"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -146,7 +147,7 @@ func (s *GroupServiceImpl) SearchGroups(ctx context.Context, req request.SearchG
 			GroupID:    group.GroupID,
 			GroupName:  group.GroupName,
 			Status:     group.Status,
-			CreateTime: group.CreateTime.Format("2006-01-02 15:04:05"),
+			CreateTime: group.CreateTime.In(time.Local).Format("2006-01-02 15:04:05"),
 		})
 	}
 	
@@ -194,8 +195,8 @@ func (s *GroupServiceImpl) GetGroupsByGroupID(ctx context.Context, groupID int64
 			GroupID:    group.GroupID,
 			GroupName:  group.GroupName,
 			Status:     group.Status,
-			CreateTime: group.CreateTime,
-			UpdateTime: group.UpdateTime,
+			CreateTime: group.CreateTime.In(time.Local),
+			UpdateTime: group.UpdateTime.In(time.Local),
 		})
 	}
 	
@@ -217,8 +218,8 @@ func (s *GroupServiceImpl) GetGroupByID(ctx context.Context, id int) (*vo.GroupV
 		GroupID:    group.GroupID,
 		GroupName:  group.GroupName,
 		Status:     group.Status,
-		CreateTime: group.CreateTime,
-		UpdateTime: group.UpdateTime,
+		CreateTime: group.CreateTime.In(time.Local),
+		UpdateTime: group.UpdateTime.In(time.Local),
 	}
 	
 	return groupVo, nil
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"app/internal/job"
+	"app/internal/model"
+	"app/internal/request"
+	"app/internal/vo"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+// JobAdminService 异步任务运维服务接口
+type JobAdminService interface {
+	GetReconcileReport() (*job.ReconcileReport, error)
+	TriggerReconcile() error
+	ListQueues() ([]vo.JobQueueVo, error)
+	ListSchedulerEntries() ([]vo.JobSchedulerEntryVo, error)
+	ListQueueTasks(req *request.JobTaskListRequest) (*vo.PageResultVo[vo.JobTaskVo], error)
+	ListServers() ([]vo.JobServerVo, error)
+}
+
+type JobAdminServiceImpl struct {
+	db         *gorm.DB
+	jobService *job.JobService
+}
+
+// NewJobAdminService 创建JobAdminService实例
+func NewJobAdminService(db *gorm.DB, jobService *job.JobService) JobAdminService {
+	return &JobAdminServiceImpl{
+		db:         db,
+		jobService: jobService,
+	}
+}
+
+// GetReconcileReport 获取最近一次对账报告；尚未对账时返回 nil
+func (j *JobAdminServiceImpl) GetReconcileReport() (*job.ReconcileReport, error) {
+	return j.jobService.LastReconcileReport(context.Background())
+}
+
+// TriggerReconcile 立即执行一次对账（异步）
+func (j *JobAdminServiceImpl) TriggerReconcile() error {
+	_, err := j.jobService.TriggerReconcile()
+	return err
+}
+
+// ListQueues 队列概况
+func (j *JobAdminServiceImpl) ListQueues() ([]vo.JobQueueVo, error) {
+	inspector := j.jobService.NewInspector()
+	defer inspector.Close()
+
+	queues, err := inspector.Queues()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]vo.JobQueueVo, 0, len(queues))
+	for _, q := range queues {
+		info, err := inspector.GetQueueInfo(q)
+		if err != nil {
+			return nil, fmt.Errorf("读取队列%s失败: %v", q, err)
+		}
+		list = append(list, vo.JobQueueVo{
+			Queue:          info.Queue,
+			Paused:         info.Paused,
+			Size:           info.Size,
+			Pending:        info.Pending,
+			Active:         info.Active,
+			Scheduled:      info.Scheduled,
+			Retry:          info.Retry,
+			Archived:       info.Archived,
+			Completed:      info.Completed,
+			Processed:      info.Processed,
+			Failed:         info.Failed,
+			ProcessedTotal: info.ProcessedTotal,
+			FailedTotal:    info.FailedTotal,
+			LatencyMs:      info.Latency.Milliseconds(),
+			MemoryUsage:    info.MemoryUsage,
+		})
+	}
+	return list, nil
+}
+
+// ListSchedulerEntries 调度条目（周期任务与系统任务）
+func (j *JobAdminServiceImpl) ListSchedulerEntries() ([]vo.JobSchedulerEntryVo, error) {
+	inspector := j.jobService.NewInspector()
+	defer inspector.Close()
+
+	entries, err := inspector.SchedulerEntries()
+	if err != nil {
+		return nil, err
+	}
+	payloads := make([][]byte, 0, len(entries))
+	for _, e := range entries {
+		if e.Task != nil {
+			payloads = append(payloads, e.Task.Payload())
+		}
+	}
+	refs, err := j.loadTaskRefs(payloads)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]vo.JobSchedulerEntryVo, 0, len(entries))
+	for _, e := range entries {
+		item := vo.JobSchedulerEntryVo{
+			ID:   e.ID,
+			Spec: e.Spec,
+			Next: jobTime(e.Next),
+			Prev: jobTime(e.Prev),
+		}
+		if e.Task != nil {
+			item.TaskType = e.Task.Type()
+			item.Payload, item.Raw = decodeJobPayload(e.Task.Payload())
+			if item.Payload != nil {
+				item.Task = refs[item.Payload.TaskID]
+			}
+		}
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+// ListQueueTasks 按状态分页列出队列任务，并关联DB任务
+func (j *JobAdminServiceImpl) ListQueueTasks(req *request.JobTaskListRequest) (*vo.PageResultVo[vo.JobTaskVo], error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 10
+	}
+	queue := req.Queue
+	if queue == "" {
+		queue = "default"
+	}
+
+	inspector := j.jobService.NewInspector()
+	defer inspector.Close()
+
+	info, err := inspector.GetQueueInfo(queue)
+	if err != nil {
+		if errors.Is(err, asynq.ErrQueueNotFound) {
+			return &vo.PageResultVo[vo.JobTaskVo]{Total: 0, List: []vo.JobTaskVo{}}, nil
+		}
+		return nil, err
+	}
+
+	opts := []asynq.ListOption{asynq.Page(req.Page), asynq.PageSize(req.Limit)}
+	var tasks []*asynq.TaskInfo
+	var total int
+	switch req.State {
+	case "pending":
+		tasks, err = inspector.ListPendingTasks(queue, opts...)
+		total = info.Pending
+	case "active":
+		tasks, err = inspector.ListActiveTasks(queue, opts...)
+		total = info.Active
+	case "scheduled":
+		tasks, err = inspector.ListScheduledTasks(queue, opts...)
+		total = info.Scheduled
+	case "retry":
+		tasks, err = inspector.ListRetryTasks(queue, opts...)
+		total = info.Retry
+	case "archived":
+		tasks, err = inspector.ListArchivedTasks(queue, opts...)
+		total = info.Archived
+	case "completed":
+		tasks, err = inspector.ListCompletedTasks(queue, opts...)
+		total = info.Completed
+	default:
+		return nil, errors.New("state 仅支持 pending/active/scheduled/retry/archived/completed")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	payloads := make([][]byte, len(tasks))
+	for i, ti := range tasks {
+		payloads[i] = ti.Payload
+	}
+	refs, err := j.loadTaskRefs(payloads)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]vo.JobTaskVo, len(tasks))
+	for i, ti := range tasks {
+		item := vo.JobTaskVo{
+			ID:            ti.ID,
+			Queue:         ti.Queue,
+			Type:          ti.Type,
+			State:         ti.State.String(),
+			MaxRetry:      ti.MaxRetry,
+			Retried:       ti.Retried,
+			LastErr:       ti.LastErr,
+			LastFailedAt:  jobTime(ti.LastFailedAt),
+			NextProcessAt: jobTime(ti.NextProcessAt),
+			CompletedAt:   jobTime(ti.CompletedAt),
+		}
+		item.Payload, item.Raw = decodeJobPayload(ti.Payload)
+		if item.Payload != nil {
+			item.Task = refs[item.Payload.TaskID]
+		}
+		list[i] = item
+	}
+	return &vo.PageResultVo[vo.JobTaskVo]{Total: int64(total), List: list}, nil
+}
+
+// ListServers Worker 服务实例及正在处理的任务
+func (j *JobAdminServiceImpl) ListServers() ([]vo.JobServerVo, error) {
+	inspector := j.jobService.NewInspector()
+	defer inspector.Close()
+
+	servers, err := inspector.Servers()
+	if err != nil {
+		return nil, err
+	}
+	var payloads [][]byte
+	for _, s := range servers {
+		for _, w := range s.ActiveWorkers {
+			payloads = append(payloads, w.TaskPayload)
+		}
+	}
+	refs, err := j.loadTaskRefs(payloads)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]vo.JobServerVo, 0, len(servers))
+	for _, s := range servers {
+		item := vo.JobServerVo{
+			ID:             s.ID,
+			Host:           s.Host,
+			PID:            s.PID,
+			Concurrency:    s.Concurrency,
+			Queues:         s.Queues,
+			StrictPriority: s.StrictPriority,
+			Status:         s.Status,
+			Started:        jobTime(s.Started),
+			ActiveWorkers:  make([]vo.JobWorkerVo, 0, len(s.ActiveWorkers)),
+		}
+		for _, w := range s.ActiveWorkers {
+			worker := vo.JobWorkerVo{
+				ID:       w.TaskID,
+				Type:     w.TaskType,
+				Queue:    w.Queue,
+				Started:  jobTime(w.Started),
+				Deadline: jobTime(w.Deadline),
+			}
+			if p, _ := decodeJobPayload(w.TaskPayload); p != nil {
+				worker.Task = refs[p.TaskID]
+			}
+			item.ActiveWorkers = append(item.ActiveWorkers, worker)
+		}
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+// loadTaskRefs 按 payload 中的任务ID批量读取DB任务（含已删除）
+func (j *JobAdminServiceImpl) loadTaskRefs(payloads [][]byte) (map[uint64]*vo.JobTaskRefVo, error) {
+	ids := make([]uint64, 0, len(payloads))
+	for _, data := range payloads {
+		if p, err := job.DecodeJobPayload(data); err == nil && p.TaskID > 0 {
+			ids = append(ids, p.TaskID)
+		}
+	}
+	refs := make(map[uint64]*vo.JobTaskRefVo, len(ids))
+	if len(ids) == 0 {
+		return refs, nil
+	}
+	var tasks []model.Task
+	if err := j.db.Select("id", "task_name", "admin_id", "status", "is_delete").Where("id IN ?", ids).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		refs[t.ID] = &vo.JobTaskRefVo{
+			ID:         t.ID,
+			TaskName:   t.TaskName,
+			AdminID:    t.AdminID,
+			Status:     t.Status,
+			StatusText: (&vo.TaskVo{Status: t.Status}).GetStatusText(),
+			Deleted:    t.IsDelete == 1,
+		}
+	}
+	return refs, nil
+}
+
+// decodeJobPayload 解析队列任务 payload；无法解析时返回原始内容
+func decodeJobPayload(data []byte) (*vo.JobPayloadVo, string) {
+	if len(data) == 0 {
+		return nil, ""
+	}
+	p, err := job.DecodeJobPayload(data)
+	if err != nil {
+		return nil, string(data)
+	}
+	return &vo.JobPayloadVo{
+		Type:       p.Type,
+		Version:    p.Version,
+		TaskID:     p.TaskID,
+		RunID:      p.RunID,
+		ExpireTime: p.ExpireTime,
+	}, ""
+}
+
+// jobTime 零值时间返回 nil
+func jobTime(t time.Time) *vo.CustomTime {
+	if t.IsZero() {
+		return nil
+	}
+	return &vo.CustomTime{Time: t}
+}
@@ -115,7 +115,7 @@ func (s *MessageService) GetMessage(ctx context.Context, messageID uint, adminID
 		AdUserID:      message.AdUserID,
 		AdGroupLink:   message.AdGroupLink,
 		AdChannelLink: message.AdChannelLink,
-		CreateTime:    message.CreateTime,
+		CreateTime:    localTime(message.CreateTime),
 	}, nil
 }
 
@@ -181,7 +181,7 @@ func (s *MessageService) SearchMessages(ctx context.Context, req request.SearchM
 			AdUserID:      message.AdUserID,
 			AdGroupLink:   message.AdGroupLink,
 			AdChannelLink: message.AdChannelLink,
-			CreateTime:    message.CreateTime,
+			CreateTime:    localTime(message.CreateTime),
 		})
 	}
 
@@ -203,3 +203,12 @@ func (s *MessageService) DeleteMessage(ctx context.Context, messageID uint, admi
 		Where("id = ? AND admin_id = ?", messageID, adminID).
 		Update("status", 1).Error
 }
+
+// localTime 时间以UTC存储，回显时换算为服务默认时区
+func localTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	local := t.In(time.Local)
+	return &local
+}
@@ -1,19 +1,21 @@
 package service
 
 import (
+	"app/internal/config"
     "app/internal/job"
     "app/internal/model"
     "app/internal/request"
     "app/internal/vo"
     "app/tools/cron"
     "app/tools/logger"
+	"context"
     "encoding/json"
     "errors"
     "fmt"
+	"slices"
     "strings"
     "time"
 
-    "github.com/hibiken/asynq"
     "gorm.io/gorm"
 )
 
@@ -26,6 +28,28 @@ type TaskService interface {
     ListTasks(req *request.TaskListRequest, adminID uint) (*vo.TaskListVo, error)
     GetTaskStats(adminID uint) (*vo.TaskStatsVo, error)
     SubmitTask(req *request.SubmitTaskRequest, adminID uint) (*vo.TaskVo, error)
+	ListExecutions(req *request.TaskExecutionListRequest, adminID uint) (*vo.PageResultVo[vo.TaskExecutionVo], error)
+	GetExecutionDetail(id uint64, adminID uint) (*vo.TaskExecutionDetailVo, error)
+	PauseTask(req *request.PauseTaskRequest, adminID uint) (*vo.TaskVo, error)
+	ResumeTask(req *request.ResumeTaskRequest, adminID uint) (*vo.TaskVo, error)
+	TriggerTask(req *request.TriggerTaskRequest, adminID uint) (*vo.TaskTriggerVo, error)
+	PreviewCron(req *request.CronPreviewRequest) (*vo.CronPreviewVo, error)
+	GetCronPresets() *cron.PresetCronExpressions
+	ListDeadLetters(req *request.TaskDeadLetterListRequest, adminID uint) (*vo.PageResultVo[vo.JobTaskVo], error)
+	RequeueDeadLetters(req *request.TaskDeadLetterRequeueRequest, adminID uint) (*vo.TaskDeadLetterResultVo, error)
+	DeleteDeadLetters(req *request.TaskDeadLetterDeleteRequest, adminID uint) (*vo.TaskDeadLetterResultVo, error)
+	CloneTask(req *request.CloneTaskRequest, adminID uint) (*vo.TaskVo, error)
+	CreateTemplate(req *request.CreateTaskTemplateRequest, adminID uint) (*vo.TaskTemplateVo, error)
+	UpdateTemplate(req *request.UpdateTaskTemplateRequest, adminID uint) (*vo.TaskTemplateVo, error)
+	DeleteTemplate(req *request.DeleteTaskTemplateRequest, adminID uint) error
+	GetTemplate(id uint64, adminID uint) (*vo.TaskTemplateVo, error)
+	ListTemplates(req *request.TaskTemplateListRequest, adminID uint) (*vo.PageResultVo[vo.TaskTemplateVo], error)
+	InstantiateTemplate(req *request.InstantiateTaskTemplateRequest, adminID uint) (*vo.TaskVo, error)
+	BatchSubmitTasks(req *request.TaskBatchRequest, adminID uint) (*vo.TaskBatchResultVo, error)
+	BatchPauseTasks(req *request.TaskBatchRequest, adminID uint) (*vo.TaskBatchResultVo, error)
+	BatchResumeTasks(req *request.TaskBatchRequest, adminID uint) (*vo.TaskBatchResultVo, error)
+	BatchDeleteTasks(req *request.TaskBatchRequest, adminID uint) (*vo.TaskBatchResultVo, error)
+	BatchExtendExpire(req *request.TaskBatchExtendExpireRequest, adminID uint) (*vo.TaskBatchResultVo, error)
 }
 
 type TaskServiceImpl struct {
@@ -54,12 +78,232 @@ func (t *TaskServiceImpl) normalizeCronTo5(expr string) (string, bool) {
     return expr, false
 }
 
+// resolveTimezone 校验任务时区（IANA名称），为空时返回服务默认时区
+func (t *TaskServiceImpl) resolveTimezone(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.Local.String(), nil
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return "", fmt.Errorf("无效的时区: %s", name)
+	}
+	return name, nil
+}
+
+// resolveMisfirePolicy 校验错过执行策略；为空/为0时使用默认值（编辑时为任务原值）
+func resolveMisfirePolicy(policy string, limit int, defaultPolicy string, defaultLimit int) (string, int, error) {
+	policy = strings.TrimSpace(policy)
+	if policy == "" {
+		policy = defaultPolicy
+	}
+	switch policy {
+	case model.MisfirePolicySkip, model.MisfirePolicyFireOnce, model.MisfirePolicyCatchUp:
+	default:
+		return "", 0, fmt.Errorf("无效的错过执行策略: %s", policy)
+	}
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if limit < 1 || limit > model.MisfireCatchUpMax {
+		return "", 0, fmt.Errorf("补执行次数上限需在1-%d之间", model.MisfireCatchUpMax)
+	}
+	return policy, limit, nil
+}
+
+// resolvePriority 校验任务优先级；为空时使用默认值（编辑时为任务原值）
+func resolvePriority(priority, defaultPriority string) (string, error) {
+	priority = strings.TrimSpace(priority)
+	if priority == "" {
+		priority = defaultPriority
+	}
+	if priority == "" {
+		priority = config.PriorityNormal
+	}
+	switch priority {
+	case config.PriorityHigh, config.PriorityNormal, config.PriorityLow:
+		return priority, nil
+	}
+	return "", fmt.Errorf("无效的优先级: %s", priority)
+}
+
+// resolveRetryPolicy 校验重试退避策略；为空/为0时使用默认值（编辑时为任务原值）
+func resolveRetryPolicy(policy string, base, max int, defaultPolicy string, defaultBase, defaultMax int) (string, int, int, error) {
+	policy = strings.TrimSpace(policy)
+	if policy == "" {
+		policy = defaultPolicy
+	}
+	if policy == "" {
+		policy = model.RetryPolicyExponential
+	}
+	switch policy {
+	case model.RetryPolicyFixed, model.RetryPolicyLinear, model.RetryPolicyExponential:
+	default:
+		return "", 0, 0, fmt.Errorf("无效的重试策略: %s", policy)
+	}
+	if base == 0 {
+		base = defaultBase
+	}
+	if base == 0 {
+		base = model.DefaultRetryBaseSeconds
+	}
+	if max == 0 {
+		max = defaultMax
+	}
+	if max == 0 {
+		max = model.DefaultRetryMaxSeconds
+	}
+	if base < 1 || base > 86400 {
+		return "", 0, 0, errors.New("重试基准时长需在1-86400秒之间")
+	}
+	if max < base || max > 86400 {
+		return "", 0, 0, errors.New("重试时长上限需不小于基准时长且不超过86400秒")
+	}
+	return policy, base, max, nil
+}
+
+// resolveRotation 校验消息轮换模式与权重；模式为空时使用默认值，权重为nil时沿用原权重（编辑时为任务原值），
+// 原权重中已不在消息列表的条目会被丢弃（新增的消息按默认权重1）
+func resolveRotation(mode string, weights map[uint64]int, messageIDs []uint64, defaultMode string, defaultWeights model.JSON) (string, model.JSON, error) {
+	mode = strings.TrimSpace(mode)
+	if mode == "" {
+		mode = defaultMode
+	}
+	if mode == "" {
+		mode = model.RotationModeAll
+	}
+	switch mode {
+	case model.RotationModeAll, model.RotationModeRoundRobin, model.RotationModeRandom, model.RotationModeWeighted:
+	default:
+		return "", nil, fmt.Errorf("无效的消息轮换模式: %s", mode)
+	}
+	if weights == nil {
+		if len(defaultWeights) == 0 {
+			return mode, defaultWeights, nil
+		}
+		weights = job.ParseRotationWeights(defaultWeights)
+		for id := range weights {
+			if !slices.Contains(messageIDs, id) {
+				delete(weights, id)
+			}
+		}
+		if len(weights) == 0 {
+			return mode, nil, nil
+		}
+	}
+	for id, w := range weights {
+		found := false
+		for _, msgID := range messageIDs {
+			if msgID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", nil, fmt.Errorf("消息%d不在任务消息列表中，无法设置权重", id)
+		}
+		if w < 1 || w > 100 {
+			return "", nil, errors.New("消息权重需在1-100之间")
+		}
+	}
+	data, err := json.Marshal(weights)
+	if err != nil {
+		return "", nil, errors.New("消息权重序列化失败")
+	}
+	return mode, model.JSON(data), nil
+}
+
+// resolveCronExpression 根据表单模式与配置校验/生成Cron表达式
+// 未传表达式时由配置生成；同时传入时两者必须描述同一调度；custom 模式或无配置时直接使用表达式
+func (t *TaskServiceImpl) resolveCronExpression(expr string, pattern *model.CronPatternType, cronConfig map[string]interface{}) (string, error) {
+	expr = strings.TrimSpace(expr)
+	if pattern == nil || *pattern == model.CronPatternCustom || len(cronConfig) == 0 {
+		return expr, nil
+	}
+
+	raw, err := json.Marshal(cronConfig)
+	if err != nil {
+		return "", errors.New("Cron配置序列化失败")
+	}
+	var cfg cron.Config
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return "", errors.New("Cron配置格式错误: " + err.Error())
+	}
+	built, err := cron.BuildExpression(string(*pattern), &cfg)
+	if err != nil {
+		return "", errors.New("Cron配置错误: " + err.Error())
+	}
+	if expr == "" {
+		return built, nil
+	}
+
+	normalized, _ := t.normalizeCronTo5(expr)
+	same, err := t.cronUtils.SameSchedule(normalized, built)
+	if err != nil {
+		return "", errors.New("Cron表达式格式错误: " + err.Error())
+	}
+	if !same {
+		return "", fmt.Errorf("Cron表达式与配置不一致：表达式为 %s，按配置应为 %s", expr, built)
+	}
+	return expr, nil
+}
+
+// hasCronConfig 只有周期任务的表单模式（非custom）保存Cron配置快照，其余清空，避免残留的配置与表达式不一致
+func hasCronConfig(triggerType model.TriggerType, pattern *model.CronPatternType) bool {
+	return triggerType == model.TriggerTypeCron && pattern != nil && *pattern != model.CronPatternCustom
+}
+
+// cronConfigSnapshot 需要保存的Cron配置快照；不保存时返回 nil
+func cronConfigSnapshot(triggerType model.TriggerType, pattern *model.CronPatternType, cronConfig map[string]interface{}) (model.JSON, error) {
+	if !hasCronConfig(triggerType, pattern) || len(cronConfig) == 0 {
+		return nil, nil
+	}
+	raw, err := json.Marshal(cronConfig)
+	if err != nil {
+		return nil, errors.New("Cron配置序列化失败")
+	}
+	return model.JSON(raw), nil
+}
+
 // CreateTask 创建任务
 func (t *TaskServiceImpl) CreateTask(req *request.CreateTaskRequest, adminID uint) (*vo.TaskVo, error) {
+	// 时区：为空时使用服务默认时区
+	timezone, err := t.resolveTimezone(req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	req.Timezone = timezone
+
+	misfirePolicy, misfireLimit, err := resolveMisfirePolicy(req.MisfirePolicy, req.MisfireCatchUpLimit, model.MisfirePolicyFireOnce, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	priority, err := resolvePriority(req.Priority, config.PriorityNormal)
+	if err != nil {
+		return nil, err
+	}
+
+	rotationMode, rotationWeights, err := resolveRotation(req.RotationMode, req.RotationWeights, req.MessageIDs, model.RotationModeAll, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	retryPolicy, retryBase, retryMax, err := resolveRetryPolicy(req.RetryPolicy, req.RetryBaseSeconds, req.RetryMaxSeconds, model.RetryPolicyExponential, model.DefaultRetryBaseSeconds, model.DefaultRetryMaxSeconds)
+	if err != nil {
+		return nil, err
+	}
+
 	// 参数验证
 	if req.TriggerType == model.TriggerTypeSchedule && req.GetScheduleTime() == nil {
 		return nil, errors.New("定时执行类型必须指定执行时间")
 	}
+	if req.TriggerType == model.TriggerTypeCron {
+		expr, err := t.resolveCronExpression(req.CronExpression, req.CronPatternType, req.CronConfig)
+		if err != nil {
+			return nil, err
+		}
+		req.CronExpression = expr
+	}
 	if req.TriggerType == model.TriggerTypeCron && req.CronExpression == "" {
 		return nil, errors.New("周期执行类型必须指定Cron表达式")
 	}
@@ -96,6 +340,15 @@ func (t *TaskServiceImpl) CreateTask(req *request.CreateTaskRequest, adminID uin
         ScheduleTime:    req.GetScheduleTime(),
         // 定时执行任务不设置到期时间；周期任务保存到期时间
         ExpireTime:      func() *time.Time { if req.TriggerType == model.TriggerTypeCron { return req.GetExpireTime() }; return nil }(),
+        Timezone:        timezone,
+        MisfirePolicy:   misfirePolicy,
+        MisfireCatchUpLimit: misfireLimit,
+        Priority:        priority,
+        RotationMode:    rotationMode,
+        RotationWeights: rotationWeights,
+        RetryPolicy:     retryPolicy,
+        RetryBaseSeconds: retryBase,
+        RetryMaxSeconds: retryMax,
         CronExpression:  cronExpr,
         CronPatternType: req.CronPatternType,
         ExecuteCount:    0,
@@ -123,12 +376,9 @@ func (t *TaskServiceImpl) CreateTask(req *request.CreateTaskRequest, adminID uin
 	}
 	task.MessageIDs = model.JSON(messageIDsJSON)
 
-	if req.CronConfig != nil {
-		cronConfigJSON, err := json.Marshal(req.CronConfig)
-		if err != nil {
-			return nil, errors.New("Cron配置序列化失败")
-		}
-		task.CronConfig = model.JSON(cronConfigJSON)
+	task.CronConfig, err = cronConfigSnapshot(req.TriggerType, req.CronPatternType, req.CronConfig)
+	if err != nil {
+		return nil, err
 	}
 
     // 不在创建阶段计算 next_execute_at；改为提交阶段计算
@@ -142,6 +392,8 @@ func (t *TaskServiceImpl) CreateTask(req *request.CreateTaskRequest, adminID uin
 }
 
 // UpdateTask 更新任务
+// 待提交/已暂停的任务仅更新配置；已提交的任务先保存新配置再替换调度，替换失败时回滚配置并恢复旧调度
+// 先写DB：即使替换调度前进程退出，对账任务也会按DB中的新配置修正调度，不会丢失调度
 func (t *TaskServiceImpl) UpdateTask(req *request.UpdateTaskRequest, adminID uint) (*vo.TaskVo, error) {
     // 查找任务
     task := &model.Task{}
@@ -152,19 +404,79 @@ func (t *TaskServiceImpl) UpdateTask(req *request.UpdateTaskRequest, adminID uin
         return nil, err
     }
 
-    // 更新前旧值若需用于校验，可在此读取（当前策略：不做调度同步）
-
-    // 前端默认不允许编辑；若后续允许，仅支持待提交状态编辑
-    if task.Status != -1 {
-        return nil, errors.New("当前状态不允许编辑（仅待提交可编辑）")
+	// 正在执行的任务不可编辑（执行结束后会回写状态，覆盖编辑结果）
+	// 周期任务执行后保持状态1，需按队列中是否有执行中的运行判断
+	if task.Status == 1 {
+		running, err := t.jobService.HasActiveRun(task.ID)
+		if err != nil {
+			logger.Error("查询任务执行状态失败", "error", err, "taskID", task.ID)
+			return nil, errors.New("无法确认任务执行状态，请稍后再试")
+		}
+		if running {
+			return nil, errors.New("任务执行中，请稍后再编辑")
+		}
+	}
+	// 已完成的任务、已失败的一次性任务不可编辑
+	editable := task.Status == -1 || task.Status == 0 || task.Status == 1 || task.Status == 4 ||
+		(task.Status == 3 && task.TriggerType == model.TriggerTypeCron)
+	if !editable {
+		return nil, errors.New("当前状态不允许编辑")
     }
+	// 已提交且仍在调度中的任务，需要同步调度
+	live := task.Status == 0 || task.Status == 1 || task.Status == 3
+
+	// 时区：为空时保持任务原时区
+	if strings.TrimSpace(req.Timezone) == "" {
+		req.Timezone = task.Timezone
+	}
+	timezone, err := t.resolveTimezone(req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	req.Timezone = timezone
+
+	misfirePolicy, misfireLimit, err := resolveMisfirePolicy(req.MisfirePolicy, req.MisfireCatchUpLimit, task.MisfirePolicy, task.MisfireCatchUpLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	priority, err := resolvePriority(req.Priority, task.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	rotationMode, rotationWeights, err := resolveRotation(req.RotationMode, req.RotationWeights, req.MessageIDs, task.RotationMode, task.RotationWeights)
+	if err != nil {
+		return nil, err
+	}
+
+	retryPolicy, retryBase, retryMax, err := resolveRetryPolicy(req.RetryPolicy, req.RetryBaseSeconds, req.RetryMaxSeconds, task.RetryPolicy, task.RetryBaseSeconds, task.RetryMaxSeconds)
+	if err != nil {
+		return nil, err
+	}
 
     // 参数验证
     if req.TriggerType == model.TriggerTypeSchedule && req.GetScheduleTime() == nil {
         return nil, errors.New("定时执行类型必须指定执行时间")
+	}
+	if req.TriggerType == model.TriggerTypeCron {
+		// 未传配置时按已保存的配置校验，保证保存后的配置与表达式一致
+		if req.CronConfig == nil && len(task.CronConfig) > 0 {
+			if err := json.Unmarshal(task.CronConfig, &req.CronConfig); err != nil {
+				return nil, errors.New("已保存的Cron配置格式错误，请重新提交Cron配置")
+			}
+		}
+		expr, err := t.resolveCronExpression(req.CronExpression, req.CronPatternType, req.CronConfig)
+		if err != nil {
+			return nil, err
+		}
+		req.CronExpression = expr
     }
     if req.TriggerType == model.TriggerTypeCron && req.CronExpression == "" {
         return nil, errors.New("周期执行类型必须指定Cron表达式")
+	}
+	if req.TriggerType == model.TriggerTypeCron && req.GetExpireTime() == nil {
+		return nil, errors.New("周期执行类型必须指定到期时间")
     }
 
 	// 验证 Cron 表达式
@@ -184,16 +496,37 @@ func (t *TaskServiceImpl) UpdateTask(req *request.UpdateTaskRequest, adminID uin
         cronExpr = ""
     }
 
+	// 定时执行任务不设置到期时间；周期任务保存到期时间
+	var expireTime *time.Time
+	if req.TriggerType == model.TriggerTypeCron {
+		expireTime = req.GetExpireTime()
+	}
+	maxRetry := req.MaxRetryCount
+	if maxRetry == 0 {
+		maxRetry = 3
+	}
+
 	// 更新字段
+	now := time.Now()
     updates := map[string]interface{}{
-        "task_name":         req.TaskName,
-        "description":       req.Description,
-        "trigger_type":      req.TriggerType,
-        "schedule_time":     req.GetScheduleTime(),
-        "cron_expression":   cronExpr,
-        "cron_pattern_type": req.CronPatternType,
-        "max_retry_count":   req.MaxRetryCount,
-        "update_time":       time.Now(),
+		"task_name":              req.TaskName,
+		"description":            req.Description,
+		"trigger_type":           req.TriggerType,
+		"schedule_time":          req.GetScheduleTime(),
+		"expire_time":            expireTime,
+		"timezone":               timezone,
+		"cron_expression":        cronExpr,
+		"cron_pattern_type":      req.CronPatternType,
+		"max_retry_count":        maxRetry,
+		"misfire_policy":         misfirePolicy,
+		"misfire_catch_up_limit": misfireLimit,
+		"priority":               priority,
+		"rotation_mode":          rotationMode,
+		"rotation_weights":       rotationWeights,
+		"retry_policy":           retryPolicy,
+		"retry_base_seconds":     retryBase,
+		"retry_max_seconds":      retryMax,
+		"update_time":            now,
     }
 
     // 处理JSON字段
@@ -209,19 +542,53 @@ func (t *TaskServiceImpl) UpdateTask(req *request.UpdateTaskRequest, adminID uin
 	}
 	updates["message_ids"] = model.JSON(messageIDsJSON)
 
-	if req.CronConfig != nil {
-		cronConfigJSON, err := json.Marshal(req.CronConfig)
+	cronConfig, err := cronConfigSnapshot(req.TriggerType, req.CronPatternType, req.CronConfig)
+	if err != nil {
+		return nil, err
+	}
+	updates["cron_config"] = cronConfig
+
+	// 新调度配置
+	newTask := *task
+	newTask.TriggerType = req.TriggerType
+	newTask.ScheduleTime = req.GetScheduleTime()
+	newTask.ExpireTime = expireTime
+	newTask.Timezone = timezone
+	newTask.CronExpression = cronExpr
+	newTask.MaxRetryCount = maxRetry
+	newTask.Priority = priority
+	newTask.RotationMode = rotationMode
+	newTask.RotationWeights = rotationWeights
+	newTask.RetryPolicy = retryPolicy
+	newTask.RetryBaseSeconds = retryBase
+	newTask.RetryMaxSeconds = retryMax
+
+	if live {
+		next, err := t.calcNextExecuteAt(&newTask, now)
 		if err != nil {
-			return nil, errors.New("Cron配置序列化失败")
+			return nil, err
 		}
-		updates["cron_config"] = model.JSON(cronConfigJSON)
+		updates["status"] = 0 // 重新调度后回到待执行
+		updates["next_execute_at"] = next
+		updates["retry_count"] = 0
+		updates["error_message"] = ""
 	}
 
-    // 不在更新阶段计算 next_execute_at；改为提交阶段计算
-
-    // 执行更新
+	// 执行更新；失败时旧调度保持不变
     if err := t.db.Model(task).Updates(updates).Error; err != nil {
         return nil, err
+	}
+
+	if live {
+		if err := t.swapSchedule(task, &newTask); err != nil {
+			// 回滚为旧配置并恢复旧调度
+			if rbErr := t.db.Model(task).Updates(taskRollbackValues(task)).Error; rbErr != nil {
+				logger.Error("编辑任务回滚失败", "error", rbErr, "taskID", task.ID)
+			}
+			t.restoreSchedule(task)
+			return nil, err
+		}
+		logger.System("任务已重新调度", "taskID", task.ID, "triggerType", newTask.TriggerType, "cron", newTask.CronExpression, "nextExecuteAt", updates["next_execute_at"])
     }
 
 	// 重新查询更新后的数据
@@ -229,9 +596,69 @@ func (t *TaskServiceImpl) UpdateTask(req *request.UpdateTaskRequest, adminID uin
         return nil, err
     }
 
-    // 当前策略：创建/更新阶段不入队，提交时统一入队
+	return t.taskToVO(task), nil
+}
 
-    return t.taskToVO(task), nil
+// swapSchedule 以新配置替换asynq中的调度：周期条目注册即替换，先注册新调度再卸载旧调度；
+// 一次性任务使用固定TaskID，新旧均为一次性任务时只能先删除旧任务再入队
+func (t *TaskServiceImpl) swapSchedule(old, next *model.Task) error {
+	if old.TriggerType == model.TriggerTypeSchedule && next.TriggerType == model.TriggerTypeSchedule {
+		if err := t.unregisterFromScheduler(old); err != nil {
+			logger.Error("编辑任务时卸载旧调度失败", "error", err, "taskID", old.ID)
+		}
+		return t.registerToScheduler(next)
+	}
+	if err := t.registerToScheduler(next); err != nil {
+		return err
+	}
+	if old.TriggerType != next.TriggerType {
+		// 触发类型变更：旧调度不会被新注册替换，需单独卸载；条目可能已不存在，仅记录日志
+		if err := t.unregisterFromScheduler(old); err != nil {
+			logger.Error("编辑任务时卸载旧调度失败", "error", err, "taskID", old.ID)
+		}
+	}
+	return nil
+}
+
+// restoreSchedule 尽力恢复任务的旧调度（用于编辑失败回滚）
+func (t *TaskServiceImpl) restoreSchedule(task *model.Task) {
+	if task.TriggerType == model.TriggerTypeSchedule && (task.ScheduleTime == nil || task.ScheduleTime.Before(time.Now())) {
+		return
+	}
+	if err := t.registerToScheduler(task); err != nil {
+		logger.Error("恢复旧调度失败", "error", err, "taskID", task.ID)
+	}
+}
+
+// taskRollbackValues 编辑失败时用于回滚的旧字段值
+func taskRollbackValues(task *model.Task) map[string]interface{} {
+	return map[string]interface{}{
+		"task_name":              task.TaskName,
+		"description":            task.Description,
+		"trigger_type":           task.TriggerType,
+		"schedule_time":          task.ScheduleTime,
+		"expire_time":            task.ExpireTime,
+		"timezone":               task.Timezone,
+		"cron_expression":        task.CronExpression,
+		"cron_pattern_type":      task.CronPatternType,
+		"cron_config":            task.CronConfig,
+		"group_ids":              task.GroupIDs,
+		"message_ids":            task.MessageIDs,
+		"max_retry_count":        task.MaxRetryCount,
+		"misfire_policy":         task.MisfirePolicy,
+		"misfire_catch_up_limit": task.MisfireCatchUpLimit,
+		"priority":               task.Priority,
+		"rotation_mode":          task.RotationMode,
+		"rotation_weights":       task.RotationWeights,
+		"retry_policy":           task.RetryPolicy,
+		"retry_base_seconds":     task.RetryBaseSeconds,
+		"retry_max_seconds":      task.RetryMaxSeconds,
+		"status":                 task.Status,
+		"next_execute_at":        task.NextExecuteAt,
+		"retry_count":            task.RetryCount,
+		"error_message":          task.ErrorMessage,
+		"update_time":            time.Now(),
+	}
 }
 
 // DeleteTask 删除任务
@@ -256,28 +683,25 @@ func (t *TaskServiceImpl) DeleteTask(req *request.DeleteTaskRequest, adminID uin
         return err
     }
 
-    // 清理 asynq 队列（所有状态），并根据任务类型做对应卸载
-    go func(taskCopy model.Task) {
-        defer func() { recover() }()
-        // 定时一次性任务：删除 Scheduled 队列中的固定ID任务
-        if taskCopy.TriggerType == model.TriggerTypeSchedule {
-            if err := t.jobService.DeleteScheduledByDBTaskID(taskCopy.ID); err != nil {
-                logger.Error("移除一次性定时任务失败", "error", err, "taskID", taskCopy.ID)
-            }
-        }
-        // 周期任务：卸载 Scheduler 条目
-        if taskCopy.TriggerType == model.TriggerTypeCron && taskCopy.CronExpression != "" {
-            if _, err := t.jobService.UnregisterCronByTask(taskCopy.CronExpression, taskCopy.ID); err != nil {
-                logger.Error("卸载cron任务失败", "error", err, "taskID", taskCopy.ID)
-            }
-        }
-        // 兜底：清理所有队列中与该DB任务关联的任务（pending/active/scheduled/retry/archived/completed）
-        if removed, canceled, err := t.jobService.PurgeQueuesByDBTaskID(taskCopy.ID); err != nil {
-            logger.Error("清理Asynq队列任务失败", "error", err, "taskID", taskCopy.ID)
-        } else {
-            logger.System("已清理Asynq队列任务", "removed", removed, "canceled_active", canceled, "taskID", taskCopy.ID)
-        }
-    }(*task)
+	// 同步清理 asynq 队列（所有状态）并按任务类型卸载，失败只记录日志（已软删除，残留由对账清理）
+	// 定时一次性任务：删除 Scheduled 队列中的固定ID任务
+	if task.TriggerType == model.TriggerTypeSchedule {
+		if err := t.jobService.DeleteScheduledByDBTaskID(task.ID); err != nil {
+			logger.Error("移除一次性定时任务失败", "error", err, "taskID", task.ID)
+		}
+	}
+	// 周期任务：卸载 Scheduler 条目
+	if task.TriggerType == model.TriggerTypeCron && task.CronExpression != "" {
+		if err := t.jobService.UnregisterCronTask(task.ID); err != nil {
+			logger.Error("卸载cron任务失败", "error", err, "taskID", task.ID)
+		}
+	}
+	// 兜底：清理所有队列中与该DB任务关联的任务（pending/active/scheduled/retry/archived/completed）
+	if removed, canceled, err := t.jobService.PurgeQueuesByDBTaskID(task.ID); err != nil {
+		logger.Error("清理Asynq队列任务失败", "error", err, "taskID", task.ID)
+	} else {
+		logger.System("已清理Asynq队列任务", "removed", removed, "canceled_active", canceled, "taskID", task.ID)
+	}
 
     return nil
 }
@@ -292,7 +716,12 @@ func (t *TaskServiceImpl) GetTaskByID(id uint64, adminID uint) (*vo.TaskVo, erro
         return nil, err
     }
 
-	return t.taskToVO(task), nil
+	taskVO := t.taskToVO(task)
+	// 预览下一次执行将发送的消息
+	if next := t.nextRotationMessage(task, taskVO.MessageIDs); next > 0 {
+		taskVO.NextMessageID = &next
+	}
+	return taskVO, nil
 }
 
 // ListTasks 获取任务列表
@@ -308,7 +737,34 @@ func (t *TaskServiceImpl) ListTasks(req *request.TaskListRequest, adminID uint)
 	var tasks []model.Task
 	var total int64
 
-    query := t.db.Model(&model.Task{}).Where("admin_id = ? AND is_delete = 0", adminID)
+	query := t.taskListQuery(req, adminID)
+
+	// 获取总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	// 分页查询
+	offset := req.GetOffset()
+	if err := query.Offset(offset).Limit(req.Limit).Order("create_time DESC").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	// 转换为VO列表
+	taskVOs := make([]vo.TaskVo, len(tasks))
+	for i, task := range tasks {
+		taskVOs[i] = *t.taskToVO(&task)
+	}
+
+	return &vo.TaskListVo{
+		Total: total,
+		List:  taskVOs,
+	}, nil
+}
+
+// taskListQuery 按任务列表的筛选条件构建查询（不含分页），批量操作按同样的条件选取任务
+func (t *TaskServiceImpl) taskListQuery(req *request.TaskListRequest, adminID uint) *gorm.DB {
+	query := t.db.Model(&model.Task{}).Where("admin_id = ? AND is_delete = 0", adminID)
 
 	// 构建查询条件
 	if req.Status != nil {
@@ -350,28 +806,7 @@ func (t *TaskServiceImpl) ListTasks(req *request.TaskListRequest, adminID uint)
 			query = query.Where(messageQuery, messageArgs...)
 		}
 	}
-
-	// 获取总数
-	if err := query.Count(&total).Error; err != nil {
-		return nil, err
-	}
-
-	// 分页查询
-	offset := req.GetOffset()
-	if err := query.Offset(offset).Limit(req.Limit).Order("create_time DESC").Find(&tasks).Error; err != nil {
-		return nil, err
-	}
-
-	// 转换为VO列表
-	taskVOs := make([]vo.TaskVo, len(tasks))
-	for i, task := range tasks {
-		taskVOs[i] = *t.taskToVO(&task)
-	}
-
-	return &vo.TaskListVo{
-		Total: total,
-		List:  taskVOs,
-	}, nil
+	return query
 }
 
 // GetTaskStats 获取任务统计信息
@@ -396,6 +831,9 @@ func (t *TaskServiceImpl) GetTaskStats(adminID uint) (*vo.TaskStatsVo, error) {
     if err := t.db.Model(&model.Task{}).Where("admin_id = ? AND status = ? AND is_delete = 0", adminID, 3).Count(&stats.FailedCount).Error; err != nil {
         return nil, err
     }
+	if err := t.db.Model(&model.Task{}).Where("admin_id = ? AND status = ? AND is_delete = 0", adminID, 4).Count(&stats.PausedCount).Error; err != nil {
+		return nil, err
+	}
 
 	return stats, nil
 }
@@ -416,23 +854,57 @@ func (t *TaskServiceImpl) SubmitTask(req *request.SubmitTaskRequest, adminID uin
     }
 
     now := time.Now()
-    updates := map[string]interface{}{
-        "status":      0, // 待执行
-        "update_time": now,
+	next, err := t.calcNextExecuteAt(task, now)
+	if err != nil {
+		return nil, err
     }
 
-    // 预计算下一次执行时间
-    if task.TriggerType == model.TriggerTypeSchedule && task.ScheduleTime != nil {
-        updates["next_execute_at"] = task.ScheduleTime
-    } else if task.TriggerType == model.TriggerTypeCron && task.CronExpression != "" {
-        next, err := t.cronUtils.CalculateNextExecution(task.CronExpression, now)
+	updates := map[string]interface{}{
+		"status":          0, // 待执行
+		"next_execute_at": next,
+		"update_time":     now,
+	}
+	if err := t.db.Model(task).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	// 注册到asynq
+	if err := t.registerToScheduler(task); err != nil {
+		return nil, err
+	}
+
+	// 重新查询task
+	if err := t.db.Where("id = ?", task.ID).First(task).Error; err != nil {
+		return nil, err
+	}
+	t.jobService.EmitTaskEvent(task, model.WebhookEventTaskSubmitted, map[string]interface{}{"nextExecuteAt": task.NextExecuteAt})
+	return t.taskToVO(task), nil
+}
+
+// calcNextExecuteAt 计算下一次执行时间，并校验执行时间/到期时间
+func (t *TaskServiceImpl) calcNextExecuteAt(task *model.Task, now time.Time) (*time.Time, error) {
+	var next *time.Time
+	if task.TriggerType == model.TriggerTypeSchedule {
+		if task.ScheduleTime == nil {
+			return nil, errors.New("定时任务必须设置执行时间")
+		}
+		if task.ScheduleTime.Before(now) {
+			return nil, errors.New("执行时间已过期")
+		}
+		next = task.ScheduleTime
+	} else if task.TriggerType == model.TriggerTypeCron {
+		if task.CronExpression == "" {
+			return nil, errors.New("周期任务必须设置Cron表达式")
+		}
+		// 按任务时区计算下次执行时间
+		n, err := t.cronUtils.CalculateNextExecution(task.CronExpression, now.In(task.Location()))
         if err != nil {
             return nil, errors.New("计算下次执行时间失败: " + err.Error())
         }
-        updates["next_execute_at"] = next
+		next = n
     }
 
-    // 提交时校验到期时间：仅周期任务需要
+	// 校验到期时间：仅周期任务需要
     if task.TriggerType == model.TriggerTypeCron {
         if task.ExpireTime == nil {
             return nil, errors.New("周期任务到期时间必填")
@@ -440,86 +912,297 @@ func (t *TaskServiceImpl) SubmitTask(req *request.SubmitTaskRequest, adminID uin
         if task.ExpireTime.Before(now) || task.ExpireTime.Equal(now) {
             return nil, errors.New("任务到期时间已过期")
         }
-        if next, ok := updates["next_execute_at"].(*time.Time); ok && next != nil {
-            if !task.ExpireTime.After(*next) {
-                return nil, errors.New("到期时间必须晚于下一次执行时间")
-            }
+		if next != nil && !task.ExpireTime.After(*next) {
+			return nil, errors.New("到期时间必须晚于下一次执行时间")
         }
     }
+	return next, nil
+}
 
-    if err := t.db.Model(task).Updates(updates).Error; err != nil {
-        return nil, err
-    }
-
-    // 注册到asynq
-    var expireStr string
-    if task.ExpireTime != nil {
-        expireStr = task.ExpireTime.In(time.Local).Format("2006-01-02 15:04:05")
-    }
-    payload, _ := job.CreateJSONPayload(job.BotMsgPayload{
-        MsgType:    "bot_msg",
-        Content:    fmt.Sprintf("任务提交，任务ID：%d", task.ID),
-        TaskID:     task.ID,
-        ExpireTime: expireStr,
-    })
+// registerToScheduler 将任务注册到asynq：一次性任务按固定TaskID入队，周期任务注册Scheduler条目
+func (t *TaskServiceImpl) registerToScheduler(task *model.Task) error {
     if task.TriggerType == model.TriggerTypeSchedule {
-        if task.ScheduleTime == nil {
-            return nil, errors.New("定时任务必须设置执行时间")
-        }
-        if task.ScheduleTime.Before(now) {
-            return nil, errors.New("执行时间已过期")
-        }
-        taskID := fmt.Sprintf("schedule:%d", task.ID)
-        if _, err := t.jobService.ScheduleTaskWithID(job.BotMsgType, payload, *task.ScheduleTime, taskID, asynq.MaxRetry(task.MaxRetryCount)); err != nil {
-            return nil, fmt.Errorf("注册一次性任务失败: %v", err)
+		if err := t.jobService.EnqueueSchedule(task, *task.ScheduleTime, job.BotMsgType); err != nil {
+			return fmt.Errorf("注册一次性任务失败: %v", err)
         }
     } else if task.TriggerType == model.TriggerTypeCron {
-        if task.CronExpression == "" {
-            return nil, errors.New("周期任务必须设置Cron表达式")
-        }
-        if _, err := t.jobService.AddCronTask(task.CronExpression, job.BotMsgType, payload, asynq.MaxRetry(task.MaxRetryCount)); err != nil {
-            return nil, fmt.Errorf("注册周期任务失败: %v", err)
+		reg := job.NewCronRegistration(task, job.BotMsgType)
+		if err := t.jobService.RegisterCronTask(reg); err != nil {
+			return fmt.Errorf("注册周期任务失败: %v", err)
         }
     }
+	return nil
+}
+
+// unregisterFromScheduler 从asynq卸载任务：一次性任务删除固定TaskID任务，周期任务卸载Scheduler条目
+func (t *TaskServiceImpl) unregisterFromScheduler(task *model.Task) error {
+	if task.TriggerType == model.TriggerTypeSchedule {
+		return t.jobService.DeleteScheduledByDBTaskID(task.ID)
+	}
+	if task.TriggerType == model.TriggerTypeCron && task.CronExpression != "" {
+		return t.jobService.UnregisterCronTask(task.ID)
+	}
+	return nil
+}
+
+// PauseTask 暂停任务：卸载调度条目、置为已暂停(4)并清理等待中的队列任务，保留任务配置以便恢复
+func (t *TaskServiceImpl) PauseTask(req *request.PauseTaskRequest, adminID uint) (*vo.TaskVo, error) {
+	task := &model.Task{}
+	if err := t.db.Where("id = ? AND admin_id = ? AND is_delete = 0", req.ID, adminID).First(task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("任务不存在或无权限操作")
+		}
+		return nil, err
+	}
+
+	// 可暂停：待执行/执行中；周期任务失败后仍在调度，也允许暂停
+	pausable := task.Status == 0 || task.Status == 1 ||
+		(task.Status == 3 && task.TriggerType == model.TriggerTypeCron)
+	if !pausable {
+		return nil, errors.New("当前状态不允许暂停")
+	}
+
+	// 条目可能已不存在（如已执行或Redis被清理），仅记录日志；执行链路会按暂停状态跳过
+	if err := t.unregisterFromScheduler(task); err != nil {
+		logger.Error("暂停任务时卸载调度条目失败", "error", err, "taskID", task.ID)
+	}
+
+	updates := map[string]interface{}{
+		"status":          4, // 已暂停
+		"next_execute_at": nil,
+		"update_time":     time.Now(),
+	}
+	if err := t.db.Model(task).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	// 移除已入队的重试、失败补发与限流/静默期延后投递：置为暂停后再清理，清理期间开始执行的会按暂停状态跳过
+	// 恢复后从下一次调度重新开始，不补发暂停前失败或延后的部分
+	if removed, err := t.jobService.PurgeWaitingByDBTaskID(task.ID); err != nil {
+		logger.Error("暂停任务时清理队列任务失败", "error", err, "taskID", task.ID)
+	} else if removed > 0 {
+		logger.System("暂停任务已清理队列任务", "removed", removed, "taskID", task.ID)
+	}
+
+	if err := t.db.Where("id = ?", task.ID).First(task).Error; err != nil {
+		return nil, err
+	}
+	return t.taskToVO(task), nil
+}
+
+// ResumeTask 恢复任务：重新计算下一次执行时间并重新注册到asynq
+func (t *TaskServiceImpl) ResumeTask(req *request.ResumeTaskRequest, adminID uint) (*vo.TaskVo, error) {
+	task := &model.Task{}
+	if err := t.db.Where("id = ? AND admin_id = ? AND is_delete = 0", req.ID, adminID).First(task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("任务不存在或无权限操作")
+		}
+		return nil, err
+	}
+
+	if task.Status != 4 {
+		return nil, errors.New("仅已暂停的任务可恢复")
+	}
+
+	now := time.Now()
+	next, err := t.calcNextExecuteAt(task, now)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"status":          0, // 待执行
+		"next_execute_at": next,
+		"update_time":     now,
+	}
+	if err := t.db.Model(task).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	if err := t.registerToScheduler(task); err != nil {
+		// 注册失败回滚为暂停状态，避免出现“待执行但无调度”的任务
+		_ = t.db.Model(task).Updates(map[string]interface{}{
+			"status":          4,
+			"next_execute_at": nil,
+			"update_time":     time.Now(),
+		}).Error
+		return nil, err
+	}
 
-    // 重新查询task
     if err := t.db.Where("id = ?", task.ID).First(task).Error; err != nil {
         return nil, err
     }
     return t.taskToVO(task), nil
 }
 
+// TriggerTask 手动触发：立即入队执行一次，不影响cron调度与下一次执行时间
+func (t *TaskServiceImpl) TriggerTask(req *request.TriggerTaskRequest, adminID uint) (*vo.TaskTriggerVo, error) {
+	task := &model.Task{}
+	if err := t.db.Where("id = ? AND admin_id = ? AND is_delete = 0", req.ID, adminID).First(task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("任务不存在或无权限操作")
+		}
+		return nil, err
+	}
+
+	// 仅已提交且未结束的任务可手动触发：待提交的任务配置尚未确认，已暂停的任务应先恢复，已完成的任务请复制后重新提交
+	if task.Status != 0 && task.Status != 1 && task.Status != 3 {
+		return nil, errors.New("当前状态不允许手动触发")
+	}
+
+	// 已到期的周期任务执行链路会直接按到期处理，提前拦截
+	if task.TriggerType == model.TriggerTypeCron && task.ExpireTime != nil && !task.ExpireTime.After(time.Now()) {
+		return nil, errors.New("任务已到期，无法手动触发")
+	}
+
+	info, err := t.jobService.EnqueueRun(task, job.MsgTypeManualTrigger)
+	if err != nil {
+		return nil, fmt.Errorf("手动触发入队失败: %v", err)
+	}
+	logger.System("任务已手动触发", "taskID", task.ID, "jobID", info.ID, "queue", info.Queue)
+
+	return &vo.TaskTriggerVo{
+		TaskID:     task.ID,
+		JobID:      info.ID,
+		Queue:      info.Queue,
+		EnqueuedAt: vo.CustomTime{Time: time.Now()},
+	}, nil
+}
+
+// PreviewCron 校验Cron表达式并按指定时区（默认服务时区）预览接下来的执行时间，可按到期时间截断
+func (t *TaskServiceImpl) PreviewCron(req *request.CronPreviewRequest) (*vo.CronPreviewVo, error) {
+	timezone, err := t.resolveTimezone(req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	location := model.LoadLocation(timezone)
+
+	expr := strings.TrimSpace(req.CronExpression)
+	if normalized, changed := t.normalizeCronTo5(expr); changed {
+		expr = normalized
+	}
+
+	preview := &vo.CronPreviewVo{
+		CronExpression: expr,
+		Timezone:       location.String(),
+		NextExecutions: make([]vo.CustomTime, 0),
+	}
+
+	now := time.Now().In(location)
+	result := t.cronUtils.ParseAndCalculateNext(expr, now)
+	if !result.IsValid {
+		preview.ErrorMessage = result.ErrorMessage
+		return preview, nil
+	}
+	preview.IsValid = true
+	preview.Description = result.Description
+	preview.DescriptionEn = result.DescriptionEn
+
+	count := req.Count
+	if count <= 0 {
+		count = 5
+	} else if count > 10 {
+		count = 10
+	}
+	executions, err := t.cronUtils.GetNextExecutions(expr, now, count)
+	if err != nil {
+		return nil, err
+	}
+
+	expireAt := req.GetExpireTime()
+	for _, next := range executions {
+		if expireAt != nil && !next.Before(*expireAt) {
+			preview.Truncated = true
+			break
+		}
+		preview.NextExecutions = append(preview.NextExecutions, *vo.CustomTimeIn(*next, location))
+	}
+	return preview, nil
+}
+
+// GetCronPresets 获取预设的常用Cron表达式
+func (t *TaskServiceImpl) GetCronPresets() *cron.PresetCronExpressions {
+	return cron.GetPresetExpressions()
+}
+
+// nextRotationMessage 下一次执行将发送的消息ID（只读）；与执行时一致，只在未删除的消息中选取，尚未确定时返回 0
+func (t *TaskServiceImpl) nextRotationMessage(task *model.Task, messageIDs []uint64) uint64 {
+	if task.RotationMode == "" || task.RotationMode == model.RotationModeAll || len(messageIDs) < 2 {
+		return 0
+	}
+	var existing []uint64
+	if err := t.db.Model(&model.Message{}).Where("id IN ? AND status = 0", messageIDs).Pluck("id", &existing).Error; err != nil {
+		return 0
+	}
+	alive := make(map[uint64]bool, len(existing))
+	for _, id := range existing {
+		alive[id] = true
+	}
+	available := make([]uint64, 0, len(existing))
+	for _, id := range messageIDs {
+		if alive[id] {
+			available = append(available, id)
+		}
+	}
+	if len(available) == 1 {
+		return available[0]
+	}
+	next, err := t.jobService.NextRotationMessage(context.Background(), task, available)
+	if err != nil {
+		logger.Error("读取消息轮换状态失败", "taskID", task.ID, "error", err)
+		return 0
+	}
+	return next
+}
+
 // taskToVO 将任务模型转换为VO
 func (t *TaskServiceImpl) taskToVO(task *model.Task) *vo.TaskVo {
 	taskVO := &vo.TaskVo{
-		ID:              task.ID,
-		TaskName:        task.TaskName,
-		Description:     task.Description,
-		Status:          task.Status,
-		AdminID:         task.AdminID,
-		TriggerType:     task.TriggerType,
-		CronExpression:  task.CronExpression,
-		CronPatternType: task.CronPatternType,
-		ExecuteCount:    task.ExecuteCount,
-		RetryCount:      task.RetryCount,
-		MaxRetryCount:   task.MaxRetryCount,
-		ErrorMessage:    task.ErrorMessage,
-		CreateTime:      vo.CustomTime{Time: task.CreateTime},
-		UpdateTime:      vo.CustomTime{Time: task.UpdateTime},
-	}
-
-	// 转换时间字段
+		ID:                  task.ID,
+		TaskName:            task.TaskName,
+		Description:         task.Description,
+		Status:              task.Status,
+		AdminID:             task.AdminID,
+		TriggerType:         task.TriggerType,
+		CronExpression:      task.CronExpression,
+		CronPatternType:     task.CronPatternType,
+		ExecuteCount:        task.ExecuteCount,
+		RetryCount:          task.RetryCount,
+		MaxRetryCount:       task.MaxRetryCount,
+		MisfirePolicy:       task.MisfirePolicy,
+		MisfireCatchUpLimit: task.MisfireCatchUpLimit,
+		Priority:            task.Priority,
+		RetryPolicy:         task.RetryPolicy,
+		RetryBaseSeconds:    task.RetryBaseSeconds,
+		RetryMaxSeconds:     task.RetryMaxSeconds,
+		RotationMode:        task.RotationMode,
+		ErrorMessage:        task.ErrorMessage,
+		Timezone:            task.Location().String(),
+	}
+
+	// 转换时间字段：数据库以UTC存储，按任务时区回显
+	loc := task.Location()
+	taskVO.CreateTime = *vo.CustomTimeIn(task.CreateTime, loc)
+	taskVO.UpdateTime = *vo.CustomTimeIn(task.UpdateTime, loc)
     if task.ScheduleTime != nil {
-        taskVO.ScheduleTime = &vo.CustomTime{Time: *task.ScheduleTime}
+		taskVO.ScheduleTime = vo.CustomTimeIn(*task.ScheduleTime, loc)
     }
     if task.ExpireTime != nil {
-        taskVO.ExpireTime = &vo.CustomTime{Time: *task.ExpireTime}
+		taskVO.ExpireTime = vo.CustomTimeIn(*task.ExpireTime, loc)
     }
 	if task.LastExecutedAt != nil {
-		taskVO.LastExecutedAt = &vo.CustomTime{Time: *task.LastExecutedAt}
+		taskVO.LastExecutedAt = vo.CustomTimeIn(*task.LastExecutedAt, loc)
 	}
 	if task.NextExecuteAt != nil {
-		taskVO.NextExecuteAt = &vo.CustomTime{Time: *task.NextExecuteAt}
+		taskVO.NextExecuteAt = vo.CustomTimeIn(*task.NextExecuteAt, loc)
+	}
+
+	// Cron 人性化描述
+	if task.CronExpression != "" {
+		if desc, err := cron.Describe(task.CronExpression); err == nil {
+			taskVO.CronDescription = desc.Zh
+			taskVO.CronDescriptionEn = desc.En
+		}
 	}
 
 	// 设置状态和类型文本
@@ -541,6 +1224,11 @@ func (t *TaskServiceImpl) taskToVO(task *model.Task) *vo.TaskVo {
 		}
 	}
 
+	// 消息轮换：回显权重（下一次执行将发送的消息仅在详情中预览）
+	if task.RotationMode == model.RotationModeWeighted {
+		taskVO.RotationWeights = job.ParseRotationWeights(task.RotationWeights)
+	}
+
 	if len(task.CronConfig) > 0 {
 		var cronConfig map[string]interface{}
 		if err := json.Unmarshal(task.CronConfig, &cronConfig); err == nil {
@@ -548,5 +1236,22 @@ func (t *TaskServiceImpl) taskToVO(task *model.Task) *vo.TaskVo {
 		}
 	}
 
+	// 历史数据未保存表单快照时，由表达式反解用于回显
+	if taskVO.CronConfig == nil && task.CronExpression != "" {
+		pattern, cfg := cron.ParseExpression(task.CronExpression)
+		if taskVO.CronPatternType == nil {
+			patternType := model.CronPatternType(pattern)
+			taskVO.CronPatternType = &patternType
+		}
+		if cfg != nil && string(*taskVO.CronPatternType) == pattern {
+			if raw, err := json.Marshal(cfg); err == nil {
+				var cronConfig map[string]interface{}
+				if err := json.Unmarshal(raw, &cronConfig); err == nil {
+					taskVO.CronConfig = cronConfig
+				}
+			}
+		}
+	}
+
 	return taskVO
 }
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"app/internal/model"
+	"app/internal/request"
+	"app/internal/vo"
+	"app/tools/logger"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 单次批量操作的任务数上限
+const taskBatchMaxItems = 500
+
+// BatchSubmitTasks 批量提交任务
+func (t *TaskServiceImpl) BatchSubmitTasks(req *request.TaskBatchRequest, adminID uint) (*vo.TaskBatchResultVo, error) {
+	return t.runBatch(req, adminID, func(id uint64) (*vo.TaskVo, error) {
+		return t.SubmitTask(&request.SubmitTaskRequest{ID: id}, adminID)
+	})
+}
+
+// BatchPauseTasks 批量暂停任务
+func (t *TaskServiceImpl) BatchPauseTasks(req *request.TaskBatchRequest, adminID uint) (*vo.TaskBatchResultVo, error) {
+	return t.runBatch(req, adminID, func(id uint64) (*vo.TaskVo, error) {
+		return t.PauseTask(&request.PauseTaskRequest{ID: id}, adminID)
+	})
+}
+
+// BatchResumeTasks 批量恢复任务
+func (t *TaskServiceImpl) BatchResumeTasks(req *request.TaskBatchRequest, adminID uint) (*vo.TaskBatchResultVo, error) {
+	return t.runBatch(req, adminID, func(id uint64) (*vo.TaskVo, error) {
+		return t.ResumeTask(&request.ResumeTaskRequest{ID: id}, adminID)
+	})
+}
+
+// BatchDeleteTasks 批量删除任务
+func (t *TaskServiceImpl) BatchDeleteTasks(req *request.TaskBatchRequest, adminID uint) (*vo.TaskBatchResultVo, error) {
+	return t.runBatch(req, adminID, func(id uint64) (*vo.TaskVo, error) {
+		return nil, t.DeleteTask(&request.DeleteTaskRequest{ID: id}, adminID)
+	})
+}
+
+// BatchExtendExpire 批量修改周期任务的到期时间
+func (t *TaskServiceImpl) BatchExtendExpire(req *request.TaskBatchExtendExpireRequest, adminID uint) (*vo.TaskBatchResultVo, error) {
+	if (req.ExpireTime == nil) == (req.ExtendHours == 0) {
+		return nil, errors.New("请指定新的到期时间或延长小时数（二选一）")
+	}
+	if req.ExtendHours < 0 {
+		return nil, errors.New("延长小时数必须大于0")
+	}
+	return t.runBatch(&req.TaskBatchRequest, adminID, func(id uint64) (*vo.TaskVo, error) {
+		return t.extendExpire(id, adminID, req.ExpireTime, req.ExtendHours)
+	})
+}
+
+// runBatch 逐个执行单任务操作（与单个接口相同的权限校验与asynq清理），单个失败不影响其他任务
+func (t *TaskServiceImpl) runBatch(req *request.TaskBatchRequest, adminID uint, fn func(id uint64) (*vo.TaskVo, error)) (*vo.TaskBatchResultVo, error) {
+	ids, err := t.batchTaskIDs(req, adminID)
+	if err != nil {
+		return nil, err
+	}
+	result := &vo.TaskBatchResultVo{
+		Requested: len(ids),
+		Items:     make([]vo.TaskBatchItemVo, 0, len(ids)),
+	}
+	for _, id := range ids {
+		item := vo.TaskBatchItemVo{ID: id}
+		taskVO, err := fn(id)
+		if err != nil {
+			item.Error = err.Error()
+			result.Failed++
+		} else {
+			item.Success = true
+			item.Task = taskVO
+			result.Succeeded++
+		}
+		result.Items = append(result.Items, item)
+	}
+	logger.System("批量操作任务完成", "adminID", adminID, "requested", result.Requested, "succeeded", result.Succeeded, "failed", result.Failed)
+	return result, nil
+}
+
+// batchTaskIDs 解析批量操作的任务ID：指定ID时去重保序；按筛选条件时选取当前管理员匹配的全部任务
+func (t *TaskServiceImpl) batchTaskIDs(req *request.TaskBatchRequest, adminID uint) ([]uint64, error) {
+	if len(req.IDs) > 0 {
+		seen := make(map[uint64]struct{}, len(req.IDs))
+		ids := make([]uint64, 0, len(req.IDs))
+		for _, id := range req.IDs {
+			if _, ok := seen[id]; ok || id == 0 {
+				continue
+			}
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+		if len(ids) > taskBatchMaxItems {
+			return nil, fmt.Errorf("单次最多操作%d个任务", taskBatchMaxItems)
+		}
+		return ids, nil
+	}
+	if req.Filter == nil {
+		return nil, errors.New("请指定任务ID列表或筛选条件")
+	}
+	var ids []uint64
+	if err := t.taskListQuery(req.Filter, adminID).Order("id ASC").Limit(taskBatchMaxItems+1).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) > taskBatchMaxItems {
+		return nil, fmt.Errorf("匹配的任务超过%d个，请缩小筛选范围", taskBatchMaxItems)
+	}
+	return ids, nil
+}
+
+// extendExpire 修改周期任务的到期时间；已在调度中的任务重新注册，使调度payload携带新的到期时间；
+// 已到期的任务（状态2）回到待执行并重新注册
+func (t *TaskServiceImpl) extendExpire(id uint64, adminID uint, expireTime *request.FlexibleTime, extendHours int) (*vo.TaskVo, error) {
+	task := &model.Task{}
+	if err := t.db.Where("id = ? AND admin_id = ? AND is_delete = 0", id, adminID).First(task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("任务不存在或无权限操作")
+		}
+		return nil, err
+	}
+	if task.TriggerType != model.TriggerTypeCron {
+		return nil, errors.New("仅周期任务有到期时间")
+	}
+
+	now := time.Now()
+	var next time.Time
+	if expireTime != nil {
+		next = expireTime.In(task.Location())
+	} else {
+		base := now
+		if task.ExpireTime != nil && task.ExpireTime.After(now) {
+			base = *task.ExpireTime
+		}
+		next = base.Add(time.Duration(extendHours) * time.Hour)
+	}
+	if !next.After(now) {
+		return nil, errors.New("到期时间必须晚于当前时间")
+	}
+
+	old := task.ExpireTime
+	updates := map[string]interface{}{
+		"expire_time": &next,
+		"update_time": now,
+	}
+	rollback := map[string]interface{}{
+		"expire_time": old,
+	}
+	// 已到期（周期任务到期后标记为已完成）：延长后回到待执行并重新注册调度
+	revived := task.Status == 2
+	if revived {
+		task.ExpireTime = &next
+		nextRun, err := t.calcNextExecuteAt(task, now)
+		if err != nil {
+			return nil, err
+		}
+		updates["status"] = 0
+		updates["next_execute_at"] = nextRun
+		rollback["status"] = 2
+		rollback["next_execute_at"] = nil
+	}
+	if err := t.db.Model(task).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	task.ExpireTime = &next
+
+	// 待执行/执行中/失败（周期任务失败后仍在调度）及重新启用的已到期任务需要（重新）注册调度条目；待提交与已暂停的任务在提交/恢复时注册
+	if revived || task.Status == 0 || task.Status == 1 || task.Status == 3 {
+		if err := t.registerToScheduler(task); err != nil {
+			rollback["update_time"] = time.Now()
+			_ = t.db.Model(task).Updates(rollback).Error
+			return nil, err
+		}
+	}
+	if revived {
+		logger.System("已到期的周期任务延长到期时间后重新启用", "taskID", task.ID, "expireTime", next)
+	}
+
+	if err := t.db.Where("id = ?", task.ID).First(task).Error; err != nil {
+		return nil, err
+	}
+	return t.taskToVO(task), nil
+}
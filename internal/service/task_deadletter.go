@@ -0,0 +1,267 @@
+package service
+
+import (
+	"app/internal/job"
+	"app/internal/model"
+	"app/internal/request"
+	"app/internal/vo"
+	"app/tools/logger"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+// ListDeadLetters 分页查询任务在asynq中重试耗尽后归档的队列任务
+func (t *TaskServiceImpl) ListDeadLetters(req *request.TaskDeadLetterListRequest, adminID uint) (*vo.PageResultVo[vo.JobTaskVo], error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 10
+	}
+
+	// 已删除任务的归档仍可查看
+	task := &model.Task{}
+	if err := t.db.Where("id = ? AND admin_id = ?", req.TaskID, adminID).First(task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("任务不存在或无权限查看")
+		}
+		return nil, err
+	}
+
+	archived, err := t.jobService.ListArchivedByDBTaskID(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	total := len(archived)
+	start := req.GetOffset()
+	if start > total {
+		start = total
+	}
+	end := start + req.Limit
+	if end > total {
+		end = total
+	}
+
+	ref := &vo.JobTaskRefVo{
+		ID:         task.ID,
+		TaskName:   task.TaskName,
+		AdminID:    task.AdminID,
+		Status:     task.Status,
+		StatusText: (&vo.TaskVo{Status: task.Status}).GetStatusText(),
+		Deleted:    task.IsDelete == 1,
+	}
+	list := make([]vo.JobTaskVo, 0, end-start)
+	for _, ti := range archived[start:end] {
+		item := vo.JobTaskVo{
+			ID:           ti.ID,
+			Queue:        ti.Queue,
+			Type:         ti.Type,
+			State:        ti.State.String(),
+			Task:         ref,
+			MaxRetry:     ti.MaxRetry,
+			Retried:      ti.Retried,
+			LastErr:      ti.LastErr,
+			LastFailedAt: jobTime(ti.LastFailedAt),
+		}
+		item.Payload, item.Raw = decodeJobPayload(ti.Payload)
+		list = append(list, item)
+	}
+	return &vo.PageResultVo[vo.JobTaskVo]{Total: int64(total), List: list}, nil
+}
+
+// RequeueDeadLetters 重新执行归档任务，并重置任务的重试计数与状态；执行结果沿用正常执行链路回写
+func (t *TaskServiceImpl) RequeueDeadLetters(req *request.TaskDeadLetterRequeueRequest, adminID uint) (*vo.TaskDeadLetterResultVo, error) {
+	task := &model.Task{}
+	if err := t.db.Where("id = ? AND admin_id = ? AND is_delete = 0", req.TaskID, adminID).First(task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("任务不存在或无权限操作")
+		}
+		return nil, err
+	}
+	switch task.Status {
+	case -1:
+		return nil, errors.New("任务未提交，无法重新执行")
+	case 2:
+		return nil, errors.New("任务已完成，无法重新执行")
+	case 4:
+		return nil, errors.New("任务已暂停，请先恢复任务")
+	}
+
+	var payload *job.JobPayload
+	if len(req.Payload) > 0 && string(req.Payload) != "null" {
+		p, err := job.DecodeJobPayload(req.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("payload格式错误: %v", err)
+		}
+		if p.TaskID == 0 {
+			p.TaskID = task.ID
+		}
+		if p.TaskID != task.ID {
+			return nil, errors.New("payload中的任务ID与当前任务不一致")
+		}
+		if err := t.checkPayloadTargets(task, p.Targets, adminID); err != nil {
+			return nil, err
+		}
+		payload = p
+	}
+
+	targets, result, err := t.selectDeadLetters(task.ID, req.JobIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, ti := range targets {
+		var p *job.JobPayload
+		if payload != nil {
+			copied := *payload
+			p = &copied
+		}
+		err := t.jobService.RequeueArchived(task, ti, p)
+		var kept *job.ArchiveNotDeletedError
+		switch {
+		case errors.As(err, &kept):
+			// 已重新入队，原归档任务仍在：计为成功并提示手动删除
+			result.Warnings = append(result.Warnings, vo.TaskDeadLetterFailVo{JobID: ti.ID, Error: err.Error()})
+		case err != nil:
+			result.Failed = append(result.Failed, vo.TaskDeadLetterFailVo{JobID: ti.ID, Error: err.Error()})
+			continue
+		}
+		result.Succeeded++
+	}
+
+	if result.Succeeded > 0 {
+		// 重新执行：清零重试计数；失败状态回到待执行，执行结果由 updateTaskOnSuccess/updateTaskOnFailure 回写
+		now := time.Now()
+		updates := map[string]interface{}{
+			"retry_count": 0,
+			"update_time": now,
+		}
+		if task.Status == 3 {
+			updates["status"] = 0
+			if task.TriggerType == model.TriggerTypeSchedule {
+				updates["next_execute_at"] = &now
+			}
+		}
+		if err := t.db.Model(&model.Task{}).Where("id = ?", task.ID).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+		logger.System("归档任务已重新执行", "taskID", task.ID, "count", result.Succeeded, "newPayload", payload != nil)
+	}
+	return result, nil
+}
+
+// DeleteDeadLetters 删除归档任务
+func (t *TaskServiceImpl) DeleteDeadLetters(req *request.TaskDeadLetterDeleteRequest, adminID uint) (*vo.TaskDeadLetterResultVo, error) {
+	var count int64
+	if err := t.db.Model(&model.Task{}).Where("id = ? AND admin_id = ?", req.TaskID, adminID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("任务不存在或无权限操作")
+	}
+
+	targets, result, err := t.selectDeadLetters(req.TaskID, req.JobIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, ti := range targets {
+		if err := t.jobService.DeleteArchived(ti); err != nil {
+			result.Failed = append(result.Failed, vo.TaskDeadLetterFailVo{JobID: ti.ID, Error: err.Error()})
+			continue
+		}
+		result.Succeeded++
+	}
+	logger.System("归档任务已删除", "taskID", req.TaskID, "count", result.Succeeded)
+	return result, nil
+}
+
+// checkPayloadTargets 校验自定义payload中的投递目标：群组与消息必须在任务配置内，且属于当前管理员
+// （Handler 按群组/消息ID直接读取机器人配置与消息内容，不再校验归属）
+func (t *TaskServiceImpl) checkPayloadTargets(task *model.Task, targets []job.DeliveryTarget, adminID uint) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	var taskGroups []int64
+	var taskMessages []uint64
+	_ = json.Unmarshal(task.GroupIDs, &taskGroups)
+	_ = json.Unmarshal(task.MessageIDs, &taskMessages)
+
+	groupIDs := make([]int64, 0, len(targets))
+	var messageIDs []uint64
+	for _, target := range targets {
+		if !slices.Contains(taskGroups, target.GroupID) {
+			return fmt.Errorf("payload中的群组%d不属于当前任务", target.GroupID)
+		}
+		for _, id := range target.MessageIDs {
+			if !slices.Contains(taskMessages, id) {
+				return fmt.Errorf("payload中的消息%d不属于当前任务", id)
+			}
+		}
+		if !slices.Contains(groupIDs, target.GroupID) {
+			groupIDs = append(groupIDs, target.GroupID)
+		}
+		for _, id := range target.MessageIDs {
+			if !slices.Contains(messageIDs, id) {
+				messageIDs = append(messageIDs, id)
+			}
+		}
+	}
+
+	var count int64
+	if err := t.db.Model(&model.Group{}).Where("admin_id = ? AND group_id IN ?", adminID, groupIDs).
+		Distinct("group_id").Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(groupIDs) {
+		return errors.New("payload中的群组不存在或无权限使用")
+	}
+	if len(messageIDs) > 0 {
+		if err := t.db.Model(&model.Message{}).Where("admin_id = ? AND id IN ?", adminID, messageIDs).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if int(count) != len(messageIDs) {
+			return errors.New("payload中的消息不存在或无权限使用")
+		}
+	}
+	return nil
+}
+
+// selectDeadLetters 按ID筛选任务的归档任务；ID为空时选中全部，不属于该任务的ID记为失败
+func (t *TaskServiceImpl) selectDeadLetters(taskID uint64, jobIDs []string) ([]*asynq.TaskInfo, *vo.TaskDeadLetterResultVo, error) {
+	archived, err := t.jobService.ListArchivedByDBTaskID(taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := &vo.TaskDeadLetterResultVo{TaskID: taskID, Failed: []vo.TaskDeadLetterFailVo{}}
+	if len(jobIDs) == 0 {
+		result.Requested = len(archived)
+		return archived, result, nil
+	}
+
+	byID := make(map[string]*asynq.TaskInfo, len(archived))
+	for _, ti := range archived {
+		byID[ti.ID] = ti
+	}
+	targets := make([]*asynq.TaskInfo, 0, len(jobIDs))
+	seen := make(map[string]bool, len(jobIDs))
+	for _, id := range jobIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result.Requested++
+		ti, ok := byID[id]
+		if !ok {
+			result.Failed = append(result.Failed, vo.TaskDeadLetterFailVo{JobID: id, Error: "归档任务不存在或不属于该任务"})
+			continue
+		}
+		targets = append(targets, ti)
+	}
+	return targets, result, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"app/internal/model"
+	"app/internal/request"
+	"app/internal/vo"
+	"encoding/json"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ListExecutions 分页查询任务执行记录
+func (t *TaskServiceImpl) ListExecutions(req *request.TaskExecutionListRequest, adminID uint) (*vo.PageResultVo[vo.TaskExecutionVo], error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 10
+	}
+
+	// 校验任务归属（已删除任务的历史仍可查看）
+	var count int64
+	if err := t.db.Model(&model.Task{}).Where("id = ? AND admin_id = ?", req.TaskID, adminID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("任务不存在或无权限查看")
+	}
+
+	query := t.db.Model(&model.TaskExecution{}).Where("task_id = ? AND admin_id = ?", req.TaskID, adminID)
+	if req.Status != nil {
+		query = query.Where("status = ?", *req.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var executions []model.TaskExecution
+	if err := query.Offset(req.GetOffset()).Limit(req.Limit).Order("id DESC").Find(&executions).Error; err != nil {
+		return nil, err
+	}
+
+	list := make([]vo.TaskExecutionVo, len(executions))
+	for i := range executions {
+		list[i] = executionToVO(&executions[i])
+	}
+	return &vo.PageResultVo[vo.TaskExecutionVo]{Total: total, List: list}, nil
+}
+
+// GetExecutionDetail 获取执行记录详情（含各群组投递明细）
+func (t *TaskServiceImpl) GetExecutionDetail(id uint64, adminID uint) (*vo.TaskExecutionDetailVo, error) {
+	var execution model.TaskExecution
+	if err := t.db.Where("id = ? AND admin_id = ?", id, adminID).First(&execution).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("执行记录不存在或无权限查看")
+		}
+		return nil, err
+	}
+
+	var deliveries []model.TaskDelivery
+	if err := t.db.Where("execution_id = ?", execution.ID).Order("id ASC").Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	// 补充群组名称
+	groupNames := make(map[int64]string)
+	if len(deliveries) > 0 {
+		groupIDs := make([]int64, 0, len(deliveries))
+		for _, d := range deliveries {
+			groupIDs = append(groupIDs, d.GroupID)
+		}
+		var groups []model.Group
+		if err := t.db.Where("admin_id = ? AND group_id IN ? AND status = 0", adminID, groupIDs).Find(&groups).Error; err == nil {
+			for _, g := range groups {
+				groupNames[g.GroupID] = g.GroupName
+			}
+		}
+	}
+
+	detail := &vo.TaskExecutionDetailVo{
+		TaskExecutionVo: executionToVO(&execution),
+		Deliveries:      make([]vo.TaskDeliveryVo, len(deliveries)),
+	}
+	for i, d := range deliveries {
+		dv := vo.TaskDeliveryVo{
+			ID:           d.ID,
+			GroupID:      d.GroupID,
+			GroupName:    groupNames[d.GroupID],
+			MessageID:    d.MessageID,
+			Status:       d.Status,
+			StatusText:   vo.GetDeliveryStatusText(d.Status),
+			TgMessageID:  d.TgMessageID,
+			LatencyMs:    d.LatencyMs,
+			ErrorMessage: d.ErrorMessage,
+			CreateTime:   vo.CustomTime{Time: d.CreateTime},
+		}
+		if len(d.TgMessageIDs) > 0 {
+			_ = json.Unmarshal(d.TgMessageIDs, &dv.TgMessageIDs)
+		}
+		detail.Deliveries[i] = dv
+	}
+	return detail, nil
+}
+
+// executionToVO 将执行记录模型转换为VO
+func executionToVO(e *model.TaskExecution) vo.TaskExecutionVo {
+	v := vo.TaskExecutionVo{
+		ID:            e.ID,
+		TaskID:        e.TaskID,
+		RunID:         e.RunID,
+		Source:        e.Source,
+		Attempt:       e.Attempt,
+		Status:        e.Status,
+		StatusText:    vo.GetExecutionStatusText(e.Status),
+		TotalCount:    e.TotalCount,
+		SuccessCount:  e.SuccessCount,
+		FailedCount:   e.FailedCount,
+		DeferredCount: e.DeferredCount,
+		SkippedCount:  e.SkippedCount,
+		ErrorMessage:  e.ErrorMessage,
+		StartedAt:     vo.CustomTime{Time: e.StartedAt},
+		DurationMs:    e.DurationMs,
+	}
+	if e.FinishedAt != nil {
+		v.FinishedAt = &vo.CustomTime{Time: *e.FinishedAt}
+	}
+	return v
+}
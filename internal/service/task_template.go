@@ -0,0 +1,388 @@
+package service
+
+import (
+	"app/internal/model"
+	"app/internal/request"
+	"app/internal/vo"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// 任务名称最大长度（task.task_name VARCHAR(50)）
+const taskNameMaxLen = 50
+
+// CloneTask 复制任务（群组、消息、调度与重试等配置）为新的待提交任务；执行次数、状态等运行数据不复制
+func (t *TaskServiceImpl) CloneTask(req *request.CloneTaskRequest, adminID uint) (*vo.TaskVo, error) {
+	src := &model.Task{}
+	if err := t.db.Where("id = ? AND admin_id = ? AND is_delete = 0", req.ID, adminID).First(src).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("任务不存在或无权限操作")
+		}
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.TaskName)
+	if name == "" {
+		name = src.TaskName + "-副本"
+	}
+	now := time.Now()
+	task := &model.Task{
+		TaskName:            truncateRunes(name, taskNameMaxLen),
+		Description:         src.Description,
+		Status:              -1, // 待提交
+		AdminID:             adminID,
+		GroupIDs:            src.GroupIDs,
+		MessageIDs:          src.MessageIDs,
+		TriggerType:         src.TriggerType,
+		ScheduleTime:        src.ScheduleTime,
+		ExpireTime:          src.ExpireTime,
+		Timezone:            src.Timezone,
+		CronExpression:      src.CronExpression,
+		CronPatternType:     src.CronPatternType,
+		CronConfig:          src.CronConfig,
+		MaxRetryCount:       src.MaxRetryCount,
+		MisfirePolicy:       src.MisfirePolicy,
+		MisfireCatchUpLimit: src.MisfireCatchUpLimit,
+		Priority:            src.Priority,
+		RetryPolicy:         src.RetryPolicy,
+		RetryBaseSeconds:    src.RetryBaseSeconds,
+		RetryMaxSeconds:     src.RetryMaxSeconds,
+		RotationMode:        src.RotationMode,
+		RotationWeights:     src.RotationWeights,
+		CreateTime:          now,
+		UpdateTime:          now,
+	}
+	if !hasCronConfig(task.TriggerType, task.CronPatternType) {
+		task.CronConfig = nil
+	}
+	if err := t.db.Create(task).Error; err != nil {
+		return nil, err
+	}
+	return t.taskToVO(task), nil
+}
+
+// CreateTemplate 创建任务模板：以已有任务的配置或直接传入的任务配置保存
+func (t *TaskServiceImpl) CreateTemplate(req *request.CreateTaskTemplateRequest, adminID uint) (*vo.TaskTemplateVo, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("模板名称不能为空")
+	}
+	if err := t.checkTemplateName(name, adminID, 0); err != nil {
+		return nil, err
+	}
+
+	var cfg *request.CreateTaskRequest
+	var sourceTaskID uint64
+	switch {
+	case req.TaskID > 0:
+		src := &model.Task{}
+		if err := t.db.Where("id = ? AND admin_id = ? AND is_delete = 0", req.TaskID, adminID).First(src).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("任务不存在或无权限操作")
+			}
+			return nil, err
+		}
+		cfg = taskToCreateRequest(src)
+		sourceTaskID = src.ID
+	case req.Task != nil:
+		cfg = req.Task
+	default:
+		return nil, errors.New("请指定来源任务或任务配置")
+	}
+	if err := t.validateTemplateConfig(cfg); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, errors.New("模板配置序列化失败")
+	}
+
+	now := time.Now()
+	tpl := &model.TaskTemplate{
+		AdminID:      adminID,
+		Name:         name,
+		Description:  req.Description,
+		Config:       model.JSON(raw),
+		SourceTaskID: sourceTaskID,
+		CreateTime:   now,
+		UpdateTime:   now,
+	}
+	if err := t.db.Create(tpl).Error; err != nil {
+		return nil, err
+	}
+	return templateToVO(tpl), nil
+}
+
+// UpdateTemplate 更新任务模板
+func (t *TaskServiceImpl) UpdateTemplate(req *request.UpdateTaskTemplateRequest, adminID uint) (*vo.TaskTemplateVo, error) {
+	tpl, err := t.findTemplate(req.ID, adminID)
+	if err != nil {
+		return nil, err
+	}
+	updates := map[string]interface{}{}
+	if name := strings.TrimSpace(req.Name); name != "" && name != tpl.Name {
+		if err := t.checkTemplateName(name, adminID, tpl.ID); err != nil {
+			return nil, err
+		}
+		updates["name"] = name
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.Task != nil {
+		if err := t.validateTemplateConfig(req.Task); err != nil {
+			return nil, err
+		}
+		raw, err := json.Marshal(req.Task)
+		if err != nil {
+			return nil, errors.New("模板配置序列化失败")
+		}
+		updates["config"] = model.JSON(raw)
+	}
+	if len(updates) > 0 {
+		updates["update_time"] = time.Now()
+		if err := t.db.Model(tpl).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+		if err := t.db.Where("id = ?", tpl.ID).First(tpl).Error; err != nil {
+			return nil, err
+		}
+	}
+	return templateToVO(tpl), nil
+}
+
+// DeleteTemplate 删除任务模板（软删除），已由模板创建的任务不受影响
+func (t *TaskServiceImpl) DeleteTemplate(req *request.DeleteTaskTemplateRequest, adminID uint) error {
+	tpl, err := t.findTemplate(req.ID, adminID)
+	if err != nil {
+		return err
+	}
+	return t.db.Model(tpl).Updates(map[string]interface{}{
+		"is_delete":   1,
+		"update_time": time.Now(),
+	}).Error
+}
+
+// GetTemplate 获取任务模板详情
+func (t *TaskServiceImpl) GetTemplate(id uint64, adminID uint) (*vo.TaskTemplateVo, error) {
+	tpl, err := t.findTemplate(id, adminID)
+	if err != nil {
+		return nil, err
+	}
+	return templateToVO(tpl), nil
+}
+
+// ListTemplates 分页查询任务模板
+func (t *TaskServiceImpl) ListTemplates(req *request.TaskTemplateListRequest, adminID uint) (*vo.PageResultVo[vo.TaskTemplateVo], error) {
+	normalizePage(&req.PageRequest)
+	query := t.db.Model(&model.TaskTemplate{}).Where("admin_id = ? AND is_delete = 0", adminID)
+	if name := strings.TrimSpace(req.Name); name != "" {
+		query = query.Where("name LIKE ?", "%"+name+"%")
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	var templates []model.TaskTemplate
+	if err := query.Order("id DESC").Offset(req.GetOffset()).Limit(req.Limit).Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	list := make([]vo.TaskTemplateVo, len(templates))
+	for i := range templates {
+		list[i] = *templateToVO(&templates[i])
+	}
+	return &vo.PageResultVo[vo.TaskTemplateVo]{Total: total, List: list}, nil
+}
+
+// InstantiateTemplate 按模板创建新的待提交任务，请求中的群组、消息、调度与到期时间覆盖模板配置；按创建任务的规则校验
+func (t *TaskServiceImpl) InstantiateTemplate(req *request.InstantiateTaskTemplateRequest, adminID uint) (*vo.TaskVo, error) {
+	tpl, err := t.findTemplate(req.ID, adminID)
+	if err != nil {
+		return nil, err
+	}
+	var cfg request.CreateTaskRequest
+	if err := json.Unmarshal(tpl.Config, &cfg); err != nil {
+		return nil, errors.New("模板配置格式错误: " + err.Error())
+	}
+
+	if name := strings.TrimSpace(req.TaskName); name != "" {
+		cfg.TaskName = name
+	}
+	if cfg.TaskName == "" {
+		cfg.TaskName = tpl.Name
+	}
+	cfg.TaskName = truncateRunes(cfg.TaskName, taskNameMaxLen)
+	if len(req.GroupIDs) > 0 {
+		cfg.GroupIDs = req.GroupIDs
+	}
+	if len(req.MessageIDs) > 0 {
+		cfg.MessageIDs = req.MessageIDs
+		// 权重按消息ID配置，更换消息后不再适用
+		cfg.RotationWeights = nil
+	}
+	if req.Timezone != "" {
+		cfg.Timezone = req.Timezone
+	}
+	if req.TriggerType != nil && *req.TriggerType != cfg.TriggerType {
+		cfg.TriggerType = *req.TriggerType
+		cfg.ScheduleTime = nil
+		cfg.CronExpression = ""
+		cfg.CronPatternType = nil
+		cfg.CronConfig = nil
+	}
+	if req.ScheduleTime != nil {
+		cfg.ScheduleTime = req.ScheduleTime
+	}
+	if req.ExpireTime != nil {
+		cfg.ExpireTime = req.ExpireTime
+	}
+	if req.CronExpression != "" || req.CronPatternType != nil {
+		cfg.CronExpression = req.CronExpression
+		cfg.CronPatternType = req.CronPatternType
+		cfg.CronConfig = req.CronConfig
+	}
+	if len(cfg.GroupIDs) == 0 {
+		return nil, errors.New("请指定群组")
+	}
+	if len(cfg.MessageIDs) == 0 {
+		return nil, errors.New("请指定消息")
+	}
+	return t.CreateTask(&cfg, adminID)
+}
+
+func (t *TaskServiceImpl) findTemplate(id uint64, adminID uint) (*model.TaskTemplate, error) {
+	tpl := &model.TaskTemplate{}
+	if err := t.db.Where("id = ? AND admin_id = ? AND is_delete = 0", id, adminID).First(tpl).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("模板不存在或无权限操作")
+		}
+		return nil, err
+	}
+	return tpl, nil
+}
+
+// checkTemplateName 同一管理员下模板名称唯一
+func (t *TaskServiceImpl) checkTemplateName(name string, adminID uint, excludeID uint64) error {
+	var count int64
+	if err := t.db.Model(&model.TaskTemplate{}).
+		Where("admin_id = ? AND name = ? AND id <> ? AND is_delete = 0", adminID, name, excludeID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("模板名称已存在")
+	}
+	return nil
+}
+
+// validateTemplateConfig 校验模板配置中与时间无关的部分（执行/到期时间在按模板创建任务时校验），
+// 并将不带时区的时间按任务时区固定下来，保存后不再依赖输入格式
+func (t *TaskServiceImpl) validateTemplateConfig(cfg *request.CreateTaskRequest) error {
+	if cfg.TriggerType != model.TriggerTypeSchedule && cfg.TriggerType != model.TriggerTypeCron {
+		return errors.New("触发类型错误")
+	}
+	timezone, err := t.resolveTimezone(cfg.Timezone)
+	if err != nil {
+		return err
+	}
+	cfg.Timezone = timezone
+	if st := cfg.GetScheduleTime(); st != nil {
+		cfg.ScheduleTime = &request.FlexibleTime{Time: *st}
+	}
+	if et := cfg.GetExpireTime(); et != nil {
+		cfg.ExpireTime = &request.FlexibleTime{Time: *et}
+	}
+	if cfg.TriggerType == model.TriggerTypeCron {
+		expr, err := t.resolveCronExpression(cfg.CronExpression, cfg.CronPatternType, cfg.CronConfig)
+		if err != nil {
+			return err
+		}
+		if expr == "" {
+			return errors.New("周期执行类型必须指定Cron表达式")
+		}
+		if valid, errMsg := t.cronUtils.ValidateCronExpression(expr); !valid {
+			return errors.New("Cron表达式格式错误: " + errMsg)
+		}
+	}
+	if _, _, err := resolveMisfirePolicy(cfg.MisfirePolicy, cfg.MisfireCatchUpLimit, model.MisfirePolicyFireOnce, 5); err != nil {
+		return err
+	}
+	if _, _, err := resolveRotation(cfg.RotationMode, cfg.RotationWeights, cfg.MessageIDs, model.RotationModeAll, nil); err != nil {
+		return err
+	}
+	if _, _, _, err := resolveRetryPolicy(cfg.RetryPolicy, cfg.RetryBaseSeconds, cfg.RetryMaxSeconds, model.RetryPolicyExponential, model.DefaultRetryBaseSeconds, model.DefaultRetryMaxSeconds); err != nil {
+		return err
+	}
+	return nil
+}
+
+// taskToCreateRequest 将任务配置转换为创建任务请求（模板快照）
+func taskToCreateRequest(task *model.Task) *request.CreateTaskRequest {
+	loc := task.Location()
+	cfg := &request.CreateTaskRequest{
+		TaskName:            task.TaskName,
+		Description:         task.Description,
+		TriggerType:         task.TriggerType,
+		CronExpression:      task.CronExpression,
+		CronPatternType:     task.CronPatternType,
+		MaxRetryCount:       task.MaxRetryCount,
+		MisfirePolicy:       task.MisfirePolicy,
+		MisfireCatchUpLimit: task.MisfireCatchUpLimit,
+		Priority:            task.Priority,
+		RetryPolicy:         task.RetryPolicy,
+		RetryBaseSeconds:    task.RetryBaseSeconds,
+		RetryMaxSeconds:     task.RetryMaxSeconds,
+		RotationMode:        task.RotationMode,
+		Timezone:            task.Timezone,
+	}
+	_ = json.Unmarshal(task.GroupIDs, &cfg.GroupIDs)
+	_ = json.Unmarshal(task.MessageIDs, &cfg.MessageIDs)
+	if len(task.CronConfig) > 0 && hasCronConfig(task.TriggerType, task.CronPatternType) {
+		_ = json.Unmarshal(task.CronConfig, &cfg.CronConfig)
+	}
+	if len(task.RotationWeights) > 0 {
+		_ = json.Unmarshal(task.RotationWeights, &cfg.RotationWeights)
+	}
+	// 按任务时区的本地时间保存，与创建任务时的输入一致
+	if task.ScheduleTime != nil {
+		cfg.ScheduleTime = &request.FlexibleTime{Time: task.ScheduleTime.In(loc)}
+	}
+	if task.ExpireTime != nil {
+		cfg.ExpireTime = &request.FlexibleTime{Time: task.ExpireTime.In(loc)}
+	}
+	return cfg
+}
+
+func templateToVO(tpl *model.TaskTemplate) *vo.TaskTemplateVo {
+	v := &vo.TaskTemplateVo{
+		ID:           tpl.ID,
+		Name:         tpl.Name,
+		Description:  tpl.Description,
+		SourceTaskID: tpl.SourceTaskID,
+		Config:       json.RawMessage(tpl.Config),
+		CreateTime:   vo.CustomTime{Time: tpl.CreateTime},
+		UpdateTime:   vo.CustomTime{Time: tpl.UpdateTime},
+	}
+	var cfg request.CreateTaskRequest
+	if err := json.Unmarshal(tpl.Config, &cfg); err == nil {
+		v.TriggerType = cfg.TriggerType
+		v.TriggerTypeText = (&vo.TaskVo{TriggerType: cfg.TriggerType}).GetTriggerTypeText()
+		v.CronExpression = cfg.CronExpression
+		v.GroupCount = len(cfg.GroupIDs)
+		v.MessageCount = len(cfg.MessageIDs)
+	}
+	return v
+}
+
+// truncateRunes 按字符截断
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
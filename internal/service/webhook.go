@@ -0,0 +1,323 @@
+package service
+
+import (
+	"app/internal/job"
+	"app/internal/model"
+	"app/internal/request"
+	"app/internal/vo"
+	"app/tools/random"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// WebhookService webhook订阅服务接口
+type WebhookService interface {
+	CreateWebhook(req *request.CreateWebhookRequest, adminID uint) (*vo.WebhookVo, error)
+	UpdateWebhook(req *request.UpdateWebhookRequest, adminID uint) (*vo.WebhookVo, error)
+	DeleteWebhook(req *request.DeleteWebhookRequest, adminID uint) error
+	ListWebhooks(req *request.WebhookListRequest, adminID uint) (*vo.PageResultVo[vo.WebhookVo], error)
+	ListDeliveries(req *request.WebhookDeliveryListRequest, adminID uint) (*vo.PageResultVo[vo.WebhookDeliveryVo], error)
+	ReplayDelivery(req *request.ReplayWebhookDeliveryRequest, adminID uint) (*vo.WebhookDeliveryVo, error)
+}
+
+type WebhookServiceImpl struct {
+	db         *gorm.DB
+	jobService *job.JobService
+}
+
+// NewWebhookService 创建WebhookService实例
+func NewWebhookService(db *gorm.DB, jobService *job.JobService) WebhookService {
+	return &WebhookServiceImpl{
+		db:         db,
+		jobService: jobService,
+	}
+}
+
+// CreateWebhook 创建webhook，未指定密钥时自动生成；完整密钥只在此时返回
+func (w *WebhookServiceImpl) CreateWebhook(req *request.CreateWebhookRequest, adminID uint) (*vo.WebhookVo, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	events, err := resolveWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+	secret := strings.TrimSpace(req.Secret)
+	if secret == "" {
+		if secret, err = random.GenerateSecureString(32); err != nil {
+			return nil, err
+		}
+	} else if len(secret) < 16 || len(secret) > 128 {
+		return nil, errors.New("签名密钥长度需在16-128之间")
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	now := time.Now()
+	hook := &model.Webhook{
+		AdminID:    adminID,
+		Name:       strings.TrimSpace(req.Name),
+		URL:        strings.TrimSpace(req.URL),
+		Secret:     secret,
+		Events:     events,
+		Enabled:    enabled,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if err := w.db.Create(hook).Error; err != nil {
+		return nil, err
+	}
+	v := webhookToVO(hook)
+	v.Secret = secret
+	return v, nil
+}
+
+// UpdateWebhook 更新webhook
+func (w *WebhookServiceImpl) UpdateWebhook(req *request.UpdateWebhookRequest, adminID uint) (*vo.WebhookVo, error) {
+	hook, err := w.findWebhook(req.ID, adminID)
+	if err != nil {
+		return nil, err
+	}
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("名称不能为空")
+		}
+		updates["name"] = name
+	}
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		updates["url"] = strings.TrimSpace(*req.URL)
+	}
+	if req.Events != nil {
+		events, err := resolveWebhookEvents(*req.Events)
+		if err != nil {
+			return nil, err
+		}
+		updates["events"] = events
+	}
+	if req.Enabled != nil {
+		updates["enabled"] = *req.Enabled
+	}
+	var secret string
+	if req.RotateSecret {
+		if secret, err = random.GenerateSecureString(32); err != nil {
+			return nil, err
+		}
+		updates["secret"] = secret
+	}
+	if len(updates) > 0 {
+		updates["update_time"] = time.Now()
+		if err := w.db.Model(hook).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+		if err := w.db.Where("id = ?", hook.ID).First(hook).Error; err != nil {
+			return nil, err
+		}
+	}
+	v := webhookToVO(hook)
+	v.Secret = secret
+	return v, nil
+}
+
+// DeleteWebhook 删除webhook（软删除），未完成的投递将不再重试
+func (w *WebhookServiceImpl) DeleteWebhook(req *request.DeleteWebhookRequest, adminID uint) error {
+	hook, err := w.findWebhook(req.ID, adminID)
+	if err != nil {
+		return err
+	}
+	return w.db.Model(hook).Updates(map[string]interface{}{
+		"is_delete":   1,
+		"update_time": time.Now(),
+	}).Error
+}
+
+// ListWebhooks 分页查询webhook
+func (w *WebhookServiceImpl) ListWebhooks(req *request.WebhookListRequest, adminID uint) (*vo.PageResultVo[vo.WebhookVo], error) {
+	normalizePage(&req.PageRequest)
+	query := w.db.Model(&model.Webhook{}).Where("admin_id = ? AND is_delete = 0", adminID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	var hooks []model.Webhook
+	if err := query.Order("id DESC").Offset(req.GetOffset()).Limit(req.Limit).Find(&hooks).Error; err != nil {
+		return nil, err
+	}
+	list := make([]vo.WebhookVo, len(hooks))
+	for i := range hooks {
+		list[i] = *webhookToVO(&hooks[i])
+	}
+	return &vo.PageResultVo[vo.WebhookVo]{Total: total, List: list}, nil
+}
+
+// ListDeliveries 分页查询投递记录
+func (w *WebhookServiceImpl) ListDeliveries(req *request.WebhookDeliveryListRequest, adminID uint) (*vo.PageResultVo[vo.WebhookDeliveryVo], error) {
+	normalizePage(&req.PageRequest)
+	query := w.db.Model(&model.WebhookDelivery{}).Where("admin_id = ?", adminID)
+	if req.WebhookID != nil {
+		query = query.Where("webhook_id = ?", *req.WebhookID)
+	}
+	if req.TaskID != nil {
+		query = query.Where("task_id = ?", *req.TaskID)
+	}
+	if req.Event != "" {
+		query = query.Where("event = ?", req.Event)
+	}
+	if req.Status != nil {
+		query = query.Where("status = ?", *req.Status)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	var deliveries []model.WebhookDelivery
+	if err := query.Order("id DESC").Offset(req.GetOffset()).Limit(req.Limit).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	list := make([]vo.WebhookDeliveryVo, len(deliveries))
+	for i := range deliveries {
+		list[i] = webhookDeliveryToVO(&deliveries[i])
+	}
+	return &vo.PageResultVo[vo.WebhookDeliveryVo]{Total: total, List: list}, nil
+}
+
+// ReplayDelivery 以原事件内容重新投递（新投递记录，事件ID不变），使用webhook当前的地址与密钥
+func (w *WebhookServiceImpl) ReplayDelivery(req *request.ReplayWebhookDeliveryRequest, adminID uint) (*vo.WebhookDeliveryVo, error) {
+	var orig model.WebhookDelivery
+	if err := w.db.Where("id = ? AND admin_id = ?", req.ID, adminID).First(&orig).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("投递记录不存在或无权限操作")
+		}
+		return nil, err
+	}
+	if _, err := w.findWebhook(orig.WebhookID, adminID); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	d := &model.WebhookDelivery{
+		WebhookID:  orig.WebhookID,
+		AdminID:    orig.AdminID,
+		TaskID:     orig.TaskID,
+		Event:      orig.Event,
+		EventID:    orig.EventID,
+		Payload:    orig.Payload,
+		Status:     model.WebhookDeliveryPending,
+		ReplayOf:   orig.ID,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if err := w.jobService.EnqueueWebhookDelivery(d); err != nil {
+		return nil, fmt.Errorf("重放入队失败: %v", err)
+	}
+	v := webhookDeliveryToVO(d)
+	return &v, nil
+}
+
+func (w *WebhookServiceImpl) findWebhook(id uint64, adminID uint) (*model.Webhook, error) {
+	hook := &model.Webhook{}
+	if err := w.db.Where("id = ? AND admin_id = ? AND is_delete = 0", id, adminID).First(hook).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("webhook不存在或无权限操作")
+		}
+		return nil, err
+	}
+	return hook, nil
+}
+
+// validateWebhookURL 仅允许 http/https 绝对地址
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.New("回调地址需为http或https绝对地址")
+	}
+	return nil
+}
+
+// resolveWebhookEvents 校验并去重事件列表；为空表示订阅全部事件
+func resolveWebhookEvents(events []string) (datatypes.JSON, error) {
+	seen := make([]string, 0, len(events))
+	for _, e := range events {
+		e = strings.TrimSpace(e)
+		if !slices.Contains(model.WebhookEvents, e) {
+			return nil, fmt.Errorf("不支持的事件: %s", e)
+		}
+		if !slices.Contains(seen, e) {
+			seen = append(seen, e)
+		}
+	}
+	if len(seen) == 0 {
+		return nil, nil
+	}
+	raw, err := json.Marshal(seen)
+	if err != nil {
+		return nil, err
+	}
+	return datatypes.JSON(raw), nil
+}
+
+func normalizePage(p *request.PageRequest) {
+	if p.Page <= 0 {
+		p.Page = 1
+	}
+	if p.Limit <= 0 || p.Limit > 100 {
+		p.Limit = 10
+	}
+}
+
+func webhookToVO(hook *model.Webhook) *vo.WebhookVo {
+	events := []string{}
+	if len(hook.Events) > 0 {
+		_ = json.Unmarshal(hook.Events, &events)
+	}
+	hint := hook.Secret
+	if len(hint) > 4 {
+		hint = "****" + hint[len(hint)-4:]
+	}
+	return &vo.WebhookVo{
+		ID:         hook.ID,
+		Name:       hook.Name,
+		URL:        hook.URL,
+		Events:     events,
+		Enabled:    hook.Enabled,
+		SecretHint: hint,
+		CreateTime: vo.CustomTime{Time: hook.CreateTime},
+		UpdateTime: vo.CustomTime{Time: hook.UpdateTime},
+	}
+}
+
+func webhookDeliveryToVO(d *model.WebhookDelivery) vo.WebhookDeliveryVo {
+	v := vo.WebhookDeliveryVo{
+		ID:           d.ID,
+		WebhookID:    d.WebhookID,
+		TaskID:       d.TaskID,
+		Event:        d.Event,
+		EventID:      d.EventID,
+		Payload:      d.Payload,
+		Status:       d.Status,
+		StatusText:   vo.GetWebhookDeliveryStatusText(d.Status),
+		Attempts:     d.Attempts,
+		ResponseCode: d.ResponseCode,
+		ResponseBody: d.ResponseBody,
+		ErrorMessage: d.ErrorMessage,
+		DurationMs:   d.DurationMs,
+		ReplayOf:     d.ReplayOf,
+		CreateTime:   vo.CustomTime{Time: d.CreateTime},
+	}
+	if d.DeliveredAt != nil {
+		v.DeliveredAt = &vo.CustomTime{Time: *d.DeliveredAt}
+	}
+	return v
+}
@@ -0,0 +1,45 @@
+package vo
+
+import "app/internal/model"
+
+// AlertConfigVo 告警配置视图对象
+type AlertConfigVo struct {
+	BotConfigID         uint        `json:"botConfigId"`
+	ChatID              int64       `json:"chatId"`
+	Enabled             bool        `json:"enabled"`
+	OnFailure           bool        `json:"onFailure"`
+	ConsecutiveFailures int         `json:"consecutiveFailures"`
+	OnExpire            bool        `json:"onExpire"`
+	DailyDigest         bool        `json:"dailyDigest"`
+	DigestHour          int         `json:"digestHour"`
+	UpdateTime          *CustomTime `json:"updateTime"`
+}
+
+// AlertVo 告警记录视图对象
+type AlertVo struct {
+	ID           uint64      `json:"id"`
+	TaskID       uint64      `json:"taskId"`
+	Kind         string      `json:"kind"`
+	Content      string      `json:"content"`
+	Status       int         `json:"status"`
+	StatusText   string      `json:"statusText"`
+	ErrorMessage string      `json:"errorMessage"`
+	SentAt       *CustomTime `json:"sentAt"`
+	CreateTime   CustomTime  `json:"createTime"`
+}
+
+// GetAlertStatusText 获取告警状态文本
+func GetAlertStatusText(status int) string {
+	switch status {
+	case model.AlertStatusPending:
+		return "待发送"
+	case model.AlertStatusSent:
+		return "已发送"
+	case model.AlertStatusFailed:
+		return "发送失败"
+	case model.AlertStatusSuppressed:
+		return "超过频率上限"
+	default:
+		return "未知状态"
+	}
+}
@@ -0,0 +1,91 @@
+package vo
+
+// JobQueueVo 队列概况
+type JobQueueVo struct {
+	Queue          string `json:"queue"`
+	Paused         bool   `json:"paused"`
+	Size           int    `json:"size"`
+	Pending        int    `json:"pending"`
+	Active         int    `json:"active"`
+	Scheduled      int    `json:"scheduled"`
+	Retry          int    `json:"retry"`
+	Archived       int    `json:"archived"`
+	Completed      int    `json:"completed"`
+	Processed      int    `json:"processed"`      // 今日处理数
+	Failed         int    `json:"failed"`         // 今日失败数
+	ProcessedTotal int    `json:"processedTotal"` // 累计处理数
+	FailedTotal    int    `json:"failedTotal"`    // 累计失败数
+	LatencyMs      int64  `json:"latencyMs"`      // 最早待执行任务的等待时长
+	MemoryUsage    int64  `json:"memoryUsage"`    // 占用内存（字节）
+}
+
+// JobTaskRefVo 队列任务关联的DB任务
+type JobTaskRefVo struct {
+	ID         uint64 `json:"id"`
+	TaskName   string `json:"taskName"`
+	AdminID    uint   `json:"adminId"`
+	Status     int    `json:"status"`
+	StatusText string `json:"statusText"`
+	Deleted    bool   `json:"deleted"`
+}
+
+// JobPayloadVo 解析后的任务 payload
+type JobPayloadVo struct {
+	Type       string `json:"type"`
+	Version    int    `json:"version"`
+	TaskID     uint64 `json:"taskId"`
+	RunID      string `json:"runId"`
+	ExpireTime string `json:"expireTime"`
+}
+
+// JobSchedulerEntryVo 调度条目
+type JobSchedulerEntryVo struct {
+	ID       string        `json:"id"`
+	Spec     string        `json:"spec"`
+	TaskType string        `json:"taskType"`
+	Payload  *JobPayloadVo `json:"payload"`
+	Raw      string        `json:"raw,omitempty"` // 无法解析时的原始 payload
+	Task     *JobTaskRefVo `json:"task"`
+	Next     *CustomTime   `json:"next"`
+	Prev     *CustomTime   `json:"prev"`
+}
+
+// JobTaskVo 队列任务
+type JobTaskVo struct {
+	ID            string        `json:"id"`
+	Queue         string        `json:"queue"`
+	Type          string        `json:"type"`
+	State         string        `json:"state"`
+	Payload       *JobPayloadVo `json:"payload"`
+	Raw           string        `json:"raw,omitempty"`
+	Task          *JobTaskRefVo `json:"task"`
+	MaxRetry      int           `json:"maxRetry"`
+	Retried       int           `json:"retried"`
+	LastErr       string        `json:"lastErr"`
+	LastFailedAt  *CustomTime   `json:"lastFailedAt"`
+	NextProcessAt *CustomTime   `json:"nextProcessAt"`
+	CompletedAt   *CustomTime   `json:"completedAt"`
+}
+
+// JobWorkerVo 正在处理的任务
+type JobWorkerVo struct {
+	ID       string        `json:"id"`
+	Type     string        `json:"type"`
+	Queue    string        `json:"queue"`
+	Task     *JobTaskRefVo `json:"task"`
+	Started  *CustomTime   `json:"started"`
+	Deadline *CustomTime   `json:"deadline"`
+}
+
+// JobServerVo Worker 服务实例
+type JobServerVo struct {
+	ID             string         `json:"id"`
+	Host           string         `json:"host"`
+	PID            int            `json:"pid"`
+	Concurrency    int            `json:"concurrency"`
+	Queues         map[string]int `json:"queues"`
+	StrictPriority bool           `json:"strictPriority"`
+	Status         string         `json:"status"`
+	Started        *CustomTime    `json:"started"`
+	ActiveWorkers  []JobWorkerVo  `json:"activeWorkers"`
+}
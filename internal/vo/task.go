@@ -9,6 +9,12 @@ import (
 // CustomTime 自定义时间类型，用于统一JSON输出格式
 type CustomTime struct {
 	time.Time
+	loc *time.Location // 输出时区，为空时使用服务默认时区
+}
+
+// CustomTimeIn 按指定时区输出的时间（数据库以UTC存储，回显时换算）
+func CustomTimeIn(t time.Time, loc *time.Location) *CustomTime {
+	return &CustomTime{Time: t, loc: loc}
 }
 
 // MarshalJSON 自定义时间JSON序列化格式
@@ -16,10 +22,14 @@ func (ct *CustomTime) MarshalJSON() ([]byte, error) {
 	if ct.Time.IsZero() {
 		return []byte("null"), nil
 	}
+	loc := ct.loc
+	if loc == nil {
+		loc = time.Local
+	}
 	// 可以根据需要调整时间格式
 	// "2006-01-02 15:04:05" - 标准格式
 	// time.RFC3339 - ISO格式(当前使用)
-	formatted := ct.Time.Format("2006-01-02 15:04:05")
+	formatted := ct.Time.In(loc).Format("2006-01-02 15:04:05")
 	return json.Marshal(formatted)
 }
 
@@ -55,29 +65,42 @@ func (ct *CustomTime) UnmarshalJSON(data []byte) error {
 
 // TaskVo 任务视图对象
 type TaskVo struct {
-	ID              uint64                  `json:"id"`
-	TaskName        string                  `json:"taskName"`
-	Description     string                  `json:"description"`
-	Status          int                     `json:"status"`
-	StatusText      string                  `json:"statusText"`
-	AdminID         uint                    `json:"adminId"`
-	GroupIDs        []int64                 `json:"groupIds"`
-	MessageIDs      []uint64                `json:"messageIds"`
-	TriggerType     model.TriggerType       `json:"triggerType"`
-	TriggerTypeText string                  `json:"triggerTypeText"`
-    ScheduleTime    *CustomTime             `json:"scheduleTime"`
-    ExpireTime      *CustomTime             `json:"expireTime"`
-    CronExpression  string                  `json:"cronExpression"`
-	CronPatternType *model.CronPatternType  `json:"cronPatternType"`
-	CronConfig      map[string]interface{}  `json:"cronConfig"`
-	LastExecutedAt  *CustomTime             `json:"lastExecutedAt"`
-	NextExecuteAt   *CustomTime             `json:"nextExecuteAt"`
-	ExecuteCount    int                     `json:"executeCount"`
-	RetryCount      int                     `json:"retryCount"`
-	MaxRetryCount   int                     `json:"maxRetryCount"`
-	ErrorMessage    string                  `json:"errorMessage"`
-	CreateTime      CustomTime              `json:"createTime"`
-	UpdateTime      CustomTime              `json:"updateTime"`
+	ID                  uint64                 `json:"id"`
+	TaskName            string                 `json:"taskName"`
+	Description         string                 `json:"description"`
+	Status              int                    `json:"status"`
+	StatusText          string                 `json:"statusText"`
+	AdminID             uint                   `json:"adminId"`
+	GroupIDs            []int64                `json:"groupIds"`
+	MessageIDs          []uint64               `json:"messageIds"`
+	TriggerType         model.TriggerType      `json:"triggerType"`
+	TriggerTypeText     string                 `json:"triggerTypeText"`
+	ScheduleTime        *CustomTime            `json:"scheduleTime"`
+	ExpireTime          *CustomTime            `json:"expireTime"`
+	Timezone            string                 `json:"timezone"`
+	CronExpression      string                 `json:"cronExpression"`
+	CronDescription     string                 `json:"cronDescription,omitempty"`
+	CronDescriptionEn   string                 `json:"cronDescriptionEn,omitempty"`
+	CronPatternType     *model.CronPatternType `json:"cronPatternType"`
+	CronConfig          map[string]interface{} `json:"cronConfig"`
+	LastExecutedAt      *CustomTime            `json:"lastExecutedAt"`
+	NextExecuteAt       *CustomTime            `json:"nextExecuteAt"`
+	ExecuteCount        int                    `json:"executeCount"`
+	RetryCount          int                    `json:"retryCount"`
+	MaxRetryCount       int                    `json:"maxRetryCount"`
+	MisfirePolicy       string                 `json:"misfirePolicy"`
+	MisfireCatchUpLimit int                    `json:"misfireCatchUpLimit"`
+	Priority            string                 `json:"priority"`
+	RetryPolicy         string                 `json:"retryPolicy"`
+	RetryBaseSeconds    int                    `json:"retryBaseSeconds"`
+	RetryMaxSeconds     int                    `json:"retryMaxSeconds"`
+	RotationMode        string                 `json:"rotationMode"`
+	RotationWeights     map[uint64]int         `json:"rotationWeights,omitempty"`
+	// 下一次执行将发送的消息（仅详情返回；all 模式或尚未确定时为空）
+	NextMessageID *uint64    `json:"nextMessageId,omitempty"`
+	ErrorMessage  string     `json:"errorMessage"`
+	CreateTime    CustomTime `json:"createTime"`
+	UpdateTime    CustomTime `json:"updateTime"`
 }
 
 // TaskListVo 任务列表视图对象
@@ -93,6 +116,7 @@ type TaskStatsVo struct {
 	RunningCount   int64 `json:"runningCount"`
 	CompletedCount int64 `json:"completedCount"`
 	FailedCount    int64 `json:"failedCount"`
+	PausedCount    int64 `json:"pausedCount"`
 }
 
 // GetStatusText 获取状态文本
@@ -108,6 +132,8 @@ func (t *TaskVo) GetStatusText() string {
 		return "已完成"
 	case 3:
 		return "执行失败"
+	case 4:
+		return "已暂停"
 	default:
 		return "未知状态"
 	}
@@ -124,3 +150,146 @@ func (t *TaskVo) GetTriggerTypeText() string {
 		return "未知类型"
 	}
 }
+
+// CronPreviewVo Cron预览结果
+type CronPreviewVo struct {
+	CronExpression string       `json:"cronExpression"`
+	IsValid        bool         `json:"isValid"`
+	ErrorMessage   string       `json:"errorMessage,omitempty"`
+	Description    string       `json:"description"`
+	DescriptionEn  string       `json:"descriptionEn"`
+	Timezone       string       `json:"timezone"`
+	NextExecutions []CustomTime `json:"nextExecutions"`
+	Truncated      bool         `json:"truncated"` // 是否因到期时间被截断
+}
+
+// TaskTriggerVo 手动触发结果
+type TaskTriggerVo struct {
+	TaskID     uint64     `json:"taskId"`
+	JobID      string     `json:"jobId"`
+	Queue      string     `json:"queue"`
+	EnqueuedAt CustomTime `json:"enqueuedAt"`
+}
+
+// TaskDeadLetterResultVo 归档任务批量操作结果
+type TaskDeadLetterResultVo struct {
+	TaskID    uint64                 `json:"taskId"`
+	Requested int                    `json:"requested"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    []TaskDeadLetterFailVo `json:"failed"`
+	// 已执行成功但需要关注的归档任务（如重新入队后原归档任务删除失败）
+	Warnings []TaskDeadLetterFailVo `json:"warnings,omitempty"`
+}
+
+// TaskDeadLetterFailVo 单个归档任务的操作失败原因
+type TaskDeadLetterFailVo struct {
+	JobID string `json:"jobId"`
+	Error string `json:"error"`
+}
+
+// TaskExecutionVo 任务执行记录视图对象
+type TaskExecutionVo struct {
+	ID            uint64      `json:"id"`
+	TaskID        uint64      `json:"taskId"`
+	RunID         string      `json:"runId"`
+	Source        string      `json:"source"`
+	Attempt       int         `json:"attempt"`
+	Status        int         `json:"status"`
+	StatusText    string      `json:"statusText"`
+	TotalCount    int         `json:"totalCount"`
+	SuccessCount  int         `json:"successCount"`
+	FailedCount   int         `json:"failedCount"`
+	DeferredCount int         `json:"deferredCount"`
+	SkippedCount  int         `json:"skippedCount"`
+	ErrorMessage  string      `json:"errorMessage"`
+	StartedAt     CustomTime  `json:"startedAt"`
+	FinishedAt    *CustomTime `json:"finishedAt"`
+	DurationMs    int64       `json:"durationMs"`
+}
+
+// TaskDeliveryVo 单个群组/消息的投递明细
+type TaskDeliveryVo struct {
+	ID           uint64     `json:"id"`
+	GroupID      int64      `json:"groupId"`
+	GroupName    string     `json:"groupName"`
+	MessageID    uint64     `json:"messageId"`
+	Status       int        `json:"status"`
+	StatusText   string     `json:"statusText"`
+	TgMessageID  int64      `json:"tgMessageId"`
+	TgMessageIDs []int64    `json:"tgMessageIds"`
+	LatencyMs    int64      `json:"latencyMs"`
+	ErrorMessage string     `json:"errorMessage"`
+	CreateTime   CustomTime `json:"createTime"`
+}
+
+// TaskExecutionDetailVo 执行记录详情（含投递明细）
+type TaskExecutionDetailVo struct {
+	TaskExecutionVo
+	Deliveries []TaskDeliveryVo `json:"deliveries"`
+}
+
+// GetExecutionStatusText 获取执行记录状态文本
+func GetExecutionStatusText(status int) string {
+	switch status {
+	case model.ExecutionStatusRunning:
+		return "执行中"
+	case model.ExecutionStatusSuccess:
+		return "成功"
+	case model.ExecutionStatusFailed:
+		return "失败"
+	case model.ExecutionStatusPartial:
+		return "部分失败"
+	case model.ExecutionStatusMissed:
+		return "错过执行"
+	default:
+		return "未知状态"
+	}
+}
+
+// GetDeliveryStatusText 获取投递明细状态文本
+func GetDeliveryStatusText(status int) string {
+	switch status {
+	case model.DeliveryStatusSuccess:
+		return "发送成功"
+	case model.DeliveryStatusFailed:
+		return "发送失败"
+	case model.DeliveryStatusDeferred:
+		return "已延后"
+	case model.DeliveryStatusSkipped:
+		return "静默期跳过"
+	default:
+		return "未知状态"
+	}
+}
+
+// TaskTemplateVo 任务模板视图对象
+type TaskTemplateVo struct {
+	ID              uint64            `json:"id"`
+	Name            string            `json:"name"`
+	Description     string            `json:"description"`
+	SourceTaskID    uint64            `json:"sourceTaskId"`
+	TriggerType     model.TriggerType `json:"triggerType"`
+	TriggerTypeText string            `json:"triggerTypeText"`
+	CronExpression  string            `json:"cronExpression"`
+	GroupCount      int               `json:"groupCount"`
+	MessageCount    int               `json:"messageCount"`
+	Config          json.RawMessage   `json:"config"` // 与创建任务请求结构一致
+	CreateTime      CustomTime        `json:"createTime"`
+	UpdateTime      CustomTime        `json:"updateTime"`
+}
+
+// TaskBatchResultVo 批量操作任务结果
+type TaskBatchResultVo struct {
+	Requested int               `json:"requested"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Items     []TaskBatchItemVo `json:"items"`
+}
+
+// TaskBatchItemVo 单个任务的操作结果
+type TaskBatchItemVo struct {
+	ID      uint64  `json:"id"`
+	Success bool    `json:"success"`
+	Error   string  `json:"error,omitempty"`
+	Task    *TaskVo `json:"task,omitempty"`
+}
@@ -0,0 +1,50 @@
+package vo
+
+import "app/internal/model"
+
+// WebhookVo webhook视图对象
+type WebhookVo struct {
+	ID         uint64     `json:"id"`
+	Name       string     `json:"name"`
+	URL        string     `json:"url"`
+	Events     []string   `json:"events"`
+	Enabled    bool       `json:"enabled"`
+	Secret     string     `json:"secret,omitempty"` // 仅在创建或重新生成时返回完整密钥
+	SecretHint string     `json:"secretHint"`       // 密钥末4位
+	CreateTime CustomTime `json:"createTime"`
+	UpdateTime CustomTime `json:"updateTime"`
+}
+
+// WebhookDeliveryVo webhook投递记录视图对象
+type WebhookDeliveryVo struct {
+	ID           uint64      `json:"id"`
+	WebhookID    uint64      `json:"webhookId"`
+	TaskID       uint64      `json:"taskId"`
+	Event        string      `json:"event"`
+	EventID      string      `json:"eventId"`
+	Payload      string      `json:"payload"`
+	Status       int         `json:"status"`
+	StatusText   string      `json:"statusText"`
+	Attempts     int         `json:"attempts"`
+	ResponseCode int         `json:"responseCode"`
+	ResponseBody string      `json:"responseBody"`
+	ErrorMessage string      `json:"errorMessage"`
+	DurationMs   int64       `json:"durationMs"`
+	ReplayOf     uint64      `json:"replayOf"`
+	DeliveredAt  *CustomTime `json:"deliveredAt"`
+	CreateTime   CustomTime  `json:"createTime"`
+}
+
+// GetWebhookDeliveryStatusText 获取投递状态文本
+func GetWebhookDeliveryStatusText(status int) string {
+	switch status {
+	case model.WebhookDeliveryPending:
+		return "待投递"
+	case model.WebhookDeliverySuccess:
+		return "投递成功"
+	case model.WebhookDeliveryFailed:
+		return "投递失败"
+	default:
+		return "未知状态"
+	}
+}
@@ -27,7 +27,7 @@ func main() {
 	flag.StringVar(&config.InitDb, "initDb", "true", "-initDb=true, -initDb=false")
 	flag.Parse()
 
-	// 设置时区
+	// 设置服务默认时区：日志与未指定时区的任务/时间回显使用；任务可通过 timezone 字段单独指定
 	time.Local, _ = time.LoadLocation("Asia/Shanghai")
 
 	// 初始化基础配置
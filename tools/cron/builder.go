@@ -0,0 +1,244 @@
+package cron
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 表单模式类型，与 model.CronPatternType 取值一致
+const (
+	PatternMinute  = "minute"
+	PatternHour    = "hour"
+	PatternDaily   = "daily"
+	PatternWeekly  = "weekly"
+	PatternMonthly = "monthly"
+	PatternCustom  = "custom"
+)
+
+// Config 前端 Cron 表单配置
+//
+//	minute : {"interval": 5}                                  => */5 * * * *
+//	hour   : {"interval": 2, "minute": 30}                    => 30 */2 * * *
+//	daily  : {"time": "09:30"}                                => 30 9 * * *
+//	weekly : {"weekdays": [1, 3], "time": "09:30"}            => 30 9 * * 1,3
+//	monthly: {"days": [1, 15], "time": "09:30"}               => 30 9 1,15 * *
+//
+// time 也可拆分为 hour/minute 传入；weekdays 取值 0-6（0 为周日，7 兼容为周日）
+type Config struct {
+	Interval int    `json:"interval,omitempty"`
+	Hour     int    `json:"hour,omitempty"`
+	Minute   int    `json:"minute,omitempty"`
+	Time     string `json:"time,omitempty"`
+	Weekdays []int  `json:"weekdays,omitempty"`
+	Days     []int  `json:"days,omitempty"`
+}
+
+// clock 解析执行时刻，time 优先于 hour/minute
+func (c *Config) clock() (hour, minute int, err error) {
+	hour, minute = c.Hour, c.Minute
+	if c.Time != "" {
+		parts := strings.Split(strings.TrimSpace(c.Time), ":")
+		if len(parts) < 2 {
+			return 0, 0, fmt.Errorf("时间格式错误: %s", c.Time)
+		}
+		if hour, err = strconv.Atoi(parts[0]); err != nil {
+			return 0, 0, fmt.Errorf("时间格式错误: %s", c.Time)
+		}
+		if minute, err = strconv.Atoi(parts[1]); err != nil {
+			return 0, 0, fmt.Errorf("时间格式错误: %s", c.Time)
+		}
+	}
+	if hour < 0 || hour > 23 {
+		return 0, 0, fmt.Errorf("小时取值%d超出范围[0,23]", hour)
+	}
+	if minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("分钟取值%d超出范围[0,59]", minute)
+	}
+	return hour, minute, nil
+}
+
+// BuildExpression 根据模式类型与表单配置生成5位 Cron 表达式；custom 模式无法生成
+func BuildExpression(pattern string, c *Config) (string, error) {
+	if c == nil {
+		return "", fmt.Errorf("Cron配置不能为空")
+	}
+	switch pattern {
+	case PatternMinute:
+		if c.Interval < 1 || c.Interval > 59 {
+			return "", fmt.Errorf("分钟间隔取值%d超出范围[1,59]", c.Interval)
+		}
+		if c.Interval == 1 {
+			return "* * * * *", nil
+		}
+		return fmt.Sprintf("*/%d * * * *", c.Interval), nil
+	case PatternHour:
+		if c.Interval < 1 || c.Interval > 23 {
+			return "", fmt.Errorf("小时间隔取值%d超出范围[1,23]", c.Interval)
+		}
+		if c.Minute < 0 || c.Minute > 59 {
+			return "", fmt.Errorf("分钟取值%d超出范围[0,59]", c.Minute)
+		}
+		if c.Interval == 1 {
+			return fmt.Sprintf("%d * * * *", c.Minute), nil
+		}
+		return fmt.Sprintf("%d */%d * * *", c.Minute, c.Interval), nil
+	case PatternDaily:
+		hour, minute, err := c.clock()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d %d * * *", minute, hour), nil
+	case PatternWeekly:
+		hour, minute, err := c.clock()
+		if err != nil {
+			return "", err
+		}
+		weekdays := make([]int, 0, len(c.Weekdays))
+		for _, d := range c.Weekdays {
+			if d == 7 {
+				d = 0
+			}
+			if d < 0 || d > 6 {
+				return "", fmt.Errorf("星期取值%d超出范围[0,6]", d)
+			}
+			weekdays = append(weekdays, d)
+		}
+		if len(weekdays) == 0 {
+			return "", fmt.Errorf("每周执行必须选择星期")
+		}
+		return fmt.Sprintf("%d %d * * %s", minute, hour, joinInts(weekdays)), nil
+	case PatternMonthly:
+		hour, minute, err := c.clock()
+		if err != nil {
+			return "", err
+		}
+		if len(c.Days) == 0 {
+			return "", fmt.Errorf("每月执行必须选择日期")
+		}
+		for _, d := range c.Days {
+			if d < 1 || d > 31 {
+				return "", fmt.Errorf("日期取值%d超出范围[1,31]", d)
+			}
+		}
+		return fmt.Sprintf("%d %d %s * *", minute, hour, joinInts(c.Days)), nil
+	case PatternCustom:
+		return "", fmt.Errorf("自定义模式需直接指定Cron表达式")
+	}
+	return "", fmt.Errorf("不支持的Cron模式: %s", pattern)
+}
+
+// ParseExpression 将5位表达式反解为模式类型与表单配置（用于回显）；无法匹配固定模式时返回 custom
+func ParseExpression(expr string) (string, *Config) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return PatternCustom, nil
+	}
+	minute, hour, dom, month, dow := fields[0], fields[1], fields[2], fields[3], fields[4]
+	if month != "*" {
+		return PatternCustom, nil
+	}
+
+	// 每N分钟
+	if hour == "*" && dom == "*" && dow == "*" {
+		if minute == "*" {
+			return PatternMinute, &Config{Interval: 1}
+		}
+		if n, ok := parseEvery(minute, 59); ok {
+			return PatternMinute, &Config{Interval: n}
+		}
+	}
+
+	m, mOK := parseNumber(minute, 0, 59)
+	if !mOK {
+		return PatternCustom, nil
+	}
+
+	// 每N小时
+	if dom == "*" && dow == "*" {
+		if hour == "*" {
+			return PatternHour, &Config{Interval: 1, Minute: m}
+		}
+		if n, ok := parseEvery(hour, 23); ok {
+			return PatternHour, &Config{Interval: n, Minute: m}
+		}
+	}
+
+	h, hOK := parseNumber(hour, 0, 23)
+	if !hOK {
+		return PatternCustom, nil
+	}
+	clock := fmt.Sprintf("%02d:%02d", h, m)
+
+	switch {
+	case dom == "*" && dow == "*":
+		return PatternDaily, &Config{Hour: h, Minute: m, Time: clock}
+	case dom == "*":
+		weekdays, ok := parseNumberList(dow, 0, 7)
+		if !ok {
+			return PatternCustom, nil
+		}
+		for i, d := range weekdays {
+			if d == 7 {
+				weekdays[i] = 0
+			}
+		}
+		return PatternWeekly, &Config{Hour: h, Minute: m, Time: clock, Weekdays: weekdays}
+	case dow == "*":
+		days, ok := parseNumberList(dom, 1, 31)
+		if !ok {
+			return PatternCustom, nil
+		}
+		return PatternMonthly, &Config{Hour: h, Minute: m, Time: clock, Days: days}
+	}
+	return PatternCustom, nil
+}
+
+// parseEvery 解析 */n
+func parseEvery(s string, max int) (int, bool) {
+	if !strings.HasPrefix(s, "*/") {
+		return 0, false
+	}
+	return parseNumber(strings.TrimPrefix(s, "*/"), 1, max)
+}
+
+func parseNumber(s string, min, max int) (int, bool) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < min || v > max {
+		return 0, false
+	}
+	return v, true
+}
+
+// parseNumberList 解析逗号分隔的单值列表
+func parseNumberList(s string, min, max int) ([]int, bool) {
+	parts := strings.Split(s, ",")
+	values := make([]int, 0, len(parts))
+	for _, p := range parts {
+		v, ok := parseNumber(p, min, max)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, v)
+	}
+	return values, true
+}
+
+// joinInts 去重、排序后以逗号拼接
+func joinInts(values []int) string {
+	seen := make(map[int]bool, len(values))
+	uniq := make([]int, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			uniq = append(uniq, v)
+		}
+	}
+	sort.Ints(uniq)
+	strs := make([]string, 0, len(uniq))
+	for _, v := range uniq {
+		strs = append(strs, strconv.Itoa(v))
+	}
+	return strings.Join(strs, ",")
+}
@@ -0,0 +1,109 @@
+package cron
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		config  *Config
+		want    string
+		wantErr bool
+	}{
+		{"每分钟", PatternMinute, &Config{Interval: 1}, "* * * * *", false},
+		{"每5分钟", PatternMinute, &Config{Interval: 5}, "*/5 * * * *", false},
+		{"分钟间隔为0", PatternMinute, &Config{Interval: 0}, "", true},
+		{"分钟间隔越界", PatternMinute, &Config{Interval: 60}, "", true},
+		{"每小时", PatternHour, &Config{Interval: 1, Minute: 30}, "30 * * * *", false},
+		{"每2小时", PatternHour, &Config{Interval: 2, Minute: 30}, "30 */2 * * *", false},
+		{"小时间隔越界", PatternHour, &Config{Interval: 24}, "", true},
+		{"每天", PatternDaily, &Config{Time: "09:30"}, "30 9 * * *", false},
+		{"每天零点", PatternDaily, &Config{Time: "00:00"}, "0 0 * * *", false},
+		{"每天最后一分钟", PatternDaily, &Config{Hour: 23, Minute: 59}, "59 23 * * *", false},
+		{"time优先于hour/minute", PatternDaily, &Config{Hour: 8, Minute: 15, Time: "09:30"}, "30 9 * * *", false},
+		{"24点无效", PatternDaily, &Config{Time: "24:00"}, "", true},
+		{"时间格式错误", PatternDaily, &Config{Time: "9"}, "", true},
+		{"每周去重排序且7为周日", PatternWeekly, &Config{Weekdays: []int{7, 3, 1, 1}, Time: "09:30"}, "30 9 * * 0,1,3", false},
+		{"每周未选星期", PatternWeekly, &Config{Time: "09:30"}, "", true},
+		{"星期越界", PatternWeekly, &Config{Weekdays: []int{8}, Time: "09:30"}, "", true},
+		{"每月", PatternMonthly, &Config{Days: []int{31, 1}, Time: "00:00"}, "0 0 1,31 * *", false},
+		{"每月未选日期", PatternMonthly, &Config{Time: "00:00"}, "", true},
+		{"日期为0", PatternMonthly, &Config{Days: []int{0}, Time: "00:00"}, "", true},
+		{"自定义模式", PatternCustom, &Config{}, "", true},
+		{"未知模式", "yearly", &Config{}, "", true},
+		{"配置为空", PatternDaily, nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildExpression(tt.pattern, tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BuildExpression() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("BuildExpression() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		pattern string
+		config  *Config
+	}{
+		{"每分钟", "* * * * *", PatternMinute, &Config{Interval: 1}},
+		{"每5分钟", "*/5 * * * *", PatternMinute, &Config{Interval: 5}},
+		{"每小时", "15 * * * *", PatternHour, &Config{Interval: 1, Minute: 15}},
+		{"每2小时", "30 */2 * * *", PatternHour, &Config{Interval: 2, Minute: 30}},
+		{"每天", "30 9 * * *", PatternDaily, &Config{Hour: 9, Minute: 30, Time: "09:30"}},
+		{"每天零点", "0 0 * * *", PatternDaily, &Config{Time: "00:00"}},
+		{"每周且7为周日", "0 9 * * 1,7", PatternWeekly, &Config{Hour: 9, Time: "09:00", Weekdays: []int{1, 0}}},
+		{"每月", "0 0 1,15 * *", PatternMonthly, &Config{Time: "00:00", Days: []int{1, 15}}},
+		{"指定月份", "0 9 * 1 *", PatternCustom, nil},
+		{"小时范围", "0 9-17 * * *", PatternCustom, nil},
+		{"同时指定日期和星期", "0 9 1 * 1", PatternCustom, nil},
+		{"字段数错误", "0 9 * *", PatternCustom, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, config := ParseExpression(tt.expr)
+			if pattern != tt.pattern {
+				t.Errorf("ParseExpression(%q) pattern = %q, want %q", tt.expr, pattern, tt.pattern)
+			}
+			if !reflect.DeepEqual(config, tt.config) {
+				t.Errorf("ParseExpression(%q) config = %+v, want %+v", tt.expr, config, tt.config)
+			}
+		})
+	}
+}
+
+// 生成的表达式反解后再生成应保持不变
+func TestBuildParseRoundTrip(t *testing.T) {
+	exprs := []string{
+		"* * * * *",
+		"*/15 * * * *",
+		"0 * * * *",
+		"45 */6 * * *",
+		"0 0 * * *",
+		"59 23 * * *",
+		"30 9 * * 0,6",
+		"0 0 1,15,31 * *",
+	}
+	for _, expr := range exprs {
+		t.Run(expr, func(t *testing.T) {
+			pattern, config := ParseExpression(expr)
+			got, err := BuildExpression(pattern, config)
+			if err != nil {
+				t.Fatalf("BuildExpression(%q) error: %v", pattern, err)
+			}
+			if got != expr {
+				t.Errorf("round trip = %q, want %q", got, expr)
+			}
+		})
+	}
+}
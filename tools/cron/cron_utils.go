@@ -24,6 +24,7 @@ type CronResult struct {
 	IsValid         bool      `json:"isValid"`
 	ErrorMessage    string    `json:"errorMessage,omitempty"`
 	Description     string    `json:"description,omitempty"`
+	DescriptionEn   string    `json:"descriptionEn,omitempty"`
 }
 
 // ParseAndCalculateNext 解析 Cron 表达式并计算下次执行时间
@@ -53,7 +54,12 @@ func (c *CronUtils) ParseAndCalculateNext(cronExpr string, baseTime time.Time) *
 
 	result.NextExecuteTime = nextTime
 	result.IsValid = true
-	result.Description = c.getDescription(cronExpr)
+	if desc, err := Describe(cronExpr); err == nil {
+		result.Description = desc.Zh
+		result.DescriptionEn = desc.En
+	} else {
+		result.Description = "自定义执行周期"
+	}
 
 	return result
 }
@@ -81,35 +87,6 @@ func (c *CronUtils) ValidateCronExpression(cronExpr string) (bool, string) {
     return true, ""
 }
 
-// getDescription 获取 Cron 表达式的人性化描述
-func (c *CronUtils) getDescription(cronExpr string) string {
-	// 这里可以根据常见的 cron 表达式模式返回人性化描述
-	switch cronExpr {
-	case "0 * * * *":
-		return "每小时执行一次"
-	case "0 0 * * *":
-		return "每天凌晨执行一次"
-	case "0 0 * * 0":
-		return "每周日凌晨执行一次"
-	case "0 0 1 * *":
-		return "每月1日凌晨执行一次"
-	case "*/5 * * * *":
-		return "每5分钟执行一次"
-	case "*/10 * * * *":
-		return "每10分钟执行一次"
-	case "*/30 * * * *":
-		return "每30分钟执行一次"
-	case "0 */2 * * *":
-		return "每2小时执行一次"
-	case "0 */6 * * *":
-		return "每6小时执行一次"
-	case "0 */12 * * *":
-		return "每12小时执行一次"
-	default:
-		return "自定义执行周期"
-	}
-}
-
 // GetNextExecutions 获取多个下次执行时间（用于预览）
 func (c *CronUtils) GetNextExecutions(cronExpr string, baseTime time.Time, count int) ([]*time.Time, error) {
     if count <= 0 || count > 10 {
@@ -136,6 +113,26 @@ func (c *CronUtils) GetNextExecutions(cronExpr string, baseTime time.Time, count
 	return executions, nil
 }
 
+// SameSchedule 判断两个表达式是否描述同一调度（按各字段取值集合比较，忽略书写差异）
+func (c *CronUtils) SameSchedule(a, b string) (bool, error) {
+	sa, err := c.parser.Parse(a)
+	if err != nil {
+		return false, fmt.Errorf("无效的 Cron 表达式: %v", err)
+	}
+	sb, err := c.parser.Parse(b)
+	if err != nil {
+		return false, fmt.Errorf("无效的 Cron 表达式: %v", err)
+	}
+	specA, okA := sa.(*cron.SpecSchedule)
+	specB, okB := sb.(*cron.SpecSchedule)
+	if !okA || !okB {
+		// @every 等非字段型调度仅做字面比较
+		return a == b, nil
+	}
+	return specA.Minute == specB.Minute && specA.Hour == specB.Hour &&
+		specA.Dom == specB.Dom && specA.Month == specB.Month && specA.Dow == specB.Dow, nil
+}
+
 // PresetCronExpressions 预设的常用 Cron 表达式
 type PresetCronExpressions struct {
 	Every5Minutes  string `json:"every5Minutes"`  // 每5分钟
@@ -0,0 +1,408 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Description Cron 表达式的中英文描述
+type Description struct {
+	Zh string `json:"zh"`
+	En string `json:"en"`
+}
+
+// 字段取值范围
+type fieldBounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteBounds = fieldBounds{min: 0, max: 59}
+	hourBounds   = fieldBounds{min: 0, max: 23}
+	domBounds    = fieldBounds{min: 1, max: 31}
+	monthBounds  = fieldBounds{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowBounds = fieldBounds{min: 0, max: 6, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var (
+	weekdayZh = []string{"日", "一", "二", "三", "四", "五", "六"}
+	weekdayEn = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+	monthEn   = []string{"", "January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+)
+
+// cronItem 字段中的单个片段：单值 a、范围 a-b、步长 a-b/n、*、*/n
+type cronItem struct {
+	lo, hi, step int
+	all          bool
+}
+
+type cronField []cronItem
+
+func (f cronField) isAny() bool {
+	return len(f) == 1 && f[0].all && f[0].step == 1
+}
+
+// everyN 是否为 */n，返回 n
+func (f cronField) everyN() (int, bool) {
+	if len(f) == 1 && f[0].all && f[0].step > 1 {
+		return f[0].step, true
+	}
+	return 0, false
+}
+
+// singles 是否全部为单值，返回取值列表
+func (f cronField) singles() ([]int, bool) {
+	values := make([]int, 0, len(f))
+	for _, it := range f {
+		if it.all || it.lo != it.hi {
+			return nil, false
+		}
+		values = append(values, it.lo)
+	}
+	return values, true
+}
+
+// Describe 解析标准5位表达式（分 时 日 月 周）或 @descriptor，返回中英文描述
+func Describe(expr string) (*Description, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("Cron 表达式不能为空")
+	}
+	if strings.HasPrefix(expr, "@") {
+		return describeDescriptor(expr)
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("仅支持标准5字段，实际%d字段", len(fields))
+	}
+	minute, err := parseField(fields[0], minuteBounds)
+	if err != nil {
+		return nil, fmt.Errorf("分钟字段: %v", err)
+	}
+	hour, err := parseField(fields[1], hourBounds)
+	if err != nil {
+		return nil, fmt.Errorf("小时字段: %v", err)
+	}
+	dom, err := parseField(fields[2], domBounds)
+	if err != nil {
+		return nil, fmt.Errorf("日期字段: %v", err)
+	}
+	month, err := parseField(fields[3], monthBounds)
+	if err != nil {
+		return nil, fmt.Errorf("月份字段: %v", err)
+	}
+	dow, err := parseField(fields[4], dowBounds)
+	if err != nil {
+		return nil, fmt.Errorf("星期字段: %v", err)
+	}
+
+	timeZh, timeEn, frequent := describeTime(minute, hour)
+	dateZh, dateEn := describeDate(dom, dow, frequent)
+	monthZh, monthEn := describeMonth(month)
+
+	zh := strings.TrimSpace(monthZh + dateZh + " " + timeZh)
+	parts := []string{timeEn}
+	if dateEn != "" {
+		parts = append(parts, dateEn)
+	}
+	if monthEn != "" {
+		parts = append(parts, monthEn)
+	}
+	en := capitalize(strings.Join(parts, " "))
+	return &Description{Zh: zh, En: en}, nil
+}
+
+func describeDescriptor(expr string) (*Description, error) {
+	switch expr {
+	case "@yearly", "@annually":
+		return &Description{Zh: "每年1月1日 00:00", En: "At 00:00 on day 1 in January"}, nil
+	case "@monthly":
+		return &Description{Zh: "每月1日 00:00", En: "At 00:00 on day 1 of the month"}, nil
+	case "@weekly":
+		return &Description{Zh: "每周日 00:00", En: "At 00:00 on Sunday"}, nil
+	case "@daily", "@midnight":
+		return &Description{Zh: "每天 00:00", En: "At 00:00 every day"}, nil
+	case "@hourly":
+		return &Description{Zh: "每小时整点", En: "At the top of every hour"}, nil
+	}
+	if strings.HasPrefix(expr, "@every ") {
+		d := strings.TrimSpace(strings.TrimPrefix(expr, "@every "))
+		return &Description{Zh: "每隔" + d, En: "Every " + d}, nil
+	}
+	return nil, fmt.Errorf("不支持的描述符: %s", expr)
+}
+
+// parseField 解析单个字段
+func parseField(s string, b fieldBounds) (cronField, error) {
+	parts := strings.Split(s, ",")
+	field := make(cronField, 0, len(parts))
+	for _, p := range parts {
+		if p == "" {
+			return nil, fmt.Errorf("存在空片段: %s", s)
+		}
+		it := cronItem{step: 1}
+		rangePart := p
+		if idx := strings.Index(p, "/"); idx >= 0 {
+			step, err := strconv.Atoi(p[idx+1:])
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("无效步长: %s", p)
+			}
+			it.step = step
+			rangePart = p[:idx]
+		}
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			it.all = true
+			it.lo, it.hi = b.min, b.max
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			lo, err := parseValue(bounds[0], b)
+			if err != nil {
+				return nil, err
+			}
+			hi, err := parseValue(bounds[1], b)
+			if err != nil {
+				return nil, err
+			}
+			if lo > hi {
+				return nil, fmt.Errorf("范围起点大于终点: %s", rangePart)
+			}
+			it.lo, it.hi = lo, hi
+		default:
+			v, err := parseValue(rangePart, b)
+			if err != nil {
+				return nil, err
+			}
+			it.lo, it.hi = v, v
+			// a/n 等价于 a-max/n
+			if it.step > 1 {
+				it.hi = b.max
+			}
+		}
+		field = append(field, it)
+	}
+	return field, nil
+}
+
+func parseValue(s string, b fieldBounds) (int, error) {
+	if b.names != nil {
+		if v, ok := b.names[strings.ToLower(s)]; ok {
+			return v, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("无法解析取值: %s", s)
+	}
+	if v < b.min || v > b.max {
+		return 0, fmt.Errorf("取值%d超出范围[%d,%d]", v, b.min, b.max)
+	}
+	return v, nil
+}
+
+// describeTime 描述分、时字段；frequent 表示按分钟/小时频率执行（日期为“每天”时可省略）
+func describeTime(minute, hour cronField) (zh, en string, frequent bool) {
+	minutes, minuteFixed := minute.singles()
+	hours, hourFixed := hour.singles()
+
+	// 固定时刻：列出 HH:MM
+	if minuteFixed && hourFixed && len(minutes)*len(hours) <= 8 {
+		times := make([]string, 0, len(minutes)*len(hours))
+		for _, h := range hours {
+			for _, m := range minutes {
+				times = append(times, fmt.Sprintf("%02d:%02d", h, m))
+			}
+		}
+		return strings.Join(times, "、"), "at " + joinEn(times), false
+	}
+
+	// 分钟部分
+	var minuteZh, minuteEn string
+	if minute.isAny() {
+		minuteZh, minuteEn = "每分钟", "every minute"
+	} else if n, ok := minute.everyN(); ok {
+		minuteZh, minuteEn = fmt.Sprintf("每%d分钟", n), fmt.Sprintf("every %d minutes", n)
+	} else if minuteFixed {
+		strs := make([]string, 0, len(minutes))
+		for _, m := range minutes {
+			strs = append(strs, strconv.Itoa(m))
+		}
+		minuteZh = "第" + strings.Join(strs, "、") + "分"
+		minuteEn = "at minute " + joinEn(strs)
+	} else {
+		minuteZh = itemsZh(minute, nil, "分", "分钟")
+		minuteEn = "at minute " + itemsEn(minute, nil)
+	}
+	_, minuteStep := minute.everyN()
+	minuteIsFreq := minute.isAny() || minuteStep
+	// 第0分：整点
+	minuteTop := minuteFixed && len(minutes) == 1 && minutes[0] == 0
+
+	// 小时部分
+	if hour.isAny() {
+		if minuteIsFreq {
+			return minuteZh, minuteEn, true
+		}
+		if minuteTop {
+			return "每小时整点", "at the top of every hour", true
+		}
+		return "每小时" + minuteZh, minuteEn + " past every hour", true
+	}
+	if n, ok := hour.everyN(); ok {
+		if minuteIsFreq {
+			return fmt.Sprintf("每%d小时内%s", n, minuteZh), fmt.Sprintf("%s, every %d hours", minuteEn, n), true
+		}
+		if minuteTop {
+			return fmt.Sprintf("每%d小时整点", n), fmt.Sprintf("at the top of every %d hours", n), true
+		}
+		return fmt.Sprintf("每%d小时%s", n, minuteZh), fmt.Sprintf("%s past every %d hours", minuteEn, n), true
+	}
+	if len(hour) == 1 && !hour[0].all && hour[0].lo != hour[0].hi && hour[0].step == 1 {
+		hourZh := fmt.Sprintf("%d点至%d点", hour[0].lo, hour[0].hi)
+		hourEn := fmt.Sprintf("between %02d:00 and %02d:59", hour[0].lo, hour[0].hi)
+		if minuteIsFreq {
+			return hourZh + minuteZh, minuteEn + ", " + hourEn, false
+		}
+		if minuteTop {
+			return hourZh + "每小时整点", "at the top of every hour " + hourEn, false
+		}
+		return hourZh + "每小时" + minuteZh, minuteEn + " past every hour " + hourEn, false
+	}
+	hourZh := itemsZh(hour, nil, "点", "小时")
+	hourEn := "hour " + itemsEn(hour, nil)
+	if minuteIsFreq {
+		return hourZh + minuteZh, minuteEn + " during " + hourEn, false
+	}
+	if minuteTop {
+		return hourZh + "整点", "at the top of " + hourEn, false
+	}
+	return hourZh + minuteZh, minuteEn + " past " + hourEn, false
+}
+
+// describeDate 描述日、周字段（两者同时限定时为“或”关系）
+func describeDate(dom, dow cronField, frequent bool) (zh, en string) {
+	domAny, dowAny := dom.isAny(), dow.isAny()
+	switch {
+	case domAny && dowAny:
+		if frequent {
+			return "", ""
+		}
+		return "每天", "every day"
+	case dowAny:
+		return describeDom(dom)
+	case domAny:
+		return describeDow(dow)
+	default:
+		domZh, domEn := describeDom(dom)
+		dowZh, dowEn := describeDow(dow)
+		return domZh + "或" + dowZh, domEn + " or " + dowEn
+	}
+}
+
+func describeDom(dom cronField) (zh, en string) {
+	if n, ok := dom.everyN(); ok {
+		return fmt.Sprintf("每%d天", n), fmt.Sprintf("every %d days", n)
+	}
+	return "每月" + itemsZh(dom, nil, "日", "天"), "on day " + itemsEn(dom, nil) + " of the month"
+}
+
+func describeDow(dow cronField) (zh, en string) {
+	if values, ok := dow.singles(); ok {
+		zhNames := make([]string, 0, len(values))
+		enNames := make([]string, 0, len(values))
+		for _, v := range values {
+			zhNames = append(zhNames, weekdayZh[v])
+			enNames = append(enNames, weekdayEn[v])
+		}
+		return "每周" + strings.Join(zhNames, "、"), "on " + joinEn(enNames)
+	}
+	zhName := func(v int) string { return "周" + weekdayZh[v] }
+	enName := func(v int) string { return weekdayEn[v] }
+	return "每" + itemsZh(dow, zhName, "", "天"), "on " + itemsEn(dow, enName)
+}
+
+func describeMonth(month cronField) (zh, en string) {
+	if month.isAny() {
+		return "", ""
+	}
+	if n, ok := month.everyN(); ok {
+		return fmt.Sprintf("每%d个月的", n), fmt.Sprintf("every %d months", n)
+	}
+	enName := func(v int) string { return monthEn[v] }
+	return itemsZh(month, nil, "月", "个月") + "的", "in " + itemsEn(month, enName)
+}
+
+// itemsZh 将字段片段拼接为中文：单值 v、范围 a至b、步长 a至b每隔n；stepUnit 为步长单位
+func itemsZh(f cronField, name func(int) string, unit, stepUnit string) string {
+	if name == nil {
+		name = func(v int) string { return strconv.Itoa(v) + unit }
+	}
+	parts := make([]string, 0, len(f))
+	for _, it := range f {
+		var s string
+		switch {
+		case it.all:
+			s = fmt.Sprintf("每%d%s", it.step, stepUnit)
+		case it.lo == it.hi:
+			s = name(it.lo)
+		default:
+			s = name(it.lo) + "至" + name(it.hi)
+		}
+		if it.step > 1 && !it.all {
+			s += fmt.Sprintf("每隔%d%s", it.step, stepUnit)
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, "、")
+}
+
+// itemsEn 将字段片段拼接为英文：v、a through b、every n from a through b
+func itemsEn(f cronField, name func(int) string) string {
+	if name == nil {
+		name = strconv.Itoa
+	}
+	parts := make([]string, 0, len(f))
+	for _, it := range f {
+		var s string
+		switch {
+		case it.all:
+			s = fmt.Sprintf("every %d", it.step)
+		case it.lo == it.hi:
+			s = name(it.lo)
+		default:
+			s = name(it.lo) + " through " + name(it.hi)
+		}
+		if it.step > 1 && !it.all {
+			s = fmt.Sprintf("every %d from %s", it.step, s)
+		}
+		parts = append(parts, s)
+	}
+	return joinEn(parts)
+}
+
+func joinEn(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	case 2:
+		return items[0] + " and " + items[1]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,66 @@
+package cron
+
+import "testing"
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		zh   string
+		en   string
+	}{
+		{"每5分钟", "*/5 * * * *", "每5分钟", "Every 5 minutes"},
+		{"每天固定时间", "30 9 * * *", "每天 09:30", "At 09:30 every day"},
+		{"每小时整点", "0 * * * *", "每小时整点", "At the top of every hour"},
+		{"零点", "0 0 * * *", "每天 00:00", "At 00:00 every day"},
+		{"一天最后一分钟", "59 23 * * *", "每天 23:59", "At 23:59 every day"},
+		{"工作日", "0 9 * * 1-5", "每周一至周五 09:00", "At 09:00 on Monday through Friday"},
+		{"周日为0", "0 0 * * 0", "每周日 00:00", "At 00:00 on Sunday"},
+		{"星期名称", "0 0 * * sun", "每周日 00:00", "At 00:00 on Sunday"},
+		{"每月多日", "0 0 1,15 * *", "每月1日、15日 00:00", "At 00:00 on day 1 and 15 of the month"},
+		{"指定月份", "0 0 * 1,7 *", "1月、7月的每天 00:00", "At 00:00 every day in January and July"},
+		{"跨零点小时段", "0 22-23,0-6 * * *", "每天 22点至23点、0点至6点整点", "At the top of hour 22 through 23 and 0 through 6 every day"},
+		{"工作时间内每15分钟", "*/15 9-17 * * 1-5", "每周一至周五 9点至17点每15分钟", "Every 15 minutes, between 09:00 and 17:59 on Monday through Friday"},
+		{"@daily", "@daily", "每天 00:00", "At 00:00 every day"},
+		{"@hourly", "@hourly", "每小时整点", "At the top of every hour"},
+		{"@every", "@every 5m", "每隔5m", "Every 5m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Describe(tt.expr)
+			if err != nil {
+				t.Fatalf("Describe(%q) error: %v", tt.expr, err)
+			}
+			if d.Zh != tt.zh {
+				t.Errorf("Describe(%q).Zh = %q, want %q", tt.expr, d.Zh, tt.zh)
+			}
+			if d.En != tt.en {
+				t.Errorf("Describe(%q).En = %q, want %q", tt.expr, d.En, tt.en)
+			}
+		})
+	}
+}
+
+func TestDescribeInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"空表达式", ""},
+		{"字段数不足", "* * * *"},
+		{"分钟越界", "60 * * * *"},
+		{"小时越界", "0 24 * * *"},
+		{"日期为0", "0 0 0 * *"},
+		{"星期越界", "0 0 * * 7"},
+		{"范围倒置", "5-1 * * * *"},
+		{"步长为0", "*/0 * * * *"},
+		{"未知描述符", "@reboot"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Describe(tt.expr); err == nil {
+				t.Errorf("Describe(%q) expected error", tt.expr)
+			}
+		})
+	}
+}
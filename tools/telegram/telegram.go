@@ -0,0 +1,254 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL Telegram Bot API 默认地址
+const DefaultBaseURL = "https://api.telegram.org"
+
+// Client Telegram Bot API 客户端（baseURL 可配置，便于对接本地桩服务）
+type Client struct {
+	baseURL string
+	cli     *http.Client
+}
+
+// NewClient 创建客户端，baseURL 为空时使用官方地址
+func NewClient(baseURL string, timeout time.Duration) *Client {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &Client{
+		baseURL: baseURL,
+		cli:     &http.Client{Timeout: timeout},
+	}
+}
+
+// BaseURL 当前使用的 API 地址
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// InputFile 待上传的文件
+type InputFile struct {
+	FileName string
+	Content  []byte
+}
+
+// InputMedia sendMediaGroup 中的单个媒体
+type InputMedia struct {
+	Type    string // photo / video
+	File    InputFile
+	Caption string
+}
+
+// Message Telegram 返回的消息（仅保留需要的字段）
+type Message struct {
+	MessageID int64 `json:"message_id"`
+}
+
+// ResponseParameters 错误响应附带参数
+type ResponseParameters struct {
+	RetryAfter      int   `json:"retry_after,omitempty"`
+	MigrateToChatID int64 `json:"migrate_to_chat_id,omitempty"`
+}
+
+// APIError Bot API 返回 ok=false 时的错误
+type APIError struct {
+	Method      string
+	ErrorCode   int
+	Description string
+	RetryAfter  int
+}
+
+func (e *APIError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("telegram %s 失败: [%d] %s (retry_after=%ds)", e.Method, e.ErrorCode, e.Description, e.RetryAfter)
+	}
+	return fmt.Sprintf("telegram %s 失败: [%d] %s", e.Method, e.ErrorCode, e.Description)
+}
+
+// Permanent 是否为不可重试的错误：4xx（429 限流除外）表示请求本身有误（chat不存在、机器人被踢出、无权限等）
+func (e *APIError) Permanent() bool {
+	return e.ErrorCode >= 400 && e.ErrorCode < 500 && e.ErrorCode != http.StatusTooManyRequests
+}
+
+type apiResponse struct {
+	Ok          bool                `json:"ok"`
+	Result      json.RawMessage     `json:"result"`
+	ErrorCode   int                 `json:"error_code"`
+	Description string              `json:"description"`
+	Parameters  *ResponseParameters `json:"parameters"`
+}
+
+// SendMessage 发送文本消息
+func (c *Client) SendMessage(ctx context.Context, token string, chatID int64, text string) (*Message, error) {
+	params := map[string]string{
+		"chat_id": strconv.FormatInt(chatID, 10),
+		"text":    text,
+	}
+	var msg Message
+	if err := c.call(ctx, token, "sendMessage", params, nil, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// SendPhoto 发送单张图片
+func (c *Client) SendPhoto(ctx context.Context, token string, chatID int64, photo InputFile, caption string) (*Message, error) {
+	return c.sendFile(ctx, token, "sendPhoto", "photo", chatID, photo, caption)
+}
+
+// SendVideo 发送单个视频
+func (c *Client) SendVideo(ctx context.Context, token string, chatID int64, video InputFile, caption string) (*Message, error) {
+	return c.sendFile(ctx, token, "sendVideo", "video", chatID, video, caption)
+}
+
+// SendMediaGroup 发送媒体组（2-10 个），返回每条消息
+func (c *Client) SendMediaGroup(ctx context.Context, token string, chatID int64, medias []InputMedia) ([]Message, error) {
+	if len(medias) < 2 || len(medias) > 10 {
+		return nil, fmt.Errorf("媒体组数量必须在2-10之间，实际%d", len(medias))
+	}
+	type mediaItem struct {
+		Type    string `json:"type"`
+		Media   string `json:"media"`
+		Caption string `json:"caption,omitempty"`
+	}
+	items := make([]mediaItem, 0, len(medias))
+	files := make(map[string]InputFile, len(medias))
+	for i, m := range medias {
+		field := fmt.Sprintf("file%d", i)
+		items = append(items, mediaItem{Type: m.Type, Media: "attach://" + field, Caption: m.Caption})
+		files[field] = m.File
+	}
+	mediaJSON, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]string{
+		"chat_id": strconv.FormatInt(chatID, 10),
+		"media":   string(mediaJSON),
+	}
+	var msgs []Message
+	if err := c.call(ctx, token, "sendMediaGroup", params, files, &msgs); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+func (c *Client) sendFile(ctx context.Context, token, method, field string, chatID int64, file InputFile, caption string) (*Message, error) {
+	params := map[string]string{
+		"chat_id": strconv.FormatInt(chatID, 10),
+	}
+	if caption != "" {
+		params["caption"] = caption
+	}
+	var msg Message
+	if err := c.call(ctx, token, method, params, map[string]InputFile{field: file}, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// call 调用 Bot API；有文件时使用 multipart，否则使用 JSON。请求地址中包含token，返回的错误不得携带地址
+func (c *Client) call(ctx context.Context, token, method string, params map[string]string, files map[string]InputFile, result interface{}) error {
+	if strings.TrimSpace(token) == "" {
+		return fmt.Errorf("telegram %s 失败: 机器人token为空", method)
+	}
+	endpoint := fmt.Sprintf("%s/bot%s/%s", c.baseURL, token, method)
+
+	var body io.Reader
+	var contentType string
+	if len(files) == 0 {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+		contentType = "application/json"
+	} else {
+		buf := &bytes.Buffer{}
+		w := multipart.NewWriter(buf)
+		for k, v := range params {
+			if err := w.WriteField(k, v); err != nil {
+				return err
+			}
+		}
+		for field, f := range files {
+			part, err := w.CreateFormFile(field, f.FileName)
+			if err != nil {
+				return err
+			}
+			if _, err := part.Write(f.Content); err != nil {
+				return err
+			}
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		body = buf
+		contentType = w.FormDataContentType()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("telegram %s 构建请求失败: %w", method, stripURL(err, token))
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.cli.Do(req)
+	if err != nil {
+		return fmt.Errorf("telegram %s 请求失败: %w", method, stripURL(err, token))
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("telegram %s 读取响应失败: %w", method, err)
+	}
+
+	var ar apiResponse
+	if err := json.Unmarshal(raw, &ar); err != nil {
+		return fmt.Errorf("telegram %s 响应解析失败(HTTP %d): %w", method, resp.StatusCode, err)
+	}
+	if !ar.Ok {
+		apiErr := &APIError{Method: method, ErrorCode: ar.ErrorCode, Description: ar.Description}
+		if ar.Parameters != nil {
+			apiErr.RetryAfter = ar.Parameters.RetryAfter
+		}
+		return apiErr
+	}
+	if result != nil && len(ar.Result) > 0 {
+		if err := json.Unmarshal(ar.Result, result); err != nil {
+			return fmt.Errorf("telegram %s 结果解析失败: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// stripURL 去掉 *url.Error 中带token的请求地址，仅保留底层错误；兜底将残留的token替换为占位符
+func stripURL(err error, token string) error {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		err = ue.Err
+	}
+	if token != "" && strings.Contains(err.Error(), token) {
+		return errors.New(strings.ReplaceAll(err.Error(), token, "<token>"))
+	}
+	return err
+}
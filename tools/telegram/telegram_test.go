@@ -0,0 +1,133 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testToken = "123456:secret"
+
+// newStub 启动本地桩服务，按方法名分发请求
+func newStub(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, method string)) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefix := "/bot" + testToken + "/"
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			t.Errorf("请求路径 = %s，期望前缀 %s", r.URL.Path, prefix)
+			http.NotFound(w, r)
+			return
+		}
+		handler(w, r, strings.TrimPrefix(r.URL.Path, prefix))
+	}))
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL+"/", 5*time.Second)
+}
+
+func TestSendMessage(t *testing.T) {
+	c := newStub(t, func(w http.ResponseWriter, r *http.Request, method string) {
+		if method != "sendMessage" {
+			t.Errorf("method = %s", method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %s", ct)
+		}
+		var params map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Fatalf("解析请求失败: %v", err)
+		}
+		if params["chat_id"] != "-1001" || params["text"] != "你好" {
+			t.Errorf("params = %v", params)
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":42}}`))
+	})
+
+	msg, err := c.SendMessage(context.Background(), testToken, -1001, "你好")
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if msg.MessageID != 42 {
+		t.Errorf("MessageID = %d, want 42", msg.MessageID)
+	}
+}
+
+func TestSendMediaGroup(t *testing.T) {
+	c := newStub(t, func(w http.ResponseWriter, r *http.Request, method string) {
+		if method != "sendMediaGroup" {
+			t.Errorf("method = %s", method)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("解析multipart失败: %v", err)
+		}
+		if got := r.FormValue("chat_id"); got != "-1001" {
+			t.Errorf("chat_id = %s", got)
+		}
+		var media []struct {
+			Type    string `json:"type"`
+			Media   string `json:"media"`
+			Caption string `json:"caption"`
+		}
+		if err := json.Unmarshal([]byte(r.FormValue("media")), &media); err != nil {
+			t.Fatalf("解析media失败: %v", err)
+		}
+		if len(media) != 2 || media[0].Media != "attach://file0" || media[0].Caption != "说明" || media[1].Type != "video" {
+			t.Errorf("media = %+v", media)
+		}
+		for _, field := range []string{"file0", "file1"} {
+			if _, _, err := r.FormFile(field); err != nil {
+				t.Errorf("缺少文件 %s: %v", field, err)
+			}
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":[{"message_id":1},{"message_id":2}]}`))
+	})
+
+	msgs, err := c.SendMediaGroup(context.Background(), testToken, -1001, []InputMedia{
+		{Type: "photo", File: InputFile{FileName: "a.jpg", Content: []byte("a")}, Caption: "说明"},
+		{Type: "video", File: InputFile{FileName: "b.mp4", Content: []byte("b")}},
+	})
+	if err != nil {
+		t.Fatalf("SendMediaGroup() error = %v", err)
+	}
+	if len(msgs) != 2 || msgs[0].MessageID != 1 || msgs[1].MessageID != 2 {
+		t.Errorf("msgs = %+v", msgs)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		code       int
+		retryAfter int
+		permanent  bool
+	}{
+		{"429限流", http.StatusTooManyRequests, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 7","parameters":{"retry_after":7}}`, 429, 7, false},
+		{"403被踢出", http.StatusForbidden, `{"ok":false,"error_code":403,"description":"Forbidden: bot was kicked"}`, 403, 0, true},
+		{"500服务端错误", http.StatusInternalServerError, `{"ok":false,"error_code":500,"description":"Internal Server Error"}`, 500, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newStub(t, func(w http.ResponseWriter, r *http.Request, method string) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			_, err := c.SendMessage(context.Background(), testToken, -1001, "hi")
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want *APIError", err)
+			}
+			if apiErr.ErrorCode != tt.code || apiErr.RetryAfter != tt.retryAfter || apiErr.Permanent() != tt.permanent {
+				t.Errorf("APIError = %+v, Permanent() = %v", apiErr, apiErr.Permanent())
+			}
+			if strings.Contains(err.Error(), testToken) {
+				t.Errorf("错误信息包含token: %v", err)
+			}
+		})
+	}
+}
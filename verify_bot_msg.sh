@@ -10,12 +10,11 @@ SERVICE_PID=$!
 sleep 3
 
 echo "2. 测试立即执行任务..."
-curl -X POST http://localhost:8080/api/task/test \
+TASK_ID=${TASK_ID:-1}
+curl -X POST http://localhost:8080/api/task/trigger \
   -H "Content-Type: application/json" \
-  -d '{
-    "task_type": "bot_msg",
-    "payload": {"msg_type": "test", "content": "测试bot_msg任务"}
-  }'
+  -H "Token: ${TOKEN}" \
+  -d "{\"id\": ${TASK_ID}}"
 
 echo "3. 测试cron定时任务..."
 curl -X POST http://localhost:8080/api/task/create \